@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"golang.org/x/text/cases"
@@ -79,21 +80,51 @@ func (m *uiModel) getArea() tea.Cmd {
 			if err := json.Unmarshal(body, &res); err != nil {
 				bodyStr = err.Error()
 			} else {
-				bodyStr = "\n"
+				bodyStr = fmt.Sprintf("\nLocation (%d, %d)\n", res.PositionX, res.PositionY)
 				if len(res.Characters) > 0 {
 					bodyStr += "Characters\n"
 					for _, value := range res.Characters {
+						health := fmt.Sprintf("(hp %d/%d)", value.HP, value.MaxHP)
 						if value.ActionName == "IDLE" || value.ActionTarget == "" {
 							bodyStr += fmt.Sprintf(
-								"\t%v is idle\n",
+								"\t%v is idle %v\n",
 								value.CharacterName,
+								health,
+							)
+						} else if value.ActionName == "CRAFTING" {
+							bodyStr += fmt.Sprintf(
+								"\t%v is crafting %v %v\n",
+								value.CharacterName,
+								caser.String(value.ActionTarget),
+								health,
+							)
+						} else if value.ActionName == "TRAVELING" {
+							bodyStr += fmt.Sprintf(
+								"\t%v is traveling to %v %v\n",
+								value.CharacterName,
+								value.ActionTarget,
+								health,
+							)
+						} else if value.ActionName == "MELEE" || value.ActionName == "ARCHERY" {
+							bodyStr += fmt.Sprintf(
+								"\t%v is fighting %v with %v %v\n",
+								value.CharacterName,
+								caser.String(value.ActionTarget),
+								strings.ToLower(value.ActionName),
+								health,
 							)
 						} else {
+							progress := ""
+							if value.IntervalTicks > 1 {
+								progress = fmt.Sprintf(" [next drop %d/%d ticks]", value.ActionTicks, value.IntervalTicks)
+							}
 							bodyStr += fmt.Sprintf(
-								"\t%v is %v at %v\n",
+								"\t%v is %v at %v%v %v\n",
 								value.CharacterName,
 								caser.String(value.ActionName),
 								caser.String(value.ActionTarget),
+								progress,
+								health,
 							)
 						}
 					}
@@ -102,8 +133,46 @@ func (m *uiModel) getArea() tea.Cmd {
 				if len(res.ResourceNodes) > 0 {
 					bodyStr += "Resources\n"
 					for _, value := range res.ResourceNodes {
-						resource := caser.String(value)
-						bodyStr += fmt.Sprintf("\t%v\n", resource)
+						resource := caser.String(value.Name)
+						if value.Remaining == nil {
+							bodyStr += fmt.Sprintf("\t%v\n", resource)
+						} else if *value.Remaining == 0 {
+							bodyStr += fmt.Sprintf(
+								"\t%v (depleted, respawns in %d ticks)\n",
+								resource,
+								value.RespawnTicksLeft,
+							)
+						} else {
+							bodyStr += fmt.Sprintf(
+								"\t%v (%d/%d remaining)\n",
+								resource,
+								*value.Remaining,
+								*value.Amount,
+							)
+						}
+					}
+				}
+
+				if len(res.Creatures) > 0 {
+					bodyStr += "Creatures\n"
+					for _, value := range res.Creatures {
+						creature := caser.String(value.Name)
+						if value.HP == 0 {
+							bodyStr += fmt.Sprintf(
+								"\t%v (dead, respawns in %d ticks)\n",
+								creature,
+								value.RespawnTicksLeft,
+							)
+						} else {
+							bodyStr += fmt.Sprintf("\t%v (hp %d/%d)\n", creature, value.HP, value.MaxHP)
+						}
+					}
+				}
+
+				if len(res.GroundItems) > 0 {
+					bodyStr += "On the ground\n"
+					for _, value := range res.GroundItems {
+						bodyStr += fmt.Sprintf("\t%v: %d\n", caser.String(value.Name), value.Quantity)
 					}
 				}
 			}
@@ -142,25 +211,12 @@ func (m *uiModel) getInventory() tea.Cmd {
 		resColor := Red
 		if res.StatusCode == 200 {
 			resColor = Green
-			caser := cases.Title(language.English)
 			var res inventoryResponse
 			if err := json.Unmarshal(body, &res); err != nil {
 				bodyStr = err.Error()
 			} else {
-				bodyStr = "\n"
-				if len(res.Items) > 0 {
-					bodyStr += "Inventory\n"
-					for name, item := range res.Items {
-						bodyStr += fmt.Sprintf(
-							"\t%v: %v (weight: %d each, total: %d)\n",
-							caser.String(name),
-							item.Quantity,
-							item.Weight,
-							item.TotalWeight,
-						)
-					}
-				}
-				bodyStr += fmt.Sprintf("\nWeight: %d/%d", res.Weight, res.Capacity)
+				bodyStr = formatInventory("Inventory", res)
+				m.inventory = &res
 			}
 		} else {
 			resColor = Red
@@ -171,6 +227,81 @@ func (m *uiModel) getInventory() tea.Cmd {
 	}
 }
 
+// refreshInventory fetches a character's inventory again without showing
+// it, for when it changed some way the pushed deltas don't cover.
+func (m *uiModel) refreshInventory(characterName string) tea.Cmd {
+	return func() tea.Msg {
+		res, err := m.makeAuthenticatedRequest("GET", fmt.Sprintf("/inventory/%v", characterName), nil)
+		if err != nil {
+			return inventoryRefreshed{characterName: characterName}
+		}
+		defer res.Body.Close()
+
+		var inventory inventoryResponse
+		if res.StatusCode != 200 || json.NewDecoder(res.Body).Decode(&inventory) != nil {
+			return inventoryRefreshed{characterName: characterName}
+		}
+		return inventoryRefreshed{characterName: characterName, inventory: &inventory}
+	}
+}
+
+func formatInventory(title string, res inventoryResponse) string {
+	caser := cases.Title(language.English)
+	bodyStr := "\n"
+	if len(res.Items) > 0 {
+		bodyStr += title + "\n"
+		for name, item := range res.Items {
+			bodyStr += fmt.Sprintf(
+				"\t%v: %v (weight: %d each, total: %d)\n",
+				caser.String(name),
+				item.Quantity,
+				item.Weight,
+				item.TotalWeight,
+			)
+			for _, durability := range item.Durability {
+				bodyStr += fmt.Sprintf(
+					"\t\tdurability: %d/%d\n",
+					durability,
+					item.MaxDurability,
+				)
+			}
+		}
+	}
+	bodyStr += fmt.Sprintf("\nWeight: %d/%d", res.Weight, res.Capacity)
+	return bodyStr
+}
+
+func (m *uiModel) getStash() tea.Cmd {
+	return func() tea.Msg {
+		res, err := m.makeAuthenticatedRequest("GET", "/stash", nil)
+		if err != nil {
+			return m.handleAPIError(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		bodyStr := ""
+		resColor := Red
+		if res.StatusCode == 200 {
+			resColor = Green
+			var res inventoryResponse
+			if err := json.Unmarshal(body, &res); err != nil {
+				bodyStr = err.Error()
+			} else {
+				bodyStr = formatInventory("Stash", res)
+			}
+		} else {
+			bodyStr = "Stash get failed"
+		}
+
+		return apiResMsg{resColor, bodyStr}
+	}
+}
+
 func (m *uiModel) setIdle() tea.Cmd {
 	return func() tea.Msg {
 		if m.selectedChar == "" {
@@ -334,6 +465,125 @@ func (m *uiModel) dropItemAll(itemName string) tea.Cmd {
 	}
 }
 
+func (m *uiModel) pickupItem(itemName string, quantity *int) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedChar == "" {
+			return apiResMsg{Red, "No character selected. Use 'sel <character>' first"}
+		}
+
+		data := map[string]interface{}{
+			"character_name": m.selectedChar,
+			"item_name":      itemName,
+		}
+		if quantity != nil {
+			data["quantity"] = *quantity
+		} else {
+			data["pickup_all"] = true
+		}
+
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		res, err := m.makeAuthenticatedRequest("POST", "/inventory/pickup", jsonData)
+		if err != nil {
+			return m.handleAPIError(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		bodyStr := ""
+		resColor := Red
+		if res.StatusCode == 200 {
+			resColor = Green
+			var response map[string]interface{}
+			if err := json.Unmarshal(body, &response); err == nil {
+				if message, ok := response["message"].(string); ok {
+					bodyStr = message
+				} else {
+					bodyStr = "Item picked up successfully"
+				}
+			} else {
+				bodyStr = "Item picked up successfully"
+			}
+		} else {
+			var errResp ErrorResponse
+			if err := json.Unmarshal(body, &errResp); err != nil {
+				bodyStr = "Failed to parse error response"
+			} else {
+				bodyStr = errResp.Error
+			}
+		}
+
+		return apiResMsg{resColor, bodyStr}
+	}
+}
+
+// transferStashItem deposits items into or withdraws them from the stash,
+// depending on action. A nil quantity moves every item of that type.
+func (m *uiModel) transferStashItem(action string, itemName string, quantity *int) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedChar == "" {
+			return apiResMsg{Red, "No character selected. Use 'sel <character>' first"}
+		}
+
+		data := map[string]interface{}{
+			"character_name": m.selectedChar,
+			"item_name":      itemName,
+		}
+		if quantity != nil {
+			data["quantity"] = *quantity
+		} else {
+			data["all"] = true
+		}
+
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		res, err := m.makeAuthenticatedRequest("POST", "/stash/"+action, jsonData)
+		if err != nil {
+			return m.handleAPIError(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		bodyStr := ""
+		resColor := Red
+		if res.StatusCode == 200 {
+			resColor = Green
+			var response map[string]interface{}
+			if err := json.Unmarshal(body, &response); err == nil {
+				if message, ok := response["message"].(string); ok {
+					bodyStr = message
+				}
+			}
+			if bodyStr == "" {
+				bodyStr = "Stash updated"
+			}
+		} else {
+			var errResp ErrorResponse
+			if err := json.Unmarshal(body, &errResp); err != nil {
+				bodyStr = "Failed to parse error response"
+			} else {
+				bodyStr = errResp.Error
+			}
+		}
+
+		return apiResMsg{resColor, bodyStr}
+	}
+}
+
 func (m *uiModel) setActionWithAmount(target string, amount *int) tea.Cmd {
 	return func() tea.Msg {
 		data := map[string]interface{}{
@@ -378,6 +628,16 @@ func (m *uiModel) setActionWithAmount(target string, amount *int) tea.Cmd {
 				caser.String(actionName),
 				caser.String(target),
 			)
+			if tool, ok := response["tool"].(string); ok {
+				bodyStr += fmt.Sprintf(
+					" with %v (x%v yield)",
+					caser.String(tool),
+					response["yield"],
+				)
+			}
+			if interval, ok := response["interval_ticks"].(float64); ok && interval > 1 {
+				bodyStr += fmt.Sprintf(", one drop every %v ticks", interval)
+			}
 
 		} else {
 			resColor = Red
@@ -393,6 +653,288 @@ func (m *uiModel) setActionWithAmount(target string, amount *int) tea.Cmd {
 	}
 }
 
+func (m *uiModel) moveCharacter(destination string) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedChar == "" {
+			return apiResMsg{Red, "No character selected. Use 'sel <character>' first"}
+		}
+
+		data := map[string]interface{}{}
+		if coords := strings.Split(destination, ","); len(coords) == 2 {
+			x, errX := strconv.Atoi(strings.TrimSpace(coords[0]))
+			y, errY := strconv.Atoi(strings.TrimSpace(coords[1]))
+			if errX != nil || errY != nil {
+				return apiResMsg{Red, "Invalid coordinates. Use 'move <x>,<y>'"}
+			}
+			data["position_x"] = x
+			data["position_y"] = y
+		} else {
+			data["direction"] = destination
+		}
+
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		res, err := m.makeAuthenticatedRequest("POST", fmt.Sprintf("/characters/%s/move", m.selectedChar), jsonData)
+		if err != nil {
+			return m.handleAPIError(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		var bodyStr string
+		var resColor Color
+		if res.StatusCode == 201 {
+			resColor = Green
+			var response struct {
+				DestinationX int32 `json:"destination_x"`
+				DestinationY int32 `json:"destination_y"`
+				Ticks        int32 `json:"ticks"`
+			}
+			if err := json.Unmarshal(body, &response); err != nil {
+				bodyStr = fmt.Sprintf("%v started traveling", m.selectedChar)
+			} else {
+				bodyStr = fmt.Sprintf(
+					"%v is traveling to (%d, %d) and will arrive in %d ticks",
+					m.selectedChar,
+					response.DestinationX,
+					response.DestinationY,
+					response.Ticks,
+				)
+			}
+		} else {
+			resColor = Red
+			var errResp ErrorResponse
+			if err := json.Unmarshal(body, &errResp); err != nil {
+				bodyStr = "Failed to parse error response"
+			} else {
+				bodyStr = errResp.Error
+			}
+		}
+
+		return apiResMsg{resColor, bodyStr}
+	}
+}
+
+func (m *uiModel) attack(target string, style string) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedChar == "" {
+			return apiResMsg{Red, "No character selected"}
+		}
+
+		data := map[string]string{
+			"target": target,
+			"style":  style,
+		}
+
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		res, err := m.makeAuthenticatedRequest("POST", fmt.Sprintf("/characters/%v/attack", m.selectedChar), jsonData)
+		if err != nil {
+			return m.handleAPIError(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		bodyStr := ""
+		resColor := Red
+		if res.StatusCode == 201 {
+			resColor = Green
+			caser := cases.Title(language.English)
+			var response map[string]interface{}
+			if err := json.Unmarshal(body, &response); err != nil {
+				bodyStr = err.Error()
+			} else {
+				bodyStr = fmt.Sprintf(
+					"%v is fighting %v with %v (hp %v/%v)",
+					caser.String(m.selectedChar),
+					caser.String(fmt.Sprint(response["target"])),
+					strings.ToLower(fmt.Sprint(response["action_name"])),
+					response["hp"],
+					response["max_hp"],
+				)
+			}
+		} else {
+			var errResp ErrorResponse
+			if err := json.Unmarshal(body, &errResp); err != nil {
+				bodyStr = "Failed to parse error response"
+			} else {
+				bodyStr = errResp.Error
+			}
+		}
+
+		return apiResMsg{resColor, bodyStr}
+	}
+}
+
+func (m *uiModel) getSkills() tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedChar == "" {
+			return apiResMsg{Red, "No character selected"}
+		}
+
+		res, err := m.makeAuthenticatedRequest("GET", fmt.Sprintf("/characters/%v/skills", m.selectedChar), nil)
+		if err != nil {
+			return m.handleAPIError(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		bodyStr := ""
+		resColor := Red
+		if res.StatusCode == 200 {
+			resColor = Green
+			caser := cases.Title(language.English)
+			var skills []skillData
+			if err := json.Unmarshal(body, &skills); err != nil {
+				bodyStr = err.Error()
+			} else if len(skills) == 0 {
+				bodyStr = fmt.Sprintf("%v has no skill experience yet", caser.String(m.selectedChar))
+			} else {
+				bodyStr = "\nSkills\n"
+				for _, skill := range skills {
+					bodyStr += fmt.Sprintf(
+						"\t%v: level %d (%d/%d xp)\n",
+						caser.String(skill.Name),
+						skill.Level,
+						skill.Experience,
+						skill.NextLevelExperience,
+					)
+				}
+			}
+		} else {
+			var errResp ErrorResponse
+			if err := json.Unmarshal(body, &errResp); err != nil {
+				bodyStr = "Failed to parse error response"
+			} else {
+				bodyStr = errResp.Error
+			}
+		}
+
+		return apiResMsg{resColor, bodyStr}
+	}
+}
+
+func (m *uiModel) getRecipes() tea.Cmd {
+	return func() tea.Msg {
+		res, err := m.makeAuthenticatedRequest("GET", "/recipes", nil)
+		if err != nil {
+			return m.handleAPIError(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		bodyStr := ""
+		resColor := Red
+		if res.StatusCode == 200 {
+			resColor = Green
+			caser := cases.Title(language.English)
+			var recipes []recipeData
+			if err := json.Unmarshal(body, &recipes); err != nil {
+				bodyStr = err.Error()
+			} else {
+				bodyStr = "\nRecipes\n"
+				for _, recipe := range recipes {
+					ingredients := make([]string, 0, len(recipe.Ingredients))
+					for _, ingredient := range recipe.Ingredients {
+						ingredients = append(ingredients, fmt.Sprintf("%d %v", ingredient.Quantity, caser.String(ingredient.Name)))
+					}
+					bodyStr += fmt.Sprintf(
+						"\t%v: %d %v from %v\n",
+						caser.String(recipe.Name),
+						recipe.Quantity,
+						caser.String(recipe.ItemName),
+						strings.Join(ingredients, ", "),
+					)
+				}
+			}
+		} else {
+			var errResp ErrorResponse
+			if err := json.Unmarshal(body, &errResp); err != nil {
+				bodyStr = "Failed to parse error response"
+			} else {
+				bodyStr = errResp.Error
+			}
+		}
+
+		return apiResMsg{resColor, bodyStr}
+	}
+}
+
+func (m *uiModel) craft(recipe string, amount *int) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedChar == "" {
+			return apiResMsg{Red, "No character selected. Use 'sel <character>' first"}
+		}
+
+		data := map[string]interface{}{
+			"recipe": recipe,
+		}
+
+		if amount != nil {
+			data["amount"] = *amount
+		}
+
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		res, err := m.makeAuthenticatedRequest("POST", fmt.Sprintf("/characters/%s/craft", m.selectedChar), jsonData)
+		if err != nil {
+			return m.handleAPIError(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		bodyStr := ""
+		resColor := Red
+		if res.StatusCode == 201 {
+			caser := cases.Title(language.English)
+			resColor = Green
+			bodyStr = fmt.Sprintf(
+				"%v started crafting %v",
+				caser.String(m.selectedChar),
+				caser.String(recipe),
+			)
+		} else {
+			var errResp ErrorResponse
+			if err := json.Unmarshal(body, &errResp); err != nil {
+				bodyStr = "Failed to parse error response"
+			} else {
+				bodyStr = errResp.Error
+			}
+		}
+
+		return apiResMsg{resColor, bodyStr}
+	}
+}
+
 func (m *uiModel) selectCharacter(charName string) tea.Cmd {
 	return func() tea.Msg {
 		res, err := m.makeAuthenticatedRequest("GET", fmt.Sprintf("/characters/%s/select", charName), nil)
@@ -411,6 +953,8 @@ func (m *uiModel) selectCharacter(charName string) tea.Cmd {
 		if res.StatusCode == 200 {
 			resColor = Green
 			m.selectedChar = charName
+			m.inventory = nil
+			m.progress = nil
 			bodyStr = fmt.Sprintf("Selected %v", charName)
 		} else {
 			resColor = Red
@@ -425,3 +969,128 @@ func (m *uiModel) selectCharacter(charName string) tea.Cmd {
 		return apiResMsg{resColor, bodyStr}
 	}
 }
+
+func (m *uiModel) getTrade() tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedChar == "" {
+			return apiResMsg{Red, "No character selected"}
+		}
+
+		res, err := m.makeAuthenticatedRequest("GET", fmt.Sprintf("/characters/%v/trade", m.selectedChar), nil)
+		if err != nil {
+			return m.handleAPIError(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		bodyStr := ""
+		resColor := Red
+		if res.StatusCode == 200 {
+			resColor = Green
+			caser := cases.Title(language.English)
+			var trade tradeData
+			if err := json.Unmarshal(body, &trade); err != nil {
+				bodyStr = err.Error()
+			} else {
+				bodyStr = fmt.Sprintf("\nTrade between %v and %v\n", caser.String(trade.Initiator), caser.String(trade.Recipient))
+				confirmed := map[string]bool{
+					trade.Initiator: trade.InitiatorConfirmed,
+					trade.Recipient: trade.RecipientConfirmed,
+				}
+				for _, name := range []string{trade.Initiator, trade.Recipient} {
+					status := "not confirmed"
+					if confirmed[name] {
+						status = "confirmed"
+					}
+					bodyStr += fmt.Sprintf("\t%v offers (%v):\n", caser.String(name), status)
+					if len(trade.Offers[name]) == 0 {
+						bodyStr += "\t\tnothing\n"
+					}
+					for _, offer := range trade.Offers[name] {
+						bodyStr += fmt.Sprintf("\t\t%v: %d\n", caser.String(offer.Name), offer.Quantity)
+					}
+				}
+			}
+		} else {
+			var errResp ErrorResponse
+			if err := json.Unmarshal(body, &errResp); err != nil {
+				bodyStr = "Failed to parse error response"
+			} else {
+				bodyStr = errResp.Error
+			}
+		}
+
+		return apiResMsg{resColor, bodyStr}
+	}
+}
+
+func (m *uiModel) proposeTrade(target string) tea.Cmd {
+	return m.sendTradeRequest("", map[string]interface{}{
+		"target": target,
+	})
+}
+
+func (m *uiModel) addTradeItem(itemName string, quantity int) tea.Cmd {
+	return m.sendTradeRequest("/items", map[string]interface{}{
+		"item_name": itemName,
+		"quantity":  quantity,
+	})
+}
+
+func (m *uiModel) updateTrade(action string) tea.Cmd {
+	return m.sendTradeRequest("/"+action, map[string]interface{}{})
+}
+
+func (m *uiModel) sendTradeRequest(path string, data map[string]interface{}) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedChar == "" {
+			return apiResMsg{Red, "No character selected"}
+		}
+
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		res, err := m.makeAuthenticatedRequest("POST", fmt.Sprintf("/characters/%v/trade%v", m.selectedChar, path), jsonData)
+		if err != nil {
+			return m.handleAPIError(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return apiResMsg{Red, err.Error()}
+		}
+
+		bodyStr := ""
+		resColor := Red
+		if res.StatusCode == 200 || res.StatusCode == 201 {
+			resColor = Green
+			var response map[string]interface{}
+			if err := json.Unmarshal(body, &response); err == nil {
+				if message, ok := response["message"].(string); ok {
+					bodyStr = message
+				} else if recipient, ok := response["recipient"].(string); ok {
+					bodyStr = fmt.Sprintf("Trade proposed to %v", cases.Title(language.English).String(recipient))
+				}
+			}
+			if bodyStr == "" {
+				bodyStr = "Trade updated"
+			}
+		} else {
+			var errResp ErrorResponse
+			if err := json.Unmarshal(body, &errResp); err != nil {
+				bodyStr = "Failed to parse error response"
+			} else {
+				bodyStr = errResp.Error
+			}
+		}
+
+		return apiResMsg{resColor, bodyStr}
+	}
+}
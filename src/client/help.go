@@ -12,9 +12,19 @@ func (m *uiModel) showHelp() tea.Cmd {
 			"  sel <character>     - Select a character\n" +
 			"  act <target>        - Set character action on target\n" +
 			"  idle                - Set character to idle\n" +
+			"  move <dir|x,y>      - Travel to another grid cell\n" +
+			"  attack <creature>   - Fight a creature at your location\n" +
+			"  craft <recipe>      - Craft a recipe from inventory items\n" +
+			"  recipes             - List craftable recipes\n" +
 			"  sense               - Sense current area\n" +
 			"  inv                 - View character inventory\n" +
-			"  drop <item> <qty>   - Drop items from inventory\n" +
+			"  skills              - View character skill levels\n" +
+			"  drop <item> <qty>   - Drop items on the ground\n" +
+			"  pickup <item> [qty] - Pick up items from the ground\n" +
+			"  stash               - View your account-wide stash\n" +
+			"  deposit <item> [n]  - Move items into your stash\n" +
+			"  withdraw <item> [n] - Move items out of your stash\n" +
+			"  trade [subcommand]  - Trade items with a nearby character\n" +
 			"  say <message>       - Send chat message\n" +
 			"  newchar <name>      - Create new character\n" +
 			"  echo <text>         - Echo text\n" +
@@ -34,7 +44,7 @@ func (m *uiModel) showCommandHelp(command string) tea.Cmd {
 			helpText = "\nSelect Character:\n" +
 				"Usage: sel <character_name>\n" +
 				"Selects a character for other commands to operate on.\n" +
-				"You must select a character before using act, sense, inv, drop, say, or idle."
+				"You must select a character before using act, attack, craft, move, sense, inv, skills, drop, pickup, deposit, withdraw, trade, say, or idle."
 		case "act":
 			helpText = "\nSet Action:\n" +
 				"Usage: act <target> [amount]\n" +
@@ -47,6 +57,34 @@ func (m *uiModel) showCommandHelp(command string) tea.Cmd {
 				"Usage: idle\n" +
 				"Sets your selected character to idle state, stopping any current action.\n" +
 				"Useful for manually stopping resource gathering or other activities."
+		case "move":
+			helpText = "\nMove Character:\n" +
+				"Usage: move <north|south|east|west|x,y>\n" +
+				"Sends your selected character to another grid cell.\n" +
+				"Directions move one cell; coordinates travel straight to that cell.\n" +
+				"Travel takes several ticks per cell and the character must be idle to start.\n" +
+				"Examples:\n" +
+				"  move north     - travels one cell north\n" +
+				"  move 2,-1      - travels to (2, -1)"
+		case "attack":
+			helpText = "\nAttack Creature:\n" +
+				"Usage: attack <creature> [melee|archery]\n" +
+				"Sets your selected character to fight a creature at your current location.\n" +
+				"Melee hits harder, archery deals less damage but takes half as much in return.\n" +
+				"Defeating a creature grants its loot and experience, then the character goes idle.\n" +
+				"A character brought to 0 hp wakes up at (0, 0). Health slowly returns outside of combat.\n" +
+				"Use 'sense' to see creatures and their health."
+		case "craft":
+			helpText = "\nCraft Recipe:\n" +
+				"Usage: craft <recipe> [amount]\n" +
+				"Sets your selected character to craft a recipe, one craft per tick.\n" +
+				"Ingredients are taken from the character's inventory.\n" +
+				"Optional amount parameter limits how many crafts to make before going idle.\n" +
+				"Use 'recipes' to see what can be crafted."
+		case "recipes":
+			helpText = "\nList Recipes:\n" +
+				"Usage: recipes\n" +
+				"Shows every recipe with its output and the ingredients it consumes."
 		case "sense":
 			helpText = "\nSense Area:\n" +
 				"Usage: sense\n" +
@@ -57,6 +95,12 @@ func (m *uiModel) showCommandHelp(command string) tea.Cmd {
 				"Usage: inv\n" +
 				"Displays your selected character's inventory with items, quantities,\n" +
 				"current weight, and total capacity."
+		case "skills":
+			helpText = "\nView Skills:\n" +
+				"Usage: skills\n" +
+				"Displays your selected character's level and experience in each skill.\n" +
+				"Skills gain experience from gathering with the matching action.\n" +
+				"Some resource nodes need a minimum skill level before you can gather them."
 		case "drop":
 			helpText = "\nDrop Items:\n" +
 				"Usage: drop <item_name> [quantity]\n" +
@@ -66,7 +110,43 @@ func (m *uiModel) showCommandHelp(command string) tea.Cmd {
 				"  drop wood      - drops all wood\n" +
 				"  drop balsa logs 2 - drops 2 balsa logs\n" +
 				"  drop balsa logs   - drops all balsa logs\n" +
-				"Case insensitive. Reduces inventory weight."
+				"Case insensitive. Reduces inventory weight.\n" +
+				"Dropped items stay on the ground where anyone nearby can pick them up, until they expire."
+		case "pickup":
+			helpText = "\nPick Up Items:\n" +
+				"Usage: pickup <item_name> [quantity]\n" +
+				"Picks up items lying on the ground at your selected character's location.\n" +
+				"If quantity is omitted, picks up all items of that type.\n" +
+				"Fails if the items would not fit in the character's inventory.\n" +
+				"Use 'sense' to see what is on the ground."
+		case "stash":
+			helpText = "\nView Stash:\n" +
+				"Usage: stash\n" +
+				"Displays the stash shared by all of your characters, with its weight and capacity.\n" +
+				"Any of your characters can deposit or withdraw from it, wherever they are."
+		case "deposit":
+			helpText = "\nDeposit Items:\n" +
+				"Usage: deposit <item_name> [quantity]\n" +
+				"Moves items from your selected character's inventory into your stash.\n" +
+				"If quantity is omitted, deposits all items of that type.\n" +
+				"Fails if the items would not fit in the stash."
+		case "withdraw":
+			helpText = "\nWithdraw Items:\n" +
+				"Usage: withdraw <item_name> [quantity]\n" +
+				"Moves items from your stash into your selected character's inventory.\n" +
+				"If quantity is omitted, withdraws all items of that type.\n" +
+				"Fails if the items would not fit in the character's inventory."
+		case "trade":
+			helpText = "\nTrade Items:\n" +
+				"Usage: trade [propose <character>|add <item_name> [quantity]|confirm|cancel]\n" +
+				"Trades items directly with another character at the same location.\n" +
+				"  trade                  - shows the open trade and both offers\n" +
+				"  trade propose <name>   - opens a trade with a character here\n" +
+				"  trade add <item> [qty] - adds items to your side of the trade\n" +
+				"  trade confirm          - accepts the trade as it stands\n" +
+				"  trade cancel           - cancels the trade\n" +
+				"Items only change hands once both sides confirm. Changing an offer clears\n" +
+				"both confirmations, and the trade fails if either side lacks room."
 		case "say":
 			helpText = "\nSend Chat Message:\n" +
 				"Usage: say <message>\n" +
@@ -40,6 +40,14 @@ func (s style) borderStyle(str string, color Color, width int) string {
 		Render(str)
 }
 
+func (s style) panelStyle(str string, borderColor Color) string {
+	return s.renderer.NewStyle().
+		Width(s.width-4).
+		Padding(0, 1).
+		Border(lg.RoundedBorder()).
+		BorderForeground(lg.Color(borderColor)).Render(str)
+}
+
 func (s style) colorStyle(str string, color Color) string {
 	return s.renderer.
 		NewStyle().
@@ -49,7 +57,7 @@ func (s style) colorStyle(str string, color Color) string {
 
 func (s style) viewportStyle(str string, borderColor Color) string {
 	return s.renderer.NewStyle().
-		Height(s.height - 5).
+		Height(s.height - 6).
 		Width(s.width - 2).
 		Border(lg.RoundedBorder()).
 		BorderForeground(lg.Color(borderColor)).Render(str)
@@ -62,10 +70,18 @@ func (s style) inputStyle(str string, borderColor Color) string {
 		BorderForeground(lg.Color(borderColor)).Render(str)
 }
 
-func (s style) uiView(viewport string, input string) string {
+func (s style) statusStyle(str string) string {
+	return s.renderer.NewStyle().
+		Width(s.width-2).
+		Padding(0, 1).
+		Foreground(lg.Color(Cyan)).Render(str)
+}
+
+func (s style) uiView(viewport string, status string, input string) string {
 	return lg.JoinVertical(
 		lg.Left,
 		viewport,
+		status,
 		input,
 	)
 }
@@ -9,6 +9,8 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gorilla/websocket"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
 type uiModel struct {
@@ -20,23 +22,56 @@ type uiModel struct {
 	cursor       int
 	wsConn       *websocket.Conn
 	wsConnected  bool
+	// inventory and progress are kept live from websocket pushes once
+	// the selected character's inventory has been fetched
+	inventory *inventoryResponse
+	progress  *actionProgressData
 }
 
 type characterData struct {
 	CharacterName string `json:"character_name"`
 	ActionName    string `json:"action_name"`
 	ActionTarget  string `json:"action_target"`
+	HP            int32  `json:"hp"`
+	MaxHP         int32  `json:"max_hp"`
+	ActionTicks   int32  `json:"action_ticks,omitempty"`
+	IntervalTicks int32  `json:"interval_ticks,omitempty"`
+}
+
+type resourceNodeData struct {
+	Name             string `json:"name"`
+	Remaining        *int32 `json:"remaining,omitempty"`
+	Amount           *int32 `json:"amount,omitempty"`
+	RespawnTicksLeft int32  `json:"respawn_ticks_left,omitempty"`
+}
+
+type creatureData struct {
+	Name             string `json:"name"`
+	HP               int32  `json:"hp"`
+	MaxHP            int32  `json:"max_hp"`
+	RespawnTicksLeft int32  `json:"respawn_ticks_left,omitempty"`
+}
+
+type groundItemData struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
 }
 
 type senseAreaResponse struct {
-	Characters    []characterData `json:"characters"`
-	ResourceNodes []string        `json:"resource_nodes"`
+	PositionX     int32              `json:"position_x"`
+	PositionY     int32              `json:"position_y"`
+	Characters    []characterData    `json:"characters"`
+	ResourceNodes []resourceNodeData `json:"resource_nodes"`
+	Creatures     []creatureData     `json:"creatures"`
+	GroundItems   []groundItemData   `json:"ground_items"`
 }
 
 type inventoryItem struct {
-	Quantity    int32 `json:"quantity"`
-	Weight      int32 `json:"weight"`
-	TotalWeight int32 `json:"total_weight"`
+	Quantity      int32   `json:"quantity"`
+	Weight        int32   `json:"weight"`
+	TotalWeight   int32   `json:"total_weight"`
+	MaxDurability int32   `json:"max_durability,omitempty"`
+	Durability    []int32 `json:"durability,omitempty"`
 }
 
 type inventoryResponse struct {
@@ -45,6 +80,74 @@ type inventoryResponse struct {
 	Capacity int32                    `json:"capacity"`
 }
 
+type recipeIngredient struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+}
+
+type recipeData struct {
+	Name        string             `json:"name"`
+	ItemName    string             `json:"item_name"`
+	Quantity    int32              `json:"quantity"`
+	Ingredients []recipeIngredient `json:"ingredients"`
+}
+
+type skillData struct {
+	Name                string `json:"name"`
+	Level               int32  `json:"level"`
+	Experience          int32  `json:"experience"`
+	NextLevelExperience int32  `json:"next_level_experience"`
+}
+
+type tradeOfferData struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+}
+
+type tradeData struct {
+	Status             string                     `json:"status"`
+	Initiator          string                     `json:"initiator"`
+	Recipient          string                     `json:"recipient"`
+	InitiatorConfirmed bool                       `json:"initiator_confirmed"`
+	RecipientConfirmed bool                       `json:"recipient_confirmed"`
+	Offers             map[string][]tradeOfferData `json:"offers"`
+}
+
+type awayItemData struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+}
+
+type awayCharacterData struct {
+	Name   string         `json:"name"`
+	Items  []awayItemData `json:"items"`
+	Events []string       `json:"events"`
+}
+
+type awaySummaryData struct {
+	AwaySeconds int64               `json:"away_seconds"`
+	Characters  []awayCharacterData `json:"characters"`
+}
+
+type itemDeltaData struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+	Weight   int32  `json:"weight"`
+}
+
+type inventoryDeltaData struct {
+	CharacterName string          `json:"character_name"`
+	Items         []itemDeltaData `json:"items"`
+}
+
+type actionProgressData struct {
+	CharacterName string `json:"character_name"`
+	ActionName    string `json:"action_name"`
+	Progress      int32  `json:"progress"`
+	Limit         int32  `json:"limit"`
+	Ticks         int32  `json:"ticks"`
+}
+
 type wsMessage struct {
 	Type string                 `json:"type"`
 	Data map[string]interface{} `json:"data"`
@@ -55,6 +158,33 @@ type chatMsgReceived struct {
 	color   Color
 }
 
+type panelReceived struct {
+	content string
+	color   Color
+}
+
+type inventoryDeltaReceived struct {
+	characters []inventoryDeltaData
+}
+
+type actionProgressReceived struct {
+	characters []actionProgressData
+}
+
+type characterIdleReceived struct {
+	characters []string
+}
+
+type inventoryChangedReceived struct {
+	characters []string
+}
+
+// inventoryRefreshed carries a refetched inventory, nil if fetching failed.
+type inventoryRefreshed struct {
+	characterName string
+	inventory     *inventoryResponse
+}
+
 type wsConnected struct{}
 
 type wsError struct {
@@ -69,6 +199,8 @@ func (m *uiModel) reset() {
 	}
 	
 	m.selectedChar = ""
+	m.inventory = nil
+	m.progress = nil
 	m.wsConnected = false
 	m.cursor = 0
 	
@@ -104,7 +236,7 @@ func InitUIModel(state *sharedState) *uiModel {
 	cmd.Focus()
 	m.input = cmd
 
-	vp := viewport.New(m.width-2, m.height-5)
+	vp := viewport.New(m.width-2, m.height-6)
 	m.viewport = vp
 	m.vpContent.WriteString("Welcome!\nType '?' for help with commands.\n")
 	m.viewport.SetContent(m.vpContent.String())
@@ -130,7 +262,7 @@ func (m *uiModel) Update(msg tea.Msg) tea.Cmd {
 		m.height = msg.Height
 
 		m.viewport.Width = msg.Width - 2
-		m.viewport.Height = msg.Height - 5
+		m.viewport.Height = msg.Height - 6
 
 		m.input.Width = msg.Width - 2
 	case apiResMsg:
@@ -144,6 +276,36 @@ func (m *uiModel) Update(msg tea.Msg) tea.Cmd {
 		m.viewport.SetContent(m.vpContent.String())
 		m.viewport.GotoBottom()
 		return m.listenForMessagesCmd()
+	case panelReceived:
+		m.vpContent.WriteString(m.panelStyle(msg.content, msg.color) + "\n")
+		m.viewport.SetContent(m.vpContent.String())
+		m.viewport.GotoBottom()
+		return m.listenForMessagesCmd()
+	case inventoryDeltaReceived:
+		m.applyInventoryDeltas(msg.characters)
+		return m.listenForMessagesCmd()
+	case actionProgressReceived:
+		for _, progress := range msg.characters {
+			if strings.EqualFold(progress.CharacterName, m.selectedChar) {
+				m.progress = &progress
+			}
+		}
+		return m.listenForMessagesCmd()
+	case characterIdleReceived:
+		if containsFold(msg.characters, m.selectedChar) {
+			m.progress = nil
+		}
+		return m.listenForMessagesCmd()
+	case inventoryChangedReceived:
+		// Only an inventory already being kept live is worth fetching again
+		if m.inventory != nil && containsFold(msg.characters, m.selectedChar) {
+			return tea.Batch(m.listenForMessagesCmd(), m.refreshInventory(m.selectedChar))
+		}
+		return m.listenForMessagesCmd()
+	case inventoryRefreshed:
+		if msg.inventory != nil && strings.EqualFold(msg.characterName, m.selectedChar) {
+			m.inventory = msg.inventory
+		}
 	case wsConnected:
 		m.wsConnected = true
 		output := m.colorStyle("Connected to chat", Green)
@@ -210,6 +372,44 @@ func (m *uiModel) Update(msg tea.Msg) tea.Cmd {
 						
 						return m.setActionWithAmount(target, amount)
 					}
+				case "craft":
+					if m.selectedChar == "" {
+						output = "No character selected. Use 'sel <character>' first"
+						outputColor = Red
+					} else if len(command) < 2 {
+						output = "Usage: craft <recipe> [amount]"
+						outputColor = Red
+					} else {
+						var recipe string
+						var amount *int
+
+						if len(command) >= 3 {
+							if lastArg, err := strconv.Atoi(command[len(command)-1]); err == nil && lastArg > 0 {
+								amount = &lastArg
+								recipe = strings.ToUpper(strings.Join(command[1:len(command)-1], " "))
+							} else {
+								recipe = strings.ToUpper(strings.Join(command[1:], " "))
+							}
+						} else {
+							recipe = strings.ToUpper(command[1])
+						}
+
+						return m.craft(recipe, amount)
+					}
+				case "recipes":
+					return m.getRecipes()
+				case "skills":
+					return m.getSkills()
+				case "move":
+					if m.selectedChar == "" {
+						output = "No character selected. Use 'sel <character>' first"
+						outputColor = Red
+					} else if len(command) < 2 {
+						output = "Usage: move <north|south|east|west|x,y>"
+						outputColor = Red
+					} else {
+						return m.moveCharacter(strings.Join(command[1:], ""))
+					}
 				case "sense":
 					return m.getArea()
 				case "inv":
@@ -258,6 +458,68 @@ func (m *uiModel) Update(msg tea.Msg) tea.Cmd {
 							return m.dropItemAll(itemName)
 						}
 					}
+				case "pickup":
+					if len(command) < 2 {
+						output = "Usage: pickup <item_name> [quantity]"
+						outputColor = Red
+					} else if quantity, err := strconv.Atoi(command[len(command)-1]); err == nil && quantity > 0 && len(command) > 2 {
+						itemName := strings.Join(command[1:len(command)-1], " ")
+						return m.pickupItem(itemName, &quantity)
+					} else {
+						itemName := strings.Join(command[1:], " ")
+						return m.pickupItem(itemName, nil)
+					}
+				case "attack":
+					if len(command) < 2 {
+						output = "Usage: attack <creature> [melee|archery]"
+						outputColor = Red
+					} else if style := strings.ToLower(command[len(command)-1]); len(command) > 2 && (style == "melee" || style == "archery") {
+						return m.attack(strings.Join(command[1:len(command)-1], " "), style)
+					} else {
+						return m.attack(strings.Join(command[1:], " "), "melee")
+					}
+				case "stash":
+					return m.getStash()
+				case "deposit", "withdraw":
+					if len(command) < 2 {
+						output = fmt.Sprintf("Usage: %v <item_name> [quantity]", command[0])
+						outputColor = Red
+					} else if quantity, err := strconv.Atoi(command[len(command)-1]); err == nil && quantity > 0 && len(command) > 2 {
+						itemName := strings.Join(command[1:len(command)-1], " ")
+						return m.transferStashItem(command[0], itemName, &quantity)
+					} else {
+						itemName := strings.Join(command[1:], " ")
+						return m.transferStashItem(command[0], itemName, nil)
+					}
+				case "trade":
+					if len(command) == 1 {
+						return m.getTrade()
+					}
+					switch command[1] {
+					case "propose":
+						if len(command) != 3 {
+							output = "Usage: trade propose <character>"
+							outputColor = Red
+						} else {
+							return m.proposeTrade(command[2])
+						}
+					case "add":
+						if len(command) < 3 {
+							output = "Usage: trade add <item_name> [quantity]"
+							outputColor = Red
+						} else if quantity, err := strconv.Atoi(command[len(command)-1]); err == nil && quantity > 0 && len(command) > 3 {
+							itemName := strings.Join(command[2:len(command)-1], " ")
+							return m.addTradeItem(itemName, quantity)
+						} else {
+							itemName := strings.Join(command[2:], " ")
+							return m.addTradeItem(itemName, 1)
+						}
+					case "confirm", "cancel":
+						return m.updateTrade(command[1])
+					default:
+						output = "Usage: trade [propose <character>|add <item> [qty]|confirm|cancel]"
+						outputColor = Red
+					}
 				case "?":
 					if len(command) == 1 {
 						return m.showHelp()
@@ -304,5 +566,76 @@ func (m *uiModel) View() string {
 	viewportStyle := m.viewportStyle(m.viewport.View(), vpColor)
 	inputStyle := m.inputStyle(m.input.View(), cmdColor)
 
-	return m.uiView(viewportStyle, inputStyle)
+	return m.uiView(viewportStyle, m.statusStyle(m.statusLine()), inputStyle)
+}
+
+// applyInventoryDeltas keeps the selected character's inventory current
+// between fetches.
+func (m *uiModel) applyInventoryDeltas(characters []inventoryDeltaData) {
+	if m.inventory == nil {
+		return
+	}
+
+	for _, delta := range characters {
+		if !strings.EqualFold(delta.CharacterName, m.selectedChar) {
+			continue
+		}
+		if m.inventory.Items == nil {
+			m.inventory.Items = make(map[string]inventoryItem)
+		}
+		for _, change := range delta.Items {
+			item := m.inventory.Items[change.Name]
+			item.Quantity += change.Quantity
+			item.Weight = change.Weight
+			item.TotalWeight = item.Quantity * item.Weight
+			m.inventory.Weight += change.Quantity * change.Weight
+			if item.Quantity <= 0 {
+				delete(m.inventory.Items, change.Name)
+			} else {
+				m.inventory.Items[change.Name] = item
+			}
+		}
+	}
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusLine shows the selected character's current action and load.
+func (m *uiModel) statusLine() string {
+	if m.selectedChar == "" {
+		return ""
+	}
+
+	caser := cases.Title(language.English)
+	parts := []string{caser.String(m.selectedChar)}
+	if m.progress != nil {
+		parts = append(parts, formatProgress(*m.progress))
+	}
+	if m.inventory != nil {
+		parts = append(parts, fmt.Sprintf("Weight %d/%d", m.inventory.Weight, m.inventory.Capacity))
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+func formatProgress(progress actionProgressData) string {
+	caser := cases.Title(language.English)
+	action := caser.String(progress.ActionName)
+	if progress.Limit <= 0 {
+		return fmt.Sprintf("%s (%d done)", action, progress.Progress)
+	}
+
+	const width = 10
+	filled := int(progress.Progress * width / progress.Limit)
+	filled = min(max(filled, 0), width)
+
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", width-filled)
+	return fmt.Sprintf("%s [%s] %d/%d", action, bar, progress.Progress, progress.Limit)
 }
\ No newline at end of file
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gorilla/websocket"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
 func (m *uiModel) connectWebSocketCmd() tea.Cmd {
@@ -68,6 +73,40 @@ func (m *uiModel) listenForMessagesCmd() tea.Cmd {
 				notificationMsg := fmt.Sprintf("⚠ %s", data)
 				return chatMsgReceived{message: notificationMsg, color: Magenta}
 			}
+		case "trade":
+			if data, ok := msg.Data["message"].(string); ok {
+				tradeMsg := fmt.Sprintf("⇄ %s", data)
+				return chatMsgReceived{message: tradeMsg, color: Yellow}
+			}
+		case "inventory_delta":
+			var delta struct {
+				Characters []inventoryDeltaData `json:"characters"`
+			}
+			if data, err := json.Marshal(msg.Data); err == nil && json.Unmarshal(data, &delta) == nil {
+				return inventoryDeltaReceived{characters: delta.Characters}
+			}
+		case "action_progress":
+			var progress struct {
+				Characters []actionProgressData `json:"characters"`
+			}
+			if data, err := json.Marshal(msg.Data); err == nil && json.Unmarshal(data, &progress) == nil {
+				return actionProgressReceived{characters: progress.Characters}
+			}
+		case "character_idle", "inventory_changed":
+			var changed struct {
+				Characters []string `json:"characters"`
+			}
+			if data, err := json.Marshal(msg.Data); err == nil && json.Unmarshal(data, &changed) == nil {
+				if msg.Type == "character_idle" {
+					return characterIdleReceived{characters: changed.Characters}
+				}
+				return inventoryChangedReceived{characters: changed.Characters}
+			}
+		case "away_summary":
+			var summary awaySummaryData
+			if data, err := json.Marshal(msg.Data); err == nil && json.Unmarshal(data, &summary) == nil {
+				return panelReceived{content: formatAwaySummary(summary), color: Cyan}
+			}
 		case "error":
 			if data, ok := msg.Data["message"].(string); ok {
 				errorMsg := fmt.Sprintf("Error: %s", data)
@@ -115,4 +154,31 @@ func (m *uiModel) sendChatMessage(message string) tea.Cmd {
 
 		return nil
 	}
-}
\ No newline at end of file
+}
+
+func formatAwaySummary(summary awaySummaryData) string {
+	caser := cases.Title(language.English)
+	away := (time.Duration(summary.AwaySeconds) * time.Second).Round(time.Minute)
+
+	awayFor := "under a minute"
+	if away >= time.Minute {
+		awayFor = strings.TrimSuffix(away.String(), "0s")
+	}
+
+	lines := []string{fmt.Sprintf("While you were away (%s)", awayFor)}
+	for _, char := range summary.Characters {
+		lines = append(lines, "", caser.String(char.Name))
+		for _, item := range char.Items {
+			sign := "+"
+			if item.Quantity < 0 {
+				sign = ""
+			}
+			lines = append(lines, fmt.Sprintf("\t%s%d %s", sign, item.Quantity, caser.String(item.Name)))
+		}
+		for _, event := range char.Events {
+			lines = append(lines, "\t"+event)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
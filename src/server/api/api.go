@@ -15,12 +15,21 @@ import (
 )
 
 type ApiConfig struct {
-	DB        *database.Queries
-	JwtSecret string
-	Redis     *redis.Client
-	Pool      *pgxpool.Pool
-	Hub       *websocket.Hub
-	Limiter   *ratelimit.Limiter
+	DB          *database.Queries
+	JwtSecret   string
+	Redis       *redis.Client
+	Pool        *pgxpool.Pool
+	Hub         *websocket.Hub
+	Limiter     *ratelimit.Limiter
+	TravelTicks int32
+
+	// notifications collects notifications inside RunInTx until the commit
+	notifications *[]Notification
+	// changes collects character changes inside RunInTx until the commit
+	changes *[]CharacterChange
+	// invalidations collects cache keys inside RunInTx, so they're only
+	// dropped once the commit makes the cached rows stale
+	invalidations *[]string
 }
 
 func (cfg *ApiConfig) setupCORS(handler http.Handler) http.Handler {
@@ -66,10 +75,24 @@ func (cfg *ApiConfig) ServeApi() {
 	mux.Handle("POST /api/characters", apiRateLimit(http.HandlerFunc(cfg.handleCreateCharacter)))
 	mux.Handle("PUT /api/characters", apiRateLimit(http.HandlerFunc(cfg.handleUpdateCharacter)))
 	mux.Handle("GET /api/characters/{character}/select", apiRateLimit(http.HandlerFunc(cfg.handleSelectCharacter)))
+	mux.Handle("POST /api/characters/{character}/move", apiRateLimit(http.HandlerFunc(cfg.handleMoveCharacter)))
+	mux.Handle("POST /api/characters/{character}/craft", apiRateLimit(http.HandlerFunc(cfg.handleCraft)))
+	mux.Handle("POST /api/characters/{character}/attack", apiRateLimit(http.HandlerFunc(cfg.handleAttack)))
+	mux.Handle("GET /api/characters/{character}/skills", apiRateLimit(http.HandlerFunc(cfg.handleGetSkills)))
+	mux.Handle("GET /api/characters/{character}/trade", apiRateLimit(http.HandlerFunc(cfg.handleGetTrade)))
+	mux.Handle("POST /api/characters/{character}/trade", apiRateLimit(http.HandlerFunc(cfg.handleProposeTrade)))
+	mux.Handle("POST /api/characters/{character}/trade/items", apiRateLimit(http.HandlerFunc(cfg.handleAddTradeItem)))
+	mux.Handle("POST /api/characters/{character}/trade/confirm", apiRateLimit(http.HandlerFunc(cfg.handleConfirmTrade)))
+	mux.Handle("POST /api/characters/{character}/trade/cancel", apiRateLimit(http.HandlerFunc(cfg.handleCancelTrade)))
+	mux.Handle("GET /api/recipes", apiRateLimit(http.HandlerFunc(cfg.handleGetRecipes)))
 	mux.Handle("GET /api/actions", apiRateLimit(http.HandlerFunc(cfg.handleGetActions)))
 	mux.Handle("GET /api/sense/area/{character}", apiRateLimit(http.HandlerFunc(cfg.handleGetArea)))
 	mux.Handle("GET /api/inventory/{character}", apiRateLimit(http.HandlerFunc(cfg.handleGetInventory)))
 	mux.Handle("POST /api/inventory/drop", apiRateLimit(http.HandlerFunc(cfg.handleDropItem)))
+	mux.Handle("POST /api/inventory/pickup", apiRateLimit(http.HandlerFunc(cfg.handlePickupItem)))
+	mux.Handle("GET /api/stash", apiRateLimit(http.HandlerFunc(cfg.handleGetStash)))
+	mux.Handle("POST /api/stash/deposit", apiRateLimit(http.HandlerFunc(cfg.handleDepositItem)))
+	mux.Handle("POST /api/stash/withdraw", apiRateLimit(http.HandlerFunc(cfg.handleWithdrawItem)))
 	mux.Handle("POST /api/login", apiRateLimit(http.HandlerFunc(cfg.handleLogin)))
 	mux.Handle("POST /api/refresh", apiRateLimit(http.HandlerFunc(cfg.handleRefresh)))
 	mux.Handle("POST /api/revoke", apiRateLimit(http.HandlerFunc(cfg.handleRevoke)))
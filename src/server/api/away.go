@@ -0,0 +1,292 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/internal/database"
+)
+
+// AwaySummary is what a user's characters did while nobody was connected.
+type AwaySummary struct {
+	Since      time.Time              `json:"since"`
+	Characters []CharacterAwaySummary `json:"characters"`
+}
+
+type CharacterAwaySummary struct {
+	Name   string     `json:"name"`
+	Items  []ItemGain `json:"items"`
+	Events []string   `json:"events"`
+}
+
+type ItemGain struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+}
+
+func awaySummaryCacheKey(userID uuid.UUID) string {
+	return fmt.Sprintf("away_summary:user:%s", userID.String())
+}
+
+// MarkUserAway starts recording gains for a user once their last connection
+// closes.
+func (cfg *ApiConfig) MarkUserAway(ctx context.Context, userID uuid.UUID) {
+	err := cfg.DB.MarkUserSeen(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		log.Printf("Failed to mark user %s away: %v", userID, err)
+	}
+}
+
+// RecordCharacterEvent keeps a note of something that happened to a
+// character, such as going idle, for the owner's away summary. Nothing is
+// kept while the owner is connected, they get the notification instead.
+func (cfg *ApiConfig) RecordCharacterEvent(ctx context.Context, characterID pgtype.UUID, message string) error {
+	err := cfg.DB.RecordCharacterEvent(ctx, database.RecordCharacterEventParams{
+		Message:     message,
+		CharacterID: characterID,
+	})
+	if err != nil {
+		return fmt.Errorf("recording event for character %s: %w", characterID.String(), err)
+	}
+	return nil
+}
+
+// recordCharacterGains adds applied inventory updates to the away gains of
+// any character whose owner isn't connected.
+func (cfg *ApiConfig) recordCharacterGains(ctx context.Context, updates []InventoryUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	inventoryIDs := make([]pgtype.UUID, len(updates))
+	itemIDs := make([]int32, len(updates))
+	quantities := make([]int32, len(updates))
+	for i, update := range updates {
+		inventoryIDs[i] = update.InventoryID
+		itemIDs[i] = update.ItemID
+		quantities[i] = update.Quantity
+	}
+
+	err := cfg.DB.BatchRecordCharacterGains(ctx, database.BatchRecordCharacterGainsParams{
+		InventoryIds: inventoryIDs,
+		ItemIds:      itemIDs,
+		Quantities:   quantities,
+	})
+	if err != nil {
+		return fmt.Errorf("recording character gains: %w", err)
+	}
+	return nil
+}
+
+// takeAwaySummary collects and clears everything recorded for the user's
+// characters since they were last seen. online marks the user as connected
+// in the same transaction so nothing recorded afterwards is lost. A nil
+// summary means there was nothing to report.
+func (cfg *ApiConfig) takeAwaySummary(ctx context.Context, userID uuid.UUID, online bool) (*AwaySummary, error) {
+	pgUserID := pgtype.UUID{Bytes: userID, Valid: true}
+
+	tx, err := cfg.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	txDB := cfg.DB.WithTx(tx)
+
+	user, err := txDB.GetUserById(ctx, pgUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if online {
+		err = txDB.MarkUserOnline(ctx, pgUserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	gains, err := txDB.TakeCharacterGainsByUserId(ctx, pgUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := txDB.TakeCharacterEventsByUserId(ctx, pgUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.LastSeenAt.Valid {
+		return nil, nil
+	}
+
+	summary := &AwaySummary{Since: user.LastSeenAt.Time}
+	byCharacter := make(map[pgtype.UUID]*CharacterAwaySummary)
+	characterSummary := func(characterID pgtype.UUID) *CharacterAwaySummary {
+		if s, ok := byCharacter[characterID]; ok {
+			return s
+		}
+		s := &CharacterAwaySummary{}
+		if character, err := cfg.GetCharacterById(ctx, characterID); err == nil {
+			s.Name = character.Name
+		}
+		byCharacter[characterID] = s
+		return s
+	}
+
+	for _, gain := range gains {
+		if gain.Quantity == 0 {
+			continue
+		}
+		item, err := cfg.GetItemById(ctx, gain.ItemID)
+		if err != nil {
+			continue
+		}
+		s := characterSummary(gain.CharacterID)
+		s.Items = append(s.Items, ItemGain{Name: item.Name, Quantity: gain.Quantity})
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	for _, event := range events {
+		s := characterSummary(event.CharacterID)
+		s.Events = append(s.Events, event.Message)
+	}
+
+	for _, s := range byCharacter {
+		summary.Characters = append(summary.Characters, *s)
+	}
+	summary.normalize()
+
+	if len(summary.Characters) == 0 {
+		return nil, nil
+	}
+	return summary, nil
+}
+
+// normalize sorts characters and items by name so summaries read the same
+// every time.
+func (s *AwaySummary) normalize() {
+	sort.Slice(s.Characters, func(i, j int) bool { return s.Characters[i].Name < s.Characters[j].Name })
+	for _, character := range s.Characters {
+		sort.Slice(character.Items, func(i, j int) bool { return character.Items[i].Name < character.Items[j].Name })
+	}
+}
+
+// MergeAwaySummaries combines two summaries for the same user, keeping the
+// earlier start and adding up gains per character and item.
+func MergeAwaySummaries(a, b *AwaySummary) *AwaySummary {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	merged := &AwaySummary{Since: a.Since}
+	if b.Since.Before(merged.Since) {
+		merged.Since = b.Since
+	}
+
+	byName := make(map[string]*CharacterAwaySummary)
+	for _, summary := range []*AwaySummary{a, b} {
+		for _, character := range summary.Characters {
+			s, ok := byName[character.Name]
+			if !ok {
+				s = &CharacterAwaySummary{Name: character.Name}
+				byName[character.Name] = s
+			}
+			for _, gain := range character.Items {
+				found := false
+				for i := range s.Items {
+					if s.Items[i].Name == gain.Name {
+						s.Items[i].Quantity += gain.Quantity
+						found = true
+						break
+					}
+				}
+				if !found {
+					s.Items = append(s.Items, gain)
+				}
+			}
+			s.Events = append(s.Events, character.Events...)
+		}
+	}
+
+	for _, s := range byName {
+		merged.Characters = append(merged.Characters, *s)
+	}
+	merged.normalize()
+
+	return merged
+}
+
+// PrepareAwaySummary runs at login. Logging in drops every open connection,
+// so the summary is held until the new websocket connects.
+func (cfg *ApiConfig) PrepareAwaySummary(ctx context.Context, userID uuid.UUID) error {
+	summary, err := cfg.takeAwaySummary(ctx, userID, false)
+	if err != nil || summary == nil {
+		return err
+	}
+
+	pending := cfg.getPendingAwaySummary(ctx, userID)
+	data, err := json.Marshal(MergeAwaySummaries(pending, summary))
+	if err != nil {
+		return err
+	}
+
+	return cfg.Redis.Set(ctx, awaySummaryCacheKey(userID), data, 24*time.Hour).Err()
+}
+
+// DeliverAwaySummary runs when a websocket connects. It marks the user as
+// connected and sends whatever happened since they were last seen.
+func (cfg *ApiConfig) DeliverAwaySummary(ctx context.Context, userID uuid.UUID) error {
+	summary, err := cfg.takeAwaySummary(ctx, userID, true)
+	if err != nil {
+		return err
+	}
+
+	pending := cfg.getPendingAwaySummary(ctx, userID)
+	cfg.Redis.Del(ctx, awaySummaryCacheKey(userID))
+
+	summary = MergeAwaySummaries(pending, summary)
+	if summary == nil {
+		return nil
+	}
+
+	cfg.SendAwaySummary(userID, summary)
+	return nil
+}
+
+// SendAwaySummary sends a summary to the user if they're connected.
+func (cfg *ApiConfig) SendAwaySummary(userID uuid.UUID, summary *AwaySummary) {
+	summary.normalize()
+	cfg.Hub.SendToUser(userID, "away_summary", map[string]interface{}{
+		"since":        summary.Since,
+		"away_seconds": int64(time.Since(summary.Since).Seconds()),
+		"characters":   summary.Characters,
+	})
+}
+
+// getPendingAwaySummary returns the summary held since login, if any.
+func (cfg *ApiConfig) getPendingAwaySummary(ctx context.Context, userID uuid.UUID) *AwaySummary {
+	cached, err := cfg.Redis.Get(ctx, awaySummaryCacheKey(userID)).Result()
+	if err != nil {
+		return nil
+	}
+
+	var summary AwaySummary
+	if json.Unmarshal([]byte(cached), &summary) != nil {
+		return nil
+	}
+	return &summary
+}
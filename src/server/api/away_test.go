@@ -0,0 +1,54 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeAwaySummaries(t *testing.T) {
+	earlier := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	a := &AwaySummary{
+		Since: later,
+		Characters: []CharacterAwaySummary{
+			{Name: "BOB", Items: []ItemGain{{Name: "STICKS", Quantity: 3}}, Events: []string{"first"}},
+		},
+	}
+	b := &AwaySummary{
+		Since: earlier,
+		Characters: []CharacterAwaySummary{
+			{Name: "BOB", Items: []ItemGain{{Name: "ROCKS", Quantity: 1}, {Name: "STICKS", Quantity: 2}}, Events: []string{"second"}},
+			{Name: "ALICE", Items: []ItemGain{{Name: "ROCKS", Quantity: 4}}},
+		},
+	}
+
+	if got := MergeAwaySummaries(nil, b); got != b {
+		t.Errorf("MergeAwaySummaries(nil, b) should return b")
+	}
+	if got := MergeAwaySummaries(a, nil); got != a {
+		t.Errorf("MergeAwaySummaries(a, nil) should return a")
+	}
+
+	merged := MergeAwaySummaries(a, b)
+	if !merged.Since.Equal(earlier) {
+		t.Errorf("Since = %v, want %v", merged.Since, earlier)
+	}
+	if len(merged.Characters) != 2 || merged.Characters[0].Name != "ALICE" || merged.Characters[1].Name != "BOB" {
+		t.Fatalf("Characters = %+v, want ALICE then BOB", merged.Characters)
+	}
+
+	bob := merged.Characters[1]
+	want := []ItemGain{{Name: "ROCKS", Quantity: 1}, {Name: "STICKS", Quantity: 5}}
+	if len(bob.Items) != len(want) {
+		t.Fatalf("BOB items = %+v, want %+v", bob.Items, want)
+	}
+	for i := range want {
+		if bob.Items[i] != want[i] {
+			t.Errorf("BOB item %d = %+v, want %+v", i, bob.Items[i], want[i])
+		}
+	}
+	if len(bob.Events) != 2 || bob.Events[0] != "first" || bob.Events[1] != "second" {
+		t.Errorf("BOB events = %v, want [first second]", bob.Events)
+	}
+}
@@ -152,6 +152,11 @@ func (cfg *ApiConfig) handleUpdateCharacter(w http.ResponseWriter, r *http.Reque
 		}
 		actionTarget = pgtype.Int4{Valid: false}
 	} else if params.Target != "" {
+		if character.DestinationX.Valid {
+			respondWithError(w, http.StatusBadRequest, "Character can't gather while traveling", nil)
+			return
+		}
+
 		resourceNodes, err := cfg.GetResourceNodeSpawnsByCoordinates(r.Context(), character.PositionX, character.PositionY)
 		if err != nil {
 			respondWithError(w, http.StatusInternalServerError, "Unable to get resource nodes", err)
@@ -179,14 +184,41 @@ func (cfg *ApiConfig) handleUpdateCharacter(w http.ResponseWriter, r *http.Reque
 			return
 		}
 
+		// The cached spawn doesn't follow depletion, so check what's left directly
+		currentSpawn, err := cfg.DB.GetResourceNodeSpawnById(r.Context(), foundSpawn.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to get resource node", err)
+			return
+		}
+		if currentSpawn.Remaining.Valid && currentSpawn.Remaining.Int32 == 0 {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("%s is depleted and respawns in %d ticks", foundNode.Name, currentSpawn.RespawnTicksLeft), nil)
+			return
+		}
+
+		if foundNode.MinLevel > 1 {
+			level := int32(1)
+			skill, err := cfg.DB.GetCharacterSkill(r.Context(), database.GetCharacterSkillParams{
+				CharacterID: character.ID,
+				ActionID:    action.ID,
+			})
+			if err == nil {
+				level = LevelForExperience(skill.Experience)
+			}
+			if level < foundNode.MinLevel {
+				respondWithError(w, http.StatusBadRequest, fmt.Sprintf("You need %s level %d to gather from %s", strings.ToLower(action.Name), foundNode.MinLevel, foundNode.Name), nil)
+				return
+			}
+		}
+
 		actionTarget = pgtype.Int4{Int32: foundSpawn.ID, Valid: true}
 	} else {
 		respondWithError(w, http.StatusBadRequest, "Target must be provided", nil)
 		return
 	}
 
+	response := map[string]interface{}{}
 	if action.RequiredToolTypeID.Valid && params.Target != "IDLE" {
-		bestTool, bestTier, err := cfg.GetBestToolForType(r.Context(), character.ID, action.RequiredToolTypeID.Int32, foundNode.MinToolTier)
+		bestTool, toolInstance, err := cfg.GetBestToolForType(r.Context(), character.ID, action.RequiredToolTypeID.Int32, foundNode.MinToolTier)
 		if err != nil {
 			respondWithError(w, http.StatusInternalServerError, "Unable to check required tool", err)
 			return
@@ -201,7 +233,12 @@ func (cfg *ApiConfig) handleUpdateCharacter(w http.ResponseWriter, r *http.Reque
 			return
 		}
 
-		_ = bestTier
+		response["tool"] = bestTool.Name
+		response["tool_tier"] = bestTool.ToolTier
+		response["yield"] = GatherYield(bestTool.ToolTier, foundNode.MinToolTier)
+		if toolInstance.Durability.Valid {
+			response["durability"] = toolInstance.Durability.Int32
+		}
 	}
 
 	var amountLimit pgtype.Int4
@@ -224,13 +261,19 @@ func (cfg *ApiConfig) handleUpdateCharacter(w http.ResponseWriter, r *http.Reque
 
 	// Invalidate active characters cache since character action changed
 	cfg.InvalidateActiveCharactersCache(r.Context())
+	cfg.InvalidateCharacterCache(r.Context(), char)
 
-	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
-		"name":        char.Name,
-		"action_id":   char.ActionID,
-		"action_name": action.Name,
-		"target":      params.Target,
-	})
+	if foundNode != nil {
+		response["interval_ticks"] = foundNode.IntervalTicks
+		response["min_quantity"] = foundNode.MinQuantity
+		response["max_quantity"] = foundNode.MaxQuantity
+	}
+	response["name"] = char.Name
+	response["action_id"] = char.ActionID
+	response["action_name"] = action.Name
+	response["target"] = params.Target
+
+	respondWithJSON(w, http.StatusCreated, response)
 }
 
 func (cfg *ApiConfig) GetActiveCharacters(ctx context.Context) ([]database.Character, error) {
@@ -266,25 +309,65 @@ func (cfg *ApiConfig) BatchUpdateCharacterProgress(ctx context.Context, updates
 	
 	ids := make([]pgtype.UUID, len(updates))
 	progress := make([]int32, len(updates))
+	ticks := make([]int32, len(updates))
+	
+	var skillCharacterIDs []pgtype.UUID
+	var skillActionIDs []int32
+	var experience []int32
 	
 	for i, update := range updates {
 		ids[i] = update.CharacterID
 		progress[i] = update.Progress
+		ticks[i] = update.Ticks
+		if update.Experience > 0 {
+			skillCharacterIDs = append(skillCharacterIDs, update.CharacterID)
+			skillActionIDs = append(skillActionIDs, update.ActionID)
+			experience = append(experience, update.Experience)
+		}
 	}
 	
-	return cfg.DB.BatchUpdateCharacterProgress(ctx, database.BatchUpdateCharacterProgressParams{
+	err := cfg.DB.BatchUpdateCharacterProgress(ctx, database.BatchUpdateCharacterProgressParams{
 		Column1: ids,
 		Column2: progress,
+		Column3: ticks,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Progress lives on the cached rows, so the next tick has to see the new values
+	cfg.InvalidateActiveCharactersCache(ctx)
+
+	if len(skillCharacterIDs) == 0 {
+		return nil
+	}
+
+	return cfg.DB.BatchAddSkillExperience(ctx, database.BatchAddSkillExperienceParams{
+		CharacterIds: skillCharacterIDs,
+		ActionIds:    skillActionIDs,
+		Experience:   experience,
 	})
 }
 
 type CharacterProgressUpdate struct {
 	CharacterID pgtype.UUID
 	Progress    int32
+	// Experience is credited to the character's skill for ActionID
+	ActionID   int32
+	Experience int32
+	// Ticks is how long the character has worked toward its next drop
+	Ticks int32
 }
 
 func (cfg *ApiConfig) InvalidateActiveCharactersCache(ctx context.Context) {
-	cfg.Redis.Del(ctx, "active_characters")
+	cfg.invalidateCache(ctx, "active_characters")
+}
+
+func (cfg *ApiConfig) InvalidateCharacterCache(ctx context.Context, character database.Character) {
+	cfg.invalidateCache(ctx,
+		fmt.Sprintf("character:name:%s", character.Name),
+		fmt.Sprintf("character:id:%s", character.ID.String()),
+	)
 }
 
 func (cfg *ApiConfig) GetCharacterByName(ctx context.Context, name string) (database.Character, error) {
@@ -371,13 +454,15 @@ func (cfg *ApiConfig) SetCharacterToIdle(ctx context.Context, characterID pgtype
 		return err
 	}
 
-	_, err = cfg.DB.SetCharacterToIdleAndResetGathering(ctx, database.SetCharacterToIdleAndResetGatheringParams{
+	character, err := cfg.DB.SetCharacterToIdleAndResetGathering(ctx, database.SetCharacterToIdleAndResetGatheringParams{
 		ActionID: idleAction.ID,
 		ID:       characterID,
 	})
 	if err == nil {
 		// Invalidate active characters cache since character went idle
 		cfg.InvalidateActiveCharactersCache(ctx)
+		cfg.InvalidateCharacterCache(ctx, character)
+		cfg.characterChanged(CharacterChange{Character: character, Idled: true})
 	}
 	return err
 }
@@ -0,0 +1,299 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/internal/auth"
+	"github.com/trbute/idler/server/internal/database"
+	"github.com/trbute/idler/server/internal/validation"
+)
+
+const (
+	meleeBaseDamage   = 4
+	archeryBaseDamage = 3
+
+	// Defeated characters wake up at the origin, where every character starts
+	respawnPositionX = 0
+	respawnPositionY = 0
+)
+
+func (cfg *ApiConfig) handleAttack(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	characterName := r.PathValue("character")
+	if err := validation.ValidateCharacterName(characterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	type parameters struct {
+		Target string `json:"target"`
+		Style  string `json:"style"`
+	}
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to decode parameters", err)
+		return
+	}
+
+	params.Target = strings.TrimSpace(strings.ToUpper(params.Target))
+	if err := validation.ValidateTarget(params.Target); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	params.Style = strings.TrimSpace(strings.ToUpper(params.Style))
+	if params.Style == "" {
+		params.Style = "MELEE"
+	}
+	if params.Style != "MELEE" && params.Style != "ARCHERY" {
+		respondWithError(w, http.StatusBadRequest, "Style must be melee or archery", nil)
+		return
+	}
+
+	character, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), characterName, userID)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusNotFound, "Character not found", err)
+		}
+		return
+	}
+
+	if character.DestinationX.Valid {
+		respondWithError(w, http.StatusBadRequest, "Character can't fight while traveling", nil)
+		return
+	}
+
+	// Spawn health changes every tick, so read it directly
+	spawns, err := cfg.DB.GetCreatureSpawnsByCoordinates(r.Context(), database.GetCreatureSpawnsByCoordinatesParams{
+		PositionX: character.PositionX,
+		PositionY: character.PositionY,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to get creatures", err)
+		return
+	}
+
+	var foundCreature *database.Creature
+	var foundSpawn *database.CreatureSpawn
+	for _, spawn := range spawns {
+		creature, err := cfg.GetCreatureById(r.Context(), spawn.CreatureID)
+		if err != nil || !strings.EqualFold(creature.Name, params.Target) {
+			continue
+		}
+		foundCreature = &creature
+		foundSpawn = &spawn
+		// Prefer a live spawn when several of the same creature share a cell
+		if spawn.Hp > 0 {
+			break
+		}
+	}
+
+	if foundSpawn == nil {
+		respondWithError(w, http.StatusBadRequest, "Target not found at character location", nil)
+		return
+	}
+
+	if foundSpawn.Hp == 0 {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("%s is dead and respawns in %d ticks", foundCreature.Name, foundSpawn.RespawnTicksLeft), nil)
+		return
+	}
+
+	action, err := cfg.GetActionByName(r.Context(), params.Style)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to get combat action", err)
+		return
+	}
+
+	char, err := cfg.DB.StartCharacterCombat(r.Context(), database.StartCharacterCombatParams{
+		ActionID:             action.ID,
+		ActionCreatureTarget: pgtype.Int4{Int32: foundSpawn.ID, Valid: true},
+		ID:                   character.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Character update failed", err)
+		return
+	}
+
+	cfg.InvalidateActiveCharactersCache(r.Context())
+	cfg.InvalidateCharacterCache(r.Context(), char)
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"name":        char.Name,
+		"action_id":   char.ActionID,
+		"action_name": action.Name,
+		"target":      foundCreature.Name,
+		"hp":          char.Hp,
+		"max_hp":      char.MaxHp,
+		"creature_hp": foundSpawn.Hp,
+	})
+}
+
+// CombatDamage returns the damage a character deals in one tick. Archers
+// trade some damage for keeping their distance.
+func CombatDamage(actionName string, level int32) int32 {
+	if actionName == "ARCHERY" {
+		return archeryBaseDamage + level
+	}
+	return meleeBaseDamage + level
+}
+
+// DamageTaken returns how much of a creature's damage lands on a character.
+// Creatures only reach archers half as often, rounded up.
+func DamageTaken(actionName string, creatureDamage int32) int32 {
+	if actionName == "ARCHERY" {
+		return (creatureDamage + 1) / 2
+	}
+	return creatureDamage
+}
+
+// ExperienceForKill is the XP for defeating a creature, scaled by its tier
+// and how long it takes to bring down.
+func ExperienceForKill(creatureTier int32, maxHP int32) int32 {
+	return ExperienceForDrop(creatureTier, max(maxHP/10, 1))
+}
+
+type CharacterDamageUpdate struct {
+	CharacterID pgtype.UUID
+	Damage      int32
+}
+
+// BatchDamageCharacters applies the damage characters took this tick.
+// Characters brought to zero health are defeated and sent back to respawn.
+func (cfg *ApiConfig) BatchDamageCharacters(ctx context.Context, updates []CharacterDamageUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	ids := make([]pgtype.UUID, len(updates))
+	damages := make([]int32, len(updates))
+	for i, update := range updates {
+		ids[i] = update.CharacterID
+		damages[i] = update.Damage
+	}
+
+	characters, err := cfg.DB.BatchDamageCharacters(ctx, database.BatchDamageCharactersParams{
+		Ids:     ids,
+		Damages: damages,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, character := range characters {
+		cfg.InvalidateCharacterCache(ctx, character)
+		if character.Hp > 0 {
+			continue
+		}
+
+		err := cfg.DefeatCharacter(ctx, character)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DefeatCharacter restores a character's health and moves them, with their
+// inventory, back to the respawn cell.
+func (cfg *ApiConfig) DefeatCharacter(ctx context.Context, character database.Character) error {
+	idleAction, err := cfg.GetActionByName(ctx, "IDLE")
+	if err != nil {
+		return err
+	}
+
+	respawned, err := cfg.DB.RespawnCharacter(ctx, database.RespawnCharacterParams{
+		PositionX: respawnPositionX,
+		PositionY: respawnPositionY,
+		ActionID:  idleAction.ID,
+		ID:        character.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = cfg.DB.UpdateInventoryPositionByCharacterId(ctx, database.UpdateInventoryPositionByCharacterIdParams{
+		CharacterID: respawned.ID,
+		PositionX:   respawned.PositionX,
+		PositionY:   respawned.PositionY,
+	})
+	if err != nil {
+		return err
+	}
+
+	cfg.InvalidateActiveCharactersCache(ctx)
+	cfg.InvalidateCharacterCache(ctx, respawned)
+	cfg.invalidateCache(ctx, fmt.Sprintf("inventory:char:%s", respawned.ID.String()))
+	cfg.characterChanged(CharacterChange{Character: respawned, Idled: true})
+
+	message := fmt.Sprintf("Character %s was defeated and woke up at (%d, %d)",
+		respawned.Name, respawned.PositionX, respawned.PositionY)
+	return cfg.NotifyCharacterOwner(ctx, respawned, message, "warning")
+}
+
+func (cfg *ApiConfig) GetCreatureById(ctx context.Context, creatureID int32) (database.Creature, error) {
+	cacheKey := fmt.Sprintf("creature:%d", creatureID)
+
+	cached, err := cfg.Redis.Get(ctx, cacheKey).Result()
+	if err == nil {
+		var creature database.Creature
+		if json.Unmarshal([]byte(cached), &creature) == nil {
+			return creature, nil
+		}
+	}
+
+	creature, err := cfg.DB.GetCreatureById(ctx, creatureID)
+	if err != nil {
+		return database.Creature{}, err
+	}
+
+	if data, err := json.Marshal(creature); err == nil {
+		cfg.Redis.Set(ctx, cacheKey, data, 24*time.Hour)
+	}
+
+	return creature, nil
+}
+
+func (cfg *ApiConfig) GetCreatureDropsByCreatureId(ctx context.Context, creatureID int32) ([]database.CreatureDrop, error) {
+	cacheKey := fmt.Sprintf("creature_drops:creature:%d", creatureID)
+
+	cached, err := cfg.Redis.Get(ctx, cacheKey).Result()
+	if err == nil {
+		var drops []database.CreatureDrop
+		if json.Unmarshal([]byte(cached), &drops) == nil {
+			return drops, nil
+		}
+	}
+
+	drops, err := cfg.DB.GetCreatureDropsByCreatureId(ctx, creatureID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(drops); err == nil {
+		cfg.Redis.Set(ctx, cacheKey, data, 24*time.Hour)
+	}
+
+	return drops, nil
+}
@@ -0,0 +1,58 @@
+package api
+
+import "testing"
+
+func TestCombatDamage(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		level  int32
+		want   int32
+	}{
+		{name: "melee level 1", action: "MELEE", level: 1, want: 5},
+		{name: "archery level 1", action: "ARCHERY", level: 1, want: 4},
+		{name: "melee level 5", action: "MELEE", level: 5, want: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CombatDamage(tt.action, tt.level); got != tt.want {
+				t.Errorf("CombatDamage(%s, %d) = %d, want %d", tt.action, tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDamageTaken(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		damage int32
+		want   int32
+	}{
+		{name: "melee takes full damage", action: "MELEE", damage: 4, want: 4},
+		{name: "archery halves damage", action: "ARCHERY", damage: 4, want: 2},
+		{name: "archery rounds up", action: "ARCHERY", damage: 3, want: 2},
+		{name: "archery never avoids a hit", action: "ARCHERY", damage: 1, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DamageTaken(tt.action, tt.damage); got != tt.want {
+				t.Errorf("DamageTaken(%s, %d) = %d, want %d", tt.action, tt.damage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExperienceForKill(t *testing.T) {
+	if got := ExperienceForKill(1, 10); got != 10 {
+		t.Errorf("ExperienceForKill(1, 10) = %d, want 10", got)
+	}
+	if got := ExperienceForKill(2, 40); got != 80 {
+		t.Errorf("ExperienceForKill(2, 40) = %d, want 80", got)
+	}
+	if got := ExperienceForKill(1, 5); got != 10 {
+		t.Errorf("ExperienceForKill(1, 5) = %d, want 10", got)
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// contentCacheKeys match every cached copy of world content, which is
+// otherwise kept for a day because it only changes on deploy.
+var contentCacheKeys = []string{
+	"actions:all",
+	"action:*",
+	"item:*",
+	"recipes:all",
+	"recipe:*",
+	"recipe_ingredients:*",
+	"resource_node:*",
+	"resource_nodes:*",
+	"resource_node_spawn:*",
+	"resources:node:*",
+	"creature:*",
+	"creature_drops:*",
+	// Inventories are dropped too, since a sync can change what their
+	// items weigh
+	"inventory_items:inv:*",
+	"inventory:char:*",
+}
+
+// InvalidateContentCache drops every cached copy of world content so the
+// next read, on any instance, comes from the database.
+func (cfg *ApiConfig) InvalidateContentCache(ctx context.Context) error {
+	for _, pattern := range contentCacheKeys {
+		iter := cfg.Redis.Scan(ctx, 0, pattern, 100).Iterator()
+		keys := []string{}
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := cfg.Redis.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NotifyContentIdled drops the cached copies of characters a content reload
+// idled, which still show them doing what was removed, and tells their
+// owners why they stopped.
+func (cfg *ApiConfig) NotifyContentIdled(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	cfg.InvalidateActiveCharactersCache(ctx)
+
+	for _, name := range names {
+		character, err := cfg.DB.GetCharacterByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		cfg.InvalidateCharacterCache(ctx, character)
+
+		message := fmt.Sprintf("What character %s was doing no longer exists. Character %s is now idle",
+			character.Name, character.Name)
+		err = cfg.NotifyCharacterOwner(ctx, character, message, "warning")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/internal/auth"
+	"github.com/trbute/idler/server/internal/database"
+	"github.com/trbute/idler/server/internal/validation"
+)
+
+// groundCapacity is large enough that a ground pile never refuses a drop.
+const groundCapacity = 1000000
+
+type groundItem struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+}
+
+func (cfg *ApiConfig) handlePickupItem(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userId, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	type parameters struct {
+		CharacterName string `json:"character_name"`
+		ItemName      string `json:"item_name"`
+		Quantity      int32  `json:"quantity"`
+		PickupAll     bool   `json:"pickup_all"`
+	}
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to decode parameters", err)
+		return
+	}
+
+	if err := validation.ValidateCharacterName(params.CharacterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	params.ItemName = strings.TrimSpace(strings.ToUpper(params.ItemName))
+	if err := validation.ValidateItemName(params.ItemName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if !params.PickupAll && params.Quantity <= 0 {
+		respondWithError(w, http.StatusBadRequest, "Quantity must be greater than 0", nil)
+		return
+	}
+
+	char, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), params.CharacterName, userId)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve character", err)
+		}
+		return
+	}
+
+	inventory, err := cfg.GetInventoryByCharacterId(r.Context(), char.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory", err)
+		return
+	}
+
+	item, err := cfg.GetItemByName(r.Context(), params.ItemName)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Item not found", err)
+		return
+	}
+
+	ground, err := cfg.DB.GetGroundInventoryByCoordinates(r.Context(), database.GetGroundInventoryByCoordinatesParams{
+		PositionX: char.PositionX,
+		PositionY: char.PositionY,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "There is nothing on the ground here", err)
+		return
+	}
+
+	onGround, err := cfg.DB.GetInventoryItemQuantity(r.Context(), database.GetInventoryItemQuantityParams{
+		InventoryID: ground.ID,
+		ItemID:      item.ID,
+	})
+	if err != nil || onGround == 0 {
+		respondWithError(w, http.StatusBadRequest, "Item not found on the ground", err)
+		return
+	}
+
+	quantity := params.Quantity
+	if params.PickupAll {
+		quantity = onGround
+	}
+	if quantity > onGround {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("There are only %d %s on the ground", onGround, strings.Title(strings.ToLower(item.Name))), nil)
+		return
+	}
+
+	canAdd, err := cfg.CheckInventoryCapacity(r.Context(), inventory.ID, item.ID, quantity)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to check inventory capacity", err)
+		return
+	}
+	if !canAdd {
+		respondWithError(w, http.StatusBadRequest, "Not enough inventory space to pick that up", nil)
+		return
+	}
+
+	err = cfg.MoveItemIfRoom(r.Context(), ground.ID, inventory.ID, item.ID, quantity)
+	if errors.Is(err, errNoRoom) {
+		respondWithError(w, http.StatusBadRequest, "Not enough inventory space to pick that up", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to pick up item: "+err.Error(), err)
+		return
+	}
+	cfg.InventoryChanged(char)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Picked up %d %s", quantity, strings.Title(strings.ToLower(item.Name))),
+	})
+}
+
+func (cfg *ApiConfig) GetOrCreateGroundInventory(ctx context.Context, x, y int32) (database.Inventory, error) {
+	ground, err := cfg.DB.GetGroundInventoryByCoordinates(ctx, database.GetGroundInventoryByCoordinatesParams{
+		PositionX: x,
+		PositionY: y,
+	})
+	if err == nil {
+		return ground, nil
+	}
+
+	return cfg.DB.CreateGroundInventory(ctx, database.CreateGroundInventoryParams{
+		PositionX: x,
+		PositionY: y,
+		Capacity:  groundCapacity,
+	})
+}
+
+// GetGroundItems lists what is lying on the ground at a cell, read directly
+// so expired items disappear straight away.
+func (cfg *ApiConfig) GetGroundItems(ctx context.Context, x, y int32) ([]groundItem, error) {
+	ground, err := cfg.DB.GetGroundInventoryByCoordinates(ctx, database.GetGroundInventoryByCoordinatesParams{
+		PositionX: x,
+		PositionY: y,
+	})
+	if err != nil {
+		return []groundItem{}, nil
+	}
+
+	inventoryItems, err := cfg.DB.GetInventoryItemsByInventoryId(ctx, ground.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []groundItem{}
+	positions := make(map[int32]int)
+	for _, invItem := range inventoryItems {
+		if i, ok := positions[invItem.ItemID]; ok {
+			items[i].Quantity += invItem.Quantity
+			continue
+		}
+
+		item, err := cfg.GetItemById(ctx, invItem.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		positions[invItem.ItemID] = len(items)
+		items = append(items, groundItem{
+			Name:     item.Name,
+			Quantity: invItem.Quantity,
+		})
+	}
+
+	return items, nil
+}
+
+// ExpireGroundItems counts another tick against every ground item, then
+// removes those left untouched for expiryTicks and takes their weight off
+// the piles they were in. Only ticks count, so items outlast downtime.
+func (cfg *ApiConfig) ExpireGroundItems(ctx context.Context, expiryTicks int32) error {
+	return cfg.RunInTx(ctx, func(txCfg *ApiConfig) error {
+		err := txCfg.DB.TickGroundItems(ctx)
+		if err != nil {
+			return err
+		}
+
+		expired, err := txCfg.DB.DeleteExpiredGroundItems(ctx, expiryTicks)
+		if err != nil {
+			return err
+		}
+
+		weightUpdates := make(map[pgtype.UUID]int32)
+		for _, invItem := range expired {
+			item, err := txCfg.GetItemById(ctx, invItem.ItemID)
+			if err != nil {
+				return err
+			}
+			weightUpdates[invItem.InventoryID] -= item.Weight * invItem.Quantity
+		}
+
+		for inventoryID, weight := range weightUpdates {
+			err := txCfg.UpdateInventoryWeight(ctx, inventoryID, weight)
+			if err != nil {
+				return err
+			}
+			txCfg.InvalidateInventoryItemsCache(ctx, inventoryID)
+		}
+		return nil
+	})
+}
@@ -1,11 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,6 +22,8 @@ type inventoryItem struct {
 	Quantity   int32 `json:"quantity"`
 	Weight     int32 `json:"weight"`
 	TotalWeight int32 `json:"total_weight"`
+	MaxDurability int32   `json:"max_durability,omitempty"`
+	Durability    []int32 `json:"durability,omitempty"`
 }
 
 type inventoryResponse struct {
@@ -62,38 +67,46 @@ func (cfg *ApiConfig) handleGetInventory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	inventoryItems, err := cfg.GetInventoryItemsByInventoryIdCached(r.Context(), inventory.ID)
+	res, err := cfg.BuildInventoryResponse(r.Context(), inventory)
 	if err != nil {
-		respondWithError(
-			w,
-			http.StatusInternalServerError,
-			"Unable to retrieve inventory items",
-			err,
-		)
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory items", err)
 		return
 	}
 
+	respondWithJSON(w, http.StatusOK, res)
+}
+
+// BuildInventoryResponse lists an inventory's contents by item name. Tools
+// are stored one row per instance, so they are folded back into a single
+// entry listing each instance's durability.
+func (cfg *ApiConfig) BuildInventoryResponse(ctx context.Context, inventory database.Inventory) (inventoryResponse, error) {
+	inventoryItems, err := cfg.GetInventoryItemsByInventoryIdCached(ctx, inventory.ID)
+	if err != nil {
+		return inventoryResponse{}, err
+	}
+
 	items := map[string]inventoryItem{}
 	for _, item := range inventoryItems {
-		itemData, err := cfg.GetItemById(r.Context(), item.ItemID)
+		itemData, err := cfg.GetItemById(ctx, item.ItemID)
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve item", err)
-			return
+			return inventoryResponse{}, err
 		}
-		items[itemData.Name] = inventoryItem{
-			Quantity:    item.Quantity,
-			Weight:      itemData.Weight,
-			TotalWeight: itemData.Weight * item.Quantity,
+		entry := items[itemData.Name]
+		entry.Quantity += item.Quantity
+		entry.Weight = itemData.Weight
+		entry.TotalWeight = itemData.Weight * entry.Quantity
+		if item.Durability.Valid {
+			entry.MaxDurability = itemData.MaxDurability.Int32
+			entry.Durability = append(entry.Durability, item.Durability.Int32)
 		}
+		items[itemData.Name] = entry
 	}
 
-	res := inventoryResponse{
+	return inventoryResponse{
 		Items:    items,
 		Weight:   inventory.Weight,
 		Capacity: inventory.Capacity,
-	}
-
-	respondWithJSON(w, http.StatusOK, res)
+	}, nil
 }
 
 func (cfg *ApiConfig) handleDropItem(w http.ResponseWriter, r *http.Request) {
@@ -170,22 +183,29 @@ func (cfg *ApiConfig) handleDropItem(w http.ResponseWriter, r *http.Request) {
 			InventoryID: inventory.ID,
 			ItemID:      item.ID,
 		})
-		if err != nil {
+		if err != nil || currentQuantity == 0 {
 			respondWithError(w, http.StatusBadRequest, "Item not found in inventory", err)
 			return
 		}
 		quantityToDrop = currentQuantity
-		messageStr = fmt.Sprintf("Dropped all %d %s", quantityToDrop, strings.Title(strings.ToLower(item.Name)))
+		messageStr = fmt.Sprintf("Dropped all %d %s on the ground", quantityToDrop, strings.Title(strings.ToLower(item.Name)))
 	} else {
 		quantityToDrop = params.Quantity
-		messageStr = fmt.Sprintf("Dropped %d %s", quantityToDrop, strings.Title(strings.ToLower(item.Name)))
+		messageStr = fmt.Sprintf("Dropped %d %s on the ground", quantityToDrop, strings.Title(strings.ToLower(item.Name)))
+	}
+
+	ground, err := cfg.GetOrCreateGroundInventory(r.Context(), char.PositionX, char.PositionY)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve ground", err)
+		return
 	}
 
-	err = cfg.DropItemFromInventory(r.Context(), inventory.ID, item.ID, quantityToDrop)
+	err = cfg.MoveItemBetweenInventories(r.Context(), inventory.ID, ground.ID, item.ID, quantityToDrop)
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "Unable to drop item: "+err.Error(), err)
 		return
 	}
+	cfg.InventoryChanged(char)
 
 	respondWithJSON(w, http.StatusOK, map[string]interface{}{
 		"message": messageStr,
@@ -243,7 +263,7 @@ func (cfg *ApiConfig) GetInventoryItemsByInventoryIdCached(ctx context.Context,
 
 func (cfg *ApiConfig) InvalidateInventoryItemsCache(ctx context.Context, inventoryID pgtype.UUID) {
 	cacheKey := fmt.Sprintf("inventory_items:inv:%s", inventoryID.String())
-	cfg.Redis.Del(ctx, cacheKey)
+	cfg.invalidateCache(ctx, cacheKey)
 }
 
 type InventoryUpdate struct {
@@ -252,52 +272,164 @@ type InventoryUpdate struct {
 	Quantity    int32
 }
 
-func (cfg *ApiConfig) BatchAddItemsToInventory(ctx context.Context, updates []InventoryUpdate) error {
+// BatchAddItemsToInventory applies a tick's inventory changes and returns
+// the ones that went through. Additions are cut down to whatever still fits
+// and the character is idled once its inventory is full.
+func (cfg *ApiConfig) BatchAddItemsToInventory(ctx context.Context, updates []InventoryUpdate) ([]InventoryUpdate, error) {
 	if len(updates) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	var validUpdates []InventoryUpdate
+	// Postgres rejects an upsert that touches the same row twice, so
+	// combine updates for the same inventory and item first
+	type inventoryItemKey struct {
+		inventoryID pgtype.UUID
+		itemID      int32
+	}
+	merged := make(map[inventoryItemKey]int)
+	var combined []InventoryUpdate
 	for _, update := range updates {
-		canAdd, err := cfg.CheckInventoryCapacity(ctx, update.InventoryID, update.ItemID, update.Quantity)
+		key := inventoryItemKey{update.InventoryID, update.ItemID}
+		if i, ok := merged[key]; ok {
+			combined[i].Quantity += update.Quantity
+			continue
+		}
+		merged[key] = len(combined)
+		combined = append(combined, update)
+	}
+
+	// Removals go first so the space they free counts toward additions
+	sort.SliceStable(combined, func(i, j int) bool {
+		return combined[i].Quantity < 0 && combined[j].Quantity >= 0
+	})
+
+	// Capacity is tracked across the whole batch, so several updates to one
+	// inventory can't each fit on their own and overfill it together
+	freeWeight := make(map[pgtype.UUID]int32)
+	full := make(map[pgtype.UUID]bool)
+
+	var validUpdates []InventoryUpdate
+	var instanceUpdates []InventoryUpdate
+	removedFrom := make(map[pgtype.UUID]bool)
+	for _, update := range combined {
+		item, err := cfg.GetItemById(ctx, update.ItemID)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if _, ok := freeWeight[update.InventoryID]; !ok {
+			inventory, err := cfg.DB.GetInventory(ctx, update.InventoryID)
+			if err != nil {
+				return nil, err
+			}
+			freeWeight[update.InventoryID] = inventory.Capacity - inventory.Weight
+		}
+		if item.MaxDurability.Valid && update.Quantity < 0 {
+			err := cfg.removeItemInstances(ctx, update.InventoryID, update.ItemID, -update.Quantity)
+			if err != nil {
+				return nil, err
+			}
+			freeWeight[update.InventoryID] -= item.Weight * update.Quantity
+			instanceUpdates = append(instanceUpdates, update)
+			continue
 		}
-		if !canAdd {
-			cfg.SendInventoryFullNotification(ctx, update.InventoryID)
+		if update.Quantity < 0 {
+			// Removals always fit, they consume crafting ingredients the
+			// tick has already checked and locked
+			removedFrom[update.InventoryID] = true
+			freeWeight[update.InventoryID] -= item.Weight * update.Quantity
+			validUpdates = append(validUpdates, update)
+			continue
+		}
+
+		// Take as much as fits and idle the character if anything is left over
+		fits := FitQuantity(freeWeight[update.InventoryID], item.Weight, update.Quantity)
+		if fits < update.Quantity && !full[update.InventoryID] {
+			full[update.InventoryID] = true
+			err := cfg.SendInventoryFullNotification(ctx, update.InventoryID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if fits == 0 {
+			continue
+		}
+		update.Quantity = fits
+		freeWeight[update.InventoryID] -= item.Weight * fits
+
+		if item.MaxDurability.Valid {
+			instanceUpdates = append(instanceUpdates, update)
 			continue
 		}
 		validUpdates = append(validUpdates, update)
 	}
 
-	if len(validUpdates) == 0 {
-		return nil
+	if len(validUpdates) == 0 && len(instanceUpdates) == 0 {
+		return nil, nil
 	}
 
-	inventoryIDs := make([]pgtype.UUID, len(validUpdates))
-	itemIDs := make([]int32, len(validUpdates))
-	quantities := make([]int32, len(validUpdates))
+	if len(validUpdates) > 0 {
+		inventoryIDs := make([]pgtype.UUID, len(validUpdates))
+		itemIDs := make([]int32, len(validUpdates))
+		quantities := make([]int32, len(validUpdates))
 
-	for i, update := range validUpdates {
-		inventoryIDs[i] = update.InventoryID
-		itemIDs[i] = update.ItemID
-		quantities[i] = update.Quantity
+		for i, update := range validUpdates {
+			inventoryIDs[i] = update.InventoryID
+			itemIDs[i] = update.ItemID
+			quantities[i] = update.Quantity
+		}
+
+		err := cfg.DB.BatchAddItemsToInventory(ctx, database.BatchAddItemsToInventoryParams{
+			Column1: inventoryIDs,
+			Column2: itemIDs,
+			Column3: quantities,
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	err := cfg.DB.BatchAddItemsToInventory(ctx, database.BatchAddItemsToInventoryParams{
-		Column1: inventoryIDs,
-		Column2: itemIDs,
-		Column3: quantities,
-	})
-	if err != nil {
-		return err
+	// Items with durability get one row per instance, starting out unworn
+	var instanceInventoryIDs []pgtype.UUID
+	var instanceItemIDs []int32
+	var instanceDurabilities []int32
+	for _, update := range instanceUpdates {
+		if update.Quantity < 0 {
+			continue
+		}
+		item, err := cfg.GetItemById(ctx, update.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		for i := int32(0); i < update.Quantity; i++ {
+			instanceInventoryIDs = append(instanceInventoryIDs, update.InventoryID)
+			instanceItemIDs = append(instanceItemIDs, update.ItemID)
+			instanceDurabilities = append(instanceDurabilities, item.MaxDurability.Int32)
+		}
+	}
+	if len(instanceItemIDs) > 0 {
+		err := cfg.DB.BatchAddItemInstancesToInventory(ctx, database.BatchAddItemInstancesToInventoryParams{
+			InventoryIds: instanceInventoryIDs,
+			ItemIds:      instanceItemIDs,
+			Durabilities: instanceDurabilities,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	validUpdates = append(validUpdates, instanceUpdates...)
+
+	for inventoryID := range removedFrom {
+		err := cfg.DB.DeleteEmptyInventoryItems(ctx, inventoryID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	inventoryWeightUpdates := make(map[pgtype.UUID]int32)
 	for _, update := range validUpdates {
 		item, err := cfg.GetItemById(ctx, update.ItemID)
 		if err != nil {
-			continue
+			return nil, err
 		}
 		weightToAdd := item.Weight * update.Quantity
 		inventoryWeightUpdates[update.InventoryID] += weightToAdd
@@ -306,27 +438,36 @@ func (cfg *ApiConfig) BatchAddItemsToInventory(ctx context.Context, updates []In
 	for inventoryID, weightToAdd := range inventoryWeightUpdates {
 		err := cfg.UpdateInventoryWeight(ctx, inventoryID, weightToAdd)
 		if err != nil {
-			log.Printf("Error updating inventory weight for %s: %v", inventoryID, err)
+			return nil, err
 		}
 		// Invalidate inventory items cache since items were added
 		cfg.InvalidateInventoryItemsCache(ctx, inventoryID)
 	}
 
-	return nil
+	err := cfg.recordCharacterGains(ctx, validUpdates)
+	if err != nil {
+		return nil, err
+	}
+
+	return validUpdates, nil
 }
 
-func (cfg *ApiConfig) GetBestToolForType(ctx context.Context, characterID pgtype.UUID, toolTypeID int32, minTier int32) (*database.Item, int32, error) {
+// GetBestToolForType returns the highest tier tool of the given type the
+// character carries, along with the instance that should take wear. Among
+// instances of the same tier the most worn one is used first.
+func (cfg *ApiConfig) GetBestToolForType(ctx context.Context, characterID pgtype.UUID, toolTypeID int32, minTier int32) (*database.Item, *database.InventoryItem, error) {
 	inventory, err := cfg.GetInventoryByCharacterId(ctx, characterID)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	inventoryItems, err := cfg.DB.GetInventoryItemsByInventoryId(ctx, inventory.ID)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	var bestTool *database.Item
+	var bestInstance *database.InventoryItem
 	var bestTier int32 = 0
 
 	for _, invItem := range inventoryItems {
@@ -336,25 +477,32 @@ func (cfg *ApiConfig) GetBestToolForType(ctx context.Context, characterID pgtype
 				continue
 			}
 
-			if item.ToolTypeID.Valid && item.ToolTypeID.Int32 == toolTypeID {
-				toolType, err := cfg.DB.GetToolTypeById(ctx, item.ToolTypeID.Int32)
-				if err != nil {
-					continue
-				}
-
-				if toolType.Tier >= minTier && toolType.Tier > bestTier {
+			if item.ToolTypeID.Valid && item.ToolTypeID.Int32 == toolTypeID && item.ToolTier >= minTier {
+				moreWorn := bestInstance != nil && item.ToolTier == bestTier &&
+					invItem.Durability.Valid && invItem.Durability.Int32 < bestInstance.Durability.Int32
+				if item.ToolTier > bestTier || moreWorn {
 					bestTool = &item
-					bestTier = toolType.Tier
+					bestInstance = &invItem
+					bestTier = item.ToolTier
 				}
 			}
 		}
 	}
 
 	if bestTool == nil {
-		return nil, 0, nil
+		return nil, nil, nil
 	}
 
-	return bestTool, bestTier, nil
+	return bestTool, bestInstance, nil
+}
+
+// GatherYield returns how many items a gathering tick grants. Every tier a
+// tool sits above the node's minimum adds one more item per tick.
+func GatherYield(toolTier int32, minToolTier int32) int32 {
+	if toolTier <= minToolTier {
+		return 1
+	}
+	return 1 + toolTier - minToolTier
 }
 
 func (cfg *ApiConfig) CheckInventoryCapacity(ctx context.Context, inventoryID pgtype.UUID, itemID int32, quantity int32) (bool, error) {
@@ -374,6 +522,18 @@ func (cfg *ApiConfig) CheckInventoryCapacity(ctx context.Context, inventoryID pg
 	return newWeight <= inventory.Capacity, nil
 }
 
+// FitQuantity returns how many of an item weighing weight each fit in
+// freeWeight, up to quantity.
+func FitQuantity(freeWeight int32, weight int32, quantity int32) int32 {
+	if weight <= 0 {
+		return quantity
+	}
+	if freeWeight <= 0 {
+		return 0
+	}
+	return min(quantity, freeWeight/weight)
+}
+
 func (cfg *ApiConfig) UpdateInventoryWeight(ctx context.Context, inventoryID pgtype.UUID, weightToAdd int32) error {
 	err := cfg.DB.UpdateInventoryWeight(ctx, database.UpdateInventoryWeightParams{
 		ID:     inventoryID,
@@ -390,40 +550,184 @@ func (cfg *ApiConfig) UpdateInventoryWeight(ctx context.Context, inventoryID pgt
 
 	if inventory.CharacterID.Valid {
 		cacheKey := fmt.Sprintf("inventory:char:%s", inventory.CharacterID.String())
-		cfg.Redis.Del(ctx, cacheKey)
+		cfg.invalidateCache(ctx, cacheKey)
 	}
 
 	return nil
 }
 
+// DropItemFromInventory takes items out of an inventory in their own
+// transaction.
 func (cfg *ApiConfig) DropItemFromInventory(ctx context.Context, inventoryID pgtype.UUID, itemID int32, quantity int32) error {
-	// First check if the item exists and get its current quantity
-	currentQuantity, err := cfg.DB.GetInventoryItemQuantity(ctx, database.GetInventoryItemQuantityParams{
+	return cfg.RunInTx(ctx, func(txCfg *ApiConfig) error {
+		err := txCfg.lockInventories(ctx, inventoryID)
+		if err != nil {
+			return err
+		}
+		return txCfg.removeItems(ctx, inventoryID, itemID, quantity)
+	})
+}
+
+// removeItems takes items out of an inventory. It must run in a transaction
+// holding the inventory's lock: the item rows are locked too, and a removal
+// that matched nothing fails rather than letting the caller hand out items
+// that are already gone.
+func (cfg *ApiConfig) removeItems(ctx context.Context, inventoryID pgtype.UUID, itemID int32, quantity int32) error {
+	rows, err := cfg.DB.LockInventoryItems(ctx, database.LockInventoryItemsParams{
 		InventoryID: inventoryID,
 		ItemID:      itemID,
 	})
 	if err != nil {
-		return fmt.Errorf("item not found in inventory")
+		return err
 	}
 
-	// Check if we have enough quantity to drop
+	var currentQuantity int32
+	for _, row := range rows {
+		currentQuantity += row.Quantity
+	}
+	if currentQuantity == 0 {
+		return fmt.Errorf("item not found in inventory")
+	}
 	if currentQuantity < quantity {
 		return fmt.Errorf("insufficient quantity: have %d, trying to drop %d", currentQuantity, quantity)
 	}
 
-	// Proceed with the removal
-	err = cfg.DB.RemoveItemsFromInventory(ctx, database.RemoveItemsFromInventoryParams{
+	item, err := cfg.GetItemById(ctx, itemID)
+	if err != nil {
+		return err
+	}
+
+	if item.MaxDurability.Valid {
+		err = cfg.removeItemInstances(ctx, inventoryID, itemID, quantity)
+		if err != nil {
+			return fmt.Errorf("failed to remove items from inventory: %v", err)
+		}
+	} else {
+		removed, err := cfg.DB.RemoveItemsFromInventory(ctx, database.RemoveItemsFromInventoryParams{
+			InventoryID: inventoryID,
+			ItemID:      itemID,
+			Quantity:    quantity,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove items from inventory: %v", err)
+		}
+		if removed == 0 {
+			return fmt.Errorf("insufficient quantity: have %d, trying to drop %d", currentQuantity, quantity)
+		}
+	}
+
+	err = cfg.DB.DeleteEmptyInventoryItems(ctx, inventoryID)
+	if err != nil {
+		return fmt.Errorf("failed to clean up empty inventory items: %v", err)
+	}
+
+	err = cfg.UpdateInventoryWeight(ctx, inventoryID, -(item.Weight * quantity))
+	if err != nil {
+		return fmt.Errorf("failed to update inventory weight: %v", err)
+	}
+
+	cfg.InvalidateInventoryItemsCache(ctx, inventoryID)
+	return nil
+}
+
+// lockInventories locks inventory rows for the rest of the transaction, in
+// a fixed order so two moves in opposite directions can't deadlock.
+func (cfg *ApiConfig) lockInventories(ctx context.Context, ids ...pgtype.UUID) error {
+	sorted := slices.Clone(ids)
+	slices.SortFunc(sorted, func(a, b pgtype.UUID) int {
+		return bytes.Compare(a.Bytes[:], b.Bytes[:])
+	})
+	sorted = slices.Compact(sorted)
+
+	for _, id := range sorted {
+		_, err := cfg.DB.LockInventory(ctx, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeItemInstances deletes quantity instances of an item that tracks
+// durability, most worn first.
+func (cfg *ApiConfig) removeItemInstances(ctx context.Context, inventoryID pgtype.UUID, itemID int32, quantity int32) error {
+	ids, err := cfg.pickItemInstances(ctx, inventoryID, itemID, quantity)
+	if err != nil {
+		return err
+	}
+
+	return cfg.DB.DeleteInventoryItemsById(ctx, ids)
+}
+
+// pickItemInstances locks an item's instances in an inventory and picks the
+// most worn, so it must run in a transaction.
+func (cfg *ApiConfig) pickItemInstances(ctx context.Context, inventoryID pgtype.UUID, itemID int32, quantity int32) ([]pgtype.UUID, error) {
+	instances, err := cfg.DB.LockInventoryItems(ctx, database.LockInventoryItemsParams{
 		InventoryID: inventoryID,
 		ItemID:      itemID,
-		Quantity:    quantity,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to remove items from inventory: %v", err)
+		return nil, err
 	}
 
-	err = cfg.DB.DeleteEmptyInventoryItems(ctx, inventoryID)
+	if int32(len(instances)) < quantity {
+		return nil, fmt.Errorf("insufficient quantity: have %d, trying to remove %d", len(instances), quantity)
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Durability.Int32 < instances[j].Durability.Int32
+	})
+
+	ids := make([]pgtype.UUID, quantity)
+	for i := range ids {
+		ids[i] = instances[i].ID
+	}
+
+	return ids, nil
+}
+
+// MoveItemBetweenInventories takes items out of one inventory and puts them
+// in another in one transaction, so the items are never in both or lost
+// when requests race. Capacity of the destination is left to the caller.
+func (cfg *ApiConfig) MoveItemBetweenInventories(ctx context.Context, fromID pgtype.UUID, toID pgtype.UUID, itemID int32, quantity int32) error {
+	return cfg.RunInTx(ctx, func(txCfg *ApiConfig) error {
+		return txCfg.moveItems(ctx, fromID, toID, itemID, quantity)
+	})
+}
+
+// errNoRoom is returned by MoveItemIfRoom when the destination can't hold
+// the items.
+var errNoRoom = errors.New("not enough room")
+
+// MoveItemIfRoom moves items like MoveItemBetweenInventories, but checks
+// the destination's capacity under the same lock, so two moves racing into
+// one inventory can't overfill it.
+func (cfg *ApiConfig) MoveItemIfRoom(ctx context.Context, fromID pgtype.UUID, toID pgtype.UUID, itemID int32, quantity int32) error {
+	return cfg.RunInTx(ctx, func(txCfg *ApiConfig) error {
+		err := txCfg.moveItems(ctx, fromID, toID, itemID, quantity)
+		if err != nil {
+			return err
+		}
+
+		to, err := txCfg.DB.GetInventory(ctx, toID)
+		if err != nil {
+			return err
+		}
+		if to.Weight > to.Capacity {
+			return errNoRoom
+		}
+		return nil
+	})
+}
+
+// moveItems moves items between inventories inside the caller's
+// transaction, keeping both weights in step. Both inventories are locked
+// first. Tools move as the same instances so their durability comes with
+// them.
+func (cfg *ApiConfig) moveItems(ctx context.Context, fromID pgtype.UUID, toID pgtype.UUID, itemID int32, quantity int32) error {
+	err := cfg.lockInventories(ctx, fromID, toID)
 	if err != nil {
-		log.Printf("Error cleaning up empty inventory items: %v", err)
+		return err
 	}
 
 	item, err := cfg.GetItemById(ctx, itemID)
@@ -431,38 +735,151 @@ func (cfg *ApiConfig) DropItemFromInventory(ctx context.Context, inventoryID pgt
 		return err
 	}
 
-	weightToRemove := -(item.Weight * quantity)
-	err = cfg.UpdateInventoryWeight(ctx, inventoryID, weightToRemove)
+	if !item.MaxDurability.Valid {
+		err = cfg.removeItems(ctx, fromID, itemID, quantity)
+		if err != nil {
+			return err
+		}
+
+		_, err = cfg.DB.AddItemsToInventory(ctx, database.AddItemsToInventoryParams{
+			InventoryID: toID,
+			ItemID:      itemID,
+			Quantity:    quantity,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add items to inventory: %v", err)
+		}
+	} else {
+		ids, err := cfg.pickItemInstances(ctx, fromID, itemID, quantity)
+		if err != nil {
+			return err
+		}
+
+		err = cfg.DB.MoveInventoryItemsById(ctx, database.MoveInventoryItemsByIdParams{
+			InventoryID: toID,
+			Ids:         ids,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to move items between inventories: %v", err)
+		}
+
+		err = cfg.UpdateInventoryWeight(ctx, fromID, -(item.Weight * quantity))
+		if err != nil {
+			return fmt.Errorf("failed to update inventory weight: %v", err)
+		}
+		cfg.InvalidateInventoryItemsCache(ctx, fromID)
+	}
+
+	err = cfg.UpdateInventoryWeight(ctx, toID, item.Weight*quantity)
+	if err != nil {
+		return fmt.Errorf("failed to update inventory weight: %v", err)
+	}
+	cfg.InvalidateInventoryItemsCache(ctx, toID)
+
+	return nil
+}
+
+type ToolWearUpdate struct {
+	CharacterID     pgtype.UUID
+	InventoryItemID pgtype.UUID
+}
+
+// BatchWearTools takes one point of durability from each tool used this
+// tick. Tools that run out are removed and their owner is set idle.
+func (cfg *ApiConfig) BatchWearTools(ctx context.Context, updates []ToolWearUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	ids := make([]pgtype.UUID, len(updates))
+	owners := make(map[pgtype.UUID]pgtype.UUID, len(updates))
+	for i, update := range updates {
+		ids[i] = update.InventoryItemID
+		owners[update.InventoryItemID] = update.CharacterID
+	}
+
+	worn, err := cfg.DB.BatchWearInventoryItems(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var broken []database.InventoryItem
+	for _, invItem := range worn {
+		cfg.InvalidateInventoryItemsCache(ctx, invItem.InventoryID)
+		if invItem.Durability.Int32 <= 0 {
+			broken = append(broken, invItem)
+		}
+	}
+
+	if len(broken) == 0 {
+		return nil
+	}
+
+	brokenIDs := make([]pgtype.UUID, len(broken))
+	for i, invItem := range broken {
+		brokenIDs[i] = invItem.ID
+	}
+
+	err = cfg.DB.DeleteInventoryItemsById(ctx, brokenIDs)
 	if err != nil {
-		log.Printf("Error updating inventory weight after drop: %v", err)
+		return err
 	}
 
-	// Invalidate inventory items cache since items were dropped
-	cfg.InvalidateInventoryItemsCache(ctx, inventoryID)
+	for _, invItem := range broken {
+		item, err := cfg.GetItemById(ctx, invItem.ItemID)
+		if err != nil {
+			return err
+		}
+
+		err = cfg.UpdateInventoryWeight(ctx, invItem.InventoryID, -item.Weight)
+		if err != nil {
+			return err
+		}
+
+		character, err := cfg.GetCharacterById(ctx, owners[invItem.ID])
+		if err != nil {
+			return err
+		}
+
+		err = cfg.SetCharacterToIdle(ctx, character.ID)
+		if err != nil {
+			return err
+		}
+		cfg.InventoryChanged(character)
+
+		message := fmt.Sprintf("Character %s's %s broke! Character set to idle.", character.Name, item.Name)
+		err = cfg.NotifyCharacterOwner(ctx, character, message, "warning")
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (cfg *ApiConfig) SendInventoryFullNotification(ctx context.Context, inventoryID pgtype.UUID) {
+func (cfg *ApiConfig) SendInventoryFullNotification(ctx context.Context, inventoryID pgtype.UUID) error {
 	inventory, err := cfg.DB.GetInventory(ctx, inventoryID)
 	if err != nil {
-		return
+		return err
 	}
 
 	if !inventory.CharacterID.Valid {
-		return
+		return nil
 	}
 
 	character, err := cfg.GetCharacterById(ctx, inventory.CharacterID)
 	if err != nil {
-		return
+		return err
 	}
 
 	err = cfg.SetCharacterToIdle(ctx, character.ID)
 	if err != nil {
-		log.Printf("Failed to set character %s to idle: %v", character.Name, err)
+		return err
 	}
 
-	message := fmt.Sprintf("Inventory is full for character %s! Character set to idle.", character.Name)
-	cfg.Hub.SendNotificationToUser(character.UserID.Bytes, message, "warning")
+	return cfg.NotifyCharacterOwner(ctx, character, InventoryFullMessage(character.Name), "warning")
+}
+
+func InventoryFullMessage(characterName string) string {
+	return fmt.Sprintf("Inventory is full for character %s! Character set to idle.", characterName)
 }
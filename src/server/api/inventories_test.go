@@ -127,4 +127,52 @@ func TestInventoryValidationIntegration(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestGatherYield(t *testing.T) {
+	tests := []struct {
+		name        string
+		toolTier    int32
+		minToolTier int32
+		want        int32
+	}{
+		{name: "tool at minimum tier", toolTier: 1, minToolTier: 1, want: 1},
+		{name: "tool one tier above", toolTier: 2, minToolTier: 1, want: 2},
+		{name: "tool two tiers above", toolTier: 3, minToolTier: 1, want: 3},
+		{name: "no minimum tier", toolTier: 2, minToolTier: 0, want: 3},
+		{name: "tool below minimum", toolTier: 0, minToolTier: 1, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GatherYield(tt.toolTier, tt.minToolTier); got != tt.want {
+				t.Errorf("GatherYield(%d, %d) = %d, want %d", tt.toolTier, tt.minToolTier, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitQuantity(t *testing.T) {
+	tests := []struct {
+		name       string
+		freeWeight int32
+		weight     int32
+		quantity   int32
+		want       int32
+	}{
+		{name: "everything fits", freeWeight: 10, weight: 2, quantity: 3, want: 3},
+		{name: "partial fill", freeWeight: 5, weight: 2, quantity: 3, want: 2},
+		{name: "exactly full", freeWeight: 6, weight: 2, quantity: 3, want: 3},
+		{name: "no room", freeWeight: 1, weight: 2, quantity: 3, want: 0},
+		{name: "over capacity", freeWeight: -4, weight: 2, quantity: 3, want: 0},
+		{name: "weightless", freeWeight: 0, weight: 0, quantity: 3, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FitQuantity(tt.freeWeight, tt.weight, tt.quantity); got != tt.want {
+				t.Errorf("FitQuantity(%d, %d, %d) = %d, want %d", tt.freeWeight, tt.weight, tt.quantity, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 	"time"
 
@@ -126,6 +127,11 @@ func (cfg *ApiConfig) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = cfg.PrepareAwaySummary(r.Context(), userid)
+	if err != nil {
+		log.Printf("Failed to prepare away summary for user %s: %v", userid, err)
+	}
+
 	respondWithJSON(w, http.StatusOK, response{
 		User: User{
 			ID:        user.ID,
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/internal/auth"
+	"github.com/trbute/idler/server/internal/database"
+	"github.com/trbute/idler/server/internal/validation"
+)
+
+type RecipeIngredient struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+}
+
+type Recipe struct {
+	Name        string             `json:"name"`
+	ItemName    string             `json:"item_name"`
+	Quantity    int32              `json:"quantity"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+}
+
+func (cfg *ApiConfig) handleGetRecipes(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	_, err = auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 500*time.Millisecond)
+	defer cancel()
+
+	const recipesCacheKey = "recipes:all"
+	cachedData, err := cfg.Redis.Get(ctx, recipesCacheKey).Bytes()
+	if err == nil {
+		var recipeResponse []Recipe
+		if err := json.Unmarshal(cachedData, &recipeResponse); err == nil {
+			respondWithJSON(w, http.StatusOK, recipeResponse)
+			return
+		}
+	}
+
+	recipes, err := cfg.DB.GetAllRecipes(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve recipes", err)
+		return
+	}
+
+	recipeResponse := []Recipe{}
+	for _, recipe := range recipes {
+		item, err := cfg.GetItemById(r.Context(), recipe.ItemID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve recipe item", err)
+			return
+		}
+
+		ingredients, err := cfg.GetRecipeIngredientsByRecipeId(r.Context(), recipe.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve recipe ingredients", err)
+			return
+		}
+
+		ingredientResponse := []RecipeIngredient{}
+		for _, ingredient := range ingredients {
+			ingredientItem, err := cfg.GetItemById(r.Context(), ingredient.ItemID)
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, "Unable to retrieve ingredient item", err)
+				return
+			}
+			ingredientResponse = append(ingredientResponse, RecipeIngredient{
+				Name:     ingredientItem.Name,
+				Quantity: ingredient.Quantity,
+			})
+		}
+
+		recipeResponse = append(recipeResponse, Recipe{
+			Name:        recipe.Name,
+			ItemName:    item.Name,
+			Quantity:    recipe.Quantity,
+			Ingredients: ingredientResponse,
+		})
+	}
+
+	if jsonData, err := json.Marshal(recipeResponse); err == nil {
+		err = cfg.Redis.Set(ctx, recipesCacheKey, jsonData, 24*time.Hour).Err()
+		if err != nil {
+			log.Printf("Failed to cache recipes: %v", err)
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, recipeResponse)
+}
+
+func (cfg *ApiConfig) handleCraft(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	characterName := r.PathValue("character")
+	if err := validation.ValidateCharacterName(characterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	type parameters struct {
+		Recipe string `json:"recipe"`
+		Amount *int   `json:"amount,omitempty"`
+	}
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to decode parameters", err)
+		return
+	}
+
+	params.Recipe = strings.TrimSpace(strings.ToUpper(params.Recipe))
+	if err := validation.ValidateTarget(params.Recipe); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := validation.ValidateAmount(params.Amount); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	character, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), characterName, userID)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusNotFound, "Character not found", err)
+		}
+		return
+	}
+
+	if character.DestinationX.Valid {
+		respondWithError(w, http.StatusBadRequest, "Character can't craft while traveling", nil)
+		return
+	}
+
+	recipe, err := cfg.DB.GetRecipeByName(r.Context(), params.Recipe)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Recipe not found", err)
+		return
+	}
+
+	ingredients, err := cfg.GetRecipeIngredientsByRecipeId(r.Context(), recipe.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve recipe ingredients", err)
+		return
+	}
+
+	inventory, err := cfg.GetInventoryByCharacterId(r.Context(), character.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory", err)
+		return
+	}
+
+	missing, err := cfg.FindMissingIngredient(r.Context(), inventory.ID, ingredients)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to check ingredients", err)
+		return
+	}
+	if missing != nil {
+		item, err := cfg.GetItemById(r.Context(), missing.ItemID)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Missing ingredients for recipe", err)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("You need %d %s to craft this", missing.Quantity, item.Name), nil)
+		return
+	}
+
+	action, err := cfg.GetActionByName(r.Context(), "CRAFTING")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to get crafting action", err)
+		return
+	}
+
+	var amountLimit pgtype.Int4
+	if params.Amount != nil && *params.Amount > 0 {
+		amountLimit = pgtype.Int4{Int32: int32(*params.Amount), Valid: true}
+	} else {
+		amountLimit = pgtype.Int4{Valid: false}
+	}
+
+	char, err := cfg.DB.StartCharacterCrafting(r.Context(), database.StartCharacterCraftingParams{
+		ActionID:          action.ID,
+		ActionRecipeID:    pgtype.Int4{Int32: recipe.ID, Valid: true},
+		ActionAmountLimit: amountLimit,
+		ID:                character.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Character update failed", err)
+		return
+	}
+
+	cfg.InvalidateActiveCharactersCache(r.Context())
+	cfg.InvalidateCharacterCache(r.Context(), char)
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"name":        char.Name,
+		"action_id":   char.ActionID,
+		"action_name": action.Name,
+		"recipe":      recipe.Name,
+	})
+}
+
+// FindMissingIngredient returns the first ingredient the inventory can't cover
+// for a single craft, or nil when every ingredient is present.
+func (cfg *ApiConfig) FindMissingIngredient(ctx context.Context, inventoryID pgtype.UUID, ingredients []database.RecipeIngredient) (*database.RecipeIngredient, error) {
+	inventoryItems, err := cfg.GetInventoryItemsByInventoryIdCached(ctx, inventoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[int32]int32)
+	for _, item := range inventoryItems {
+		quantities[item.ItemID] += item.Quantity
+	}
+
+	for _, ingredient := range ingredients {
+		if quantities[ingredient.ItemID] < ingredient.Quantity {
+			return &ingredient, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (cfg *ApiConfig) GetRecipeById(ctx context.Context, recipeID int32) (database.Recipe, error) {
+	cacheKey := fmt.Sprintf("recipe:%d", recipeID)
+
+	cached, err := cfg.Redis.Get(ctx, cacheKey).Result()
+	if err == nil {
+		var recipe database.Recipe
+		if json.Unmarshal([]byte(cached), &recipe) == nil {
+			return recipe, nil
+		}
+	}
+
+	recipe, err := cfg.DB.GetRecipeById(ctx, recipeID)
+	if err != nil {
+		return database.Recipe{}, err
+	}
+
+	if data, err := json.Marshal(recipe); err == nil {
+		cfg.Redis.Set(ctx, cacheKey, data, 24*time.Hour)
+	}
+
+	return recipe, nil
+}
+
+func (cfg *ApiConfig) GetRecipeIngredientsByRecipeId(ctx context.Context, recipeID int32) ([]database.RecipeIngredient, error) {
+	cacheKey := fmt.Sprintf("recipe_ingredients:recipe:%d", recipeID)
+
+	cached, err := cfg.Redis.Get(ctx, cacheKey).Result()
+	if err == nil {
+		var ingredients []database.RecipeIngredient
+		if json.Unmarshal([]byte(cached), &ingredients) == nil {
+			return ingredients, nil
+		}
+	}
+
+	ingredients, err := cfg.DB.GetRecipeIngredientsByRecipeId(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(ingredients); err == nil {
+		cfg.Redis.Set(ctx, cacheKey, data, 24*time.Hour)
+	}
+
+	return ingredients, nil
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/trbute/idler/server/internal/auth"
@@ -11,13 +12,35 @@ type charData struct {
 	CharacterName string `json:"character_name"`
 	ActionName    string `json:"action_name"`
 	ActionTarget  string `json:"action_target"`
+	HP            int32  `json:"hp"`
+	MaxHP         int32  `json:"max_hp"`
+	// ActionTicks counts toward IntervalTicks, when the next drop comes
+	ActionTicks   int32  `json:"action_ticks,omitempty"`
+	IntervalTicks int32  `json:"interval_ticks,omitempty"`
+}
+
+type nodeData struct {
+	Name string `json:"name"`
+	// Remaining and Amount are left out for nodes that never deplete
+	Remaining        *int32 `json:"remaining,omitempty"`
+	Amount           *int32 `json:"amount,omitempty"`
+	RespawnTicksLeft int32  `json:"respawn_ticks_left,omitempty"`
+}
+
+type creatureData struct {
+	Name             string `json:"name"`
+	HP               int32  `json:"hp"`
+	MaxHP            int32  `json:"max_hp"`
+	RespawnTicksLeft int32  `json:"respawn_ticks_left,omitempty"`
 }
 
 type area struct {
 	PositionX     int32      `json:"position_x"`
 	PositionY     int32      `json:"position_y"`
-	Characters    []charData `json:"characters"`
-	ResourceNodes []string   `json:"resource_nodes"`
+	Characters    []charData   `json:"characters"`
+	ResourceNodes []nodeData   `json:"resource_nodes"`
+	Creatures     []creatureData `json:"creatures"`
+	GroundItems   []groundItem `json:"ground_items"`
 }
 
 func (cfg *ApiConfig) handleGetArea(w http.ResponseWriter, r *http.Request) {
@@ -54,7 +77,11 @@ func (cfg *ApiConfig) handleGetArea(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resourceNodes, err := cfg.GetResourceNodeSpawnsByCoordinates(r.Context(), char.PositionX, char.PositionY)
+	// Read spawns directly, the cached rows don't follow depletion
+	resourceNodes, err := cfg.DB.GetResourceNodeSpawnsByCoordinates(r.Context(), database.GetResourceNodeSpawnsByCoordinatesParams{
+		PositionX: char.PositionX,
+		PositionY: char.PositionY,
+	})
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve resource nodes in area", err)
 		return
@@ -68,12 +95,34 @@ func (cfg *ApiConfig) handleGetArea(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		actionTarget := ""
-		if c.ActionTarget.Valid {
+		var intervalTicks int32
+		if c.DestinationX.Valid && c.DestinationY.Valid {
+			actionTarget = fmt.Sprintf("(%d, %d)", c.DestinationX.Int32, c.DestinationY.Int32)
+		} else if c.ActionRecipeID.Valid {
+			recipe, err := cfg.GetRecipeById(r.Context(), c.ActionRecipeID.Int32)
+			if err == nil {
+				actionTarget = recipe.Name
+			} else {
+				actionTarget = "Unknown Recipe"
+			}
+		} else if c.ActionCreatureTarget.Valid {
+			spawn, err := cfg.DB.GetCreatureSpawnById(r.Context(), c.ActionCreatureTarget.Int32)
+			if err == nil {
+				creature, err := cfg.GetCreatureById(r.Context(), spawn.CreatureID)
+				if err == nil {
+					actionTarget = creature.Name
+				}
+			}
+			if actionTarget == "" {
+				actionTarget = "Unknown Target"
+			}
+		} else if c.ActionTarget.Valid {
 			spawn, err := cfg.DB.GetResourceNodeSpawnById(r.Context(), c.ActionTarget.Int32)
 			if err == nil {
 				targetNode, err := cfg.GetResourceNodeById(r.Context(), spawn.NodeID)
 				if err == nil {
 					actionTarget = targetNode.Name
+					intervalTicks = targetNode.IntervalTicks
 				}
 			}
 			if actionTarget == "" {
@@ -85,23 +134,69 @@ func (cfg *ApiConfig) handleGetArea(w http.ResponseWriter, r *http.Request) {
 			CharacterName: c.Name,
 			ActionName:    action.Name,
 			ActionTarget:  actionTarget,
+			HP:            c.Hp,
+			MaxHP:         c.MaxHp,
+			ActionTicks:   c.ActionTicks,
+			IntervalTicks: intervalTicks,
 		})
 	}
 
-	nodeNames := make([]string, 0, len(resourceNodes))
+	nodes := make([]nodeData, 0, len(resourceNodes))
 	for _, node := range resourceNodes {
 		resourceNode, err := cfg.GetResourceNodeById(r.Context(), node.NodeID)
 		if err != nil {
 			continue
 		}
-		nodeNames = append(nodeNames, resourceNode.Name)
+		data := nodeData{Name: resourceNode.Name}
+		if node.Remaining.Valid {
+			data.Remaining = &node.Remaining.Int32
+			data.Amount = &resourceNode.Amount.Int32
+			if node.Remaining.Int32 == 0 {
+				data.RespawnTicksLeft = node.RespawnTicksLeft
+			}
+		}
+		nodes = append(nodes, data)
+	}
+
+	creatureSpawns, err := cfg.DB.GetCreatureSpawnsByCoordinates(r.Context(), database.GetCreatureSpawnsByCoordinatesParams{
+		PositionX: char.PositionX,
+		PositionY: char.PositionY,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve creatures in area", err)
+		return
+	}
+
+	creatures := make([]creatureData, 0, len(creatureSpawns))
+	for _, spawn := range creatureSpawns {
+		creature, err := cfg.GetCreatureById(r.Context(), spawn.CreatureID)
+		if err != nil {
+			continue
+		}
+		data := creatureData{
+			Name:  creature.Name,
+			HP:    spawn.Hp,
+			MaxHP: creature.MaxHp,
+		}
+		if spawn.Hp == 0 {
+			data.RespawnTicksLeft = spawn.RespawnTicksLeft
+		}
+		creatures = append(creatures, data)
+	}
+
+	groundItems, err := cfg.GetGroundItems(r.Context(), char.PositionX, char.PositionY)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve ground items", err)
+		return
 	}
 
 	area := area{
 		PositionX:     char.PositionX,
 		PositionY:     char.PositionY,
 		Characters:    chars,
-		ResourceNodes: nodeNames,
+		ResourceNodes: nodes,
+		Creatures:     creatures,
+		GroundItems:   groundItems,
 	}
 
 	respondWithJSON(w, http.StatusOK, area)
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/trbute/idler/server/internal/auth"
+	"github.com/trbute/idler/server/internal/validation"
+)
+
+// experiencePerTier is the XP granted for each item gathered from a node,
+// multiplied by the node's tier.
+const experiencePerTier = 10
+
+type Skill struct {
+	Name                string `json:"name"`
+	Level               int32  `json:"level"`
+	Experience          int32  `json:"experience"`
+	NextLevelExperience int32  `json:"next_level_experience"`
+}
+
+func (cfg *ApiConfig) handleGetSkills(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	characterName := r.PathValue("character")
+	if err := validation.ValidateCharacterName(characterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	character, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), characterName, userID)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusNotFound, "Character not found", err)
+		}
+		return
+	}
+
+	skills, err := cfg.DB.GetCharacterSkills(r.Context(), character.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve skills", err)
+		return
+	}
+
+	skillResponse := []Skill{}
+	for _, skill := range skills {
+		action, err := cfg.GetActionById(r.Context(), skill.ActionID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve skill action", err)
+			return
+		}
+
+		level := LevelForExperience(skill.Experience)
+		skillResponse = append(skillResponse, Skill{
+			Name:                action.Name,
+			Level:               level,
+			Experience:          skill.Experience,
+			NextLevelExperience: ExperienceForLevel(level + 1),
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, skillResponse)
+}
+
+// ExperienceForLevel returns the total XP needed to reach a level. Each
+// level costs 100 XP more than the one before it.
+func ExperienceForLevel(level int32) int32 {
+	if level <= 1 {
+		return 0
+	}
+	return 50 * level * (level - 1)
+}
+
+func LevelForExperience(experience int32) int32 {
+	level := int32(1)
+	for experience >= ExperienceForLevel(level+1) {
+		level++
+	}
+	return level
+}
+
+func ExperienceForDrop(nodeTier int32, quantity int32) int32 {
+	if nodeTier < 1 {
+		nodeTier = 1
+	}
+	return experiencePerTier * nodeTier * quantity
+}
@@ -0,0 +1,38 @@
+package api
+
+import "testing"
+
+func TestLevelForExperience(t *testing.T) {
+	tests := []struct {
+		name       string
+		experience int32
+		want       int32
+	}{
+		{name: "no experience", experience: 0, want: 1},
+		{name: "just below level 2", experience: 99, want: 1},
+		{name: "exactly level 2", experience: 100, want: 2},
+		{name: "between levels", experience: 250, want: 2},
+		{name: "exactly level 3", experience: 300, want: 3},
+		{name: "level 10", experience: 4500, want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LevelForExperience(tt.experience); got != tt.want {
+				t.Errorf("LevelForExperience(%d) = %d, want %d", tt.experience, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExperienceForDrop(t *testing.T) {
+	if got := ExperienceForDrop(1, 1); got != 10 {
+		t.Errorf("ExperienceForDrop(1, 1) = %d, want 10", got)
+	}
+	if got := ExperienceForDrop(2, 3); got != 60 {
+		t.Errorf("ExperienceForDrop(2, 3) = %d, want 60", got)
+	}
+	if got := ExperienceForDrop(0, 1); got != 10 {
+		t.Errorf("ExperienceForDrop(0, 1) = %d, want 10", got)
+	}
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/internal/auth"
+	"github.com/trbute/idler/server/internal/database"
+	"github.com/trbute/idler/server/internal/validation"
+)
+
+// stashCapacity is the weight a user's shared stash can hold.
+const stashCapacity = 500
+
+func (cfg *ApiConfig) handleGetStash(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	stash, err := cfg.GetOrCreateStash(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve stash", err)
+		return
+	}
+
+	res, err := cfg.BuildInventoryResponse(r.Context(), stash)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve stash items", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, res)
+}
+
+func (cfg *ApiConfig) handleDepositItem(w http.ResponseWriter, r *http.Request) {
+	cfg.handleStashTransfer(w, r, true)
+}
+
+func (cfg *ApiConfig) handleWithdrawItem(w http.ResponseWriter, r *http.Request) {
+	cfg.handleStashTransfer(w, r, false)
+}
+
+// handleStashTransfer moves items between a character's inventory and its
+// owner's stash, in whichever direction deposit says.
+func (cfg *ApiConfig) handleStashTransfer(w http.ResponseWriter, r *http.Request, deposit bool) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	type parameters struct {
+		CharacterName string `json:"character_name"`
+		ItemName      string `json:"item_name"`
+		Quantity      int32  `json:"quantity"`
+		All           bool   `json:"all"`
+	}
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to decode parameters", err)
+		return
+	}
+
+	if err := validation.ValidateCharacterName(params.CharacterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	params.ItemName = strings.TrimSpace(strings.ToUpper(params.ItemName))
+	if err := validation.ValidateItemName(params.ItemName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if !params.All && params.Quantity <= 0 {
+		respondWithError(w, http.StatusBadRequest, "Quantity must be greater than 0", nil)
+		return
+	}
+
+	char, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), params.CharacterName, userID)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve character", err)
+		}
+		return
+	}
+
+	inventory, err := cfg.GetInventoryByCharacterId(r.Context(), char.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory", err)
+		return
+	}
+
+	stash, err := cfg.GetOrCreateStash(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve stash", err)
+		return
+	}
+
+	item, err := cfg.GetItemByName(r.Context(), params.ItemName)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Item not found", err)
+		return
+	}
+
+	from, to := stash, inventory
+	fromName := "the stash"
+	if deposit {
+		from, to = inventory, stash
+		fromName = "inventory"
+	}
+
+	available, err := cfg.DB.GetInventoryItemQuantity(r.Context(), database.GetInventoryItemQuantityParams{
+		InventoryID: from.ID,
+		ItemID:      item.ID,
+	})
+	if err != nil || available == 0 {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Item not found in %s", fromName), err)
+		return
+	}
+
+	itemName := strings.Title(strings.ToLower(item.Name))
+	quantity := params.Quantity
+	if params.All {
+		quantity = available
+	}
+	if quantity > available {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("There are only %d %s in %s", available, itemName, fromName), nil)
+		return
+	}
+
+	canAdd, err := cfg.CheckInventoryCapacity(r.Context(), to.ID, item.ID, quantity)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to check inventory capacity", err)
+		return
+	}
+	if !canAdd {
+		if deposit {
+			respondWithError(w, http.StatusBadRequest, "Not enough stash space to deposit that", nil)
+		} else {
+			respondWithError(w, http.StatusBadRequest, "Not enough inventory space to withdraw that", nil)
+		}
+		return
+	}
+
+	err = cfg.MoveItemIfRoom(r.Context(), from.ID, to.ID, item.ID, quantity)
+	if errors.Is(err, errNoRoom) {
+		if deposit {
+			respondWithError(w, http.StatusBadRequest, "Not enough stash space to deposit that", nil)
+		} else {
+			respondWithError(w, http.StatusBadRequest, "Not enough inventory space to withdraw that", nil)
+		}
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to move item: "+err.Error(), err)
+		return
+	}
+	cfg.InventoryChanged(char)
+
+	message := fmt.Sprintf("Withdrew %d %s from the stash", quantity, itemName)
+	if deposit {
+		message = fmt.Sprintf("Deposited %d %s in the stash", quantity, itemName)
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": message,
+	})
+}
+
+// GetOrCreateStash returns the user's stash, creating it on first use.
+// Stashes aren't tied to a place, so they sit at the origin every character
+// starts from.
+func (cfg *ApiConfig) GetOrCreateStash(ctx context.Context, userID uuid.UUID) (database.Inventory, error) {
+	pgUserID := pgtype.UUID{
+		Bytes: userID,
+		Valid: true,
+	}
+
+	stash, err := cfg.DB.GetStashByUserId(ctx, pgUserID)
+	if err == nil {
+		return stash, nil
+	}
+
+	return cfg.DB.CreateStash(ctx, database.CreateStashParams{
+		UserID:   pgUserID,
+		Capacity: stashCapacity,
+	})
+}
@@ -0,0 +1,600 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/internal/auth"
+	"github.com/trbute/idler/server/internal/database"
+	"github.com/trbute/idler/server/internal/validation"
+)
+
+const (
+	tradeStatusCompleted = "COMPLETED"
+	tradeStatusCancelled = "CANCELLED"
+)
+
+type tradeOffer struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+}
+
+type tradeResponse struct {
+	Status             string                  `json:"status"`
+	Initiator          string                  `json:"initiator"`
+	Recipient          string                  `json:"recipient"`
+	InitiatorConfirmed bool                    `json:"initiator_confirmed"`
+	RecipientConfirmed bool                    `json:"recipient_confirmed"`
+	Offers             map[string][]tradeOffer `json:"offers"`
+}
+
+func (cfg *ApiConfig) handleGetTrade(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	characterName := r.PathValue("character")
+	if err := validation.ValidateCharacterName(characterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	character, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), characterName, userID)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusNotFound, "Character not found", err)
+		}
+		return
+	}
+
+	trade, err := cfg.DB.GetOpenTradeByCharacterId(r.Context(), character.ID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Character has no open trade", err)
+		return
+	}
+
+	response, err := cfg.buildTradeResponse(r.Context(), trade)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve trade", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+func (cfg *ApiConfig) handleProposeTrade(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	characterName := r.PathValue("character")
+	if err := validation.ValidateCharacterName(characterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	type parameters struct {
+		Target string `json:"target"`
+	}
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to decode parameters", err)
+		return
+	}
+
+	if err := validation.ValidateCharacterName(params.Target); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	character, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), characterName, userID)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusNotFound, "Character not found", err)
+		}
+		return
+	}
+
+	// Positions are read directly so a finished trip is never missed
+	character, err = cfg.DB.GetCharacterById(r.Context(), character.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve character", err)
+		return
+	}
+
+	target, err := cfg.DB.GetCharacterByName(r.Context(), params.Target)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Target character not found", err)
+		return
+	}
+
+	if target.ID == character.ID {
+		respondWithError(w, http.StatusBadRequest, "Character can't trade with itself", nil)
+		return
+	}
+
+	if target.PositionX != character.PositionX || target.PositionY != character.PositionY {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("%s is not at your location", target.Name), nil)
+		return
+	}
+
+	if _, err := cfg.DB.GetOpenTradeByCharacterId(r.Context(), character.ID); err == nil {
+		respondWithError(w, http.StatusBadRequest, "Character already has an open trade", nil)
+		return
+	}
+
+	if _, err := cfg.DB.GetOpenTradeByCharacterId(r.Context(), target.ID); err == nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("%s is already trading", target.Name), nil)
+		return
+	}
+
+	trade, err := cfg.DB.CreateTrade(r.Context(), database.CreateTradeParams{
+		InitiatorID: character.ID,
+		RecipientID: target.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create trade", err)
+		return
+	}
+
+	cfg.sendTradeUpdate(r.Context(), trade, fmt.Sprintf("%s wants to trade with %s", character.Name, target.Name))
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"initiator": character.Name,
+		"recipient": target.Name,
+		"status":    trade.Status,
+	})
+}
+
+func (cfg *ApiConfig) handleAddTradeItem(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	characterName := r.PathValue("character")
+	if err := validation.ValidateCharacterName(characterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	type parameters struct {
+		ItemName string `json:"item_name"`
+		Quantity int32  `json:"quantity"`
+	}
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to decode parameters", err)
+		return
+	}
+
+	params.ItemName = strings.TrimSpace(strings.ToUpper(params.ItemName))
+	if err := validation.ValidateItemName(params.ItemName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if params.Quantity <= 0 {
+		respondWithError(w, http.StatusBadRequest, "Quantity must be greater than 0", nil)
+		return
+	}
+
+	character, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), characterName, userID)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusNotFound, "Character not found", err)
+		}
+		return
+	}
+
+	trade, err := cfg.DB.GetOpenTradeByCharacterId(r.Context(), character.ID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Character has no open trade", err)
+		return
+	}
+
+	item, err := cfg.GetItemByName(r.Context(), params.ItemName)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Item not found", err)
+		return
+	}
+
+	inventory, err := cfg.GetInventoryByCharacterId(r.Context(), character.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory", err)
+		return
+	}
+
+	owned, err := cfg.DB.GetInventoryItemQuantity(r.Context(), database.GetInventoryItemQuantityParams{
+		InventoryID: inventory.ID,
+		ItemID:      item.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to check inventory", err)
+		return
+	}
+
+	tradeItems, err := cfg.DB.GetTradeItems(r.Context(), trade.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve trade items", err)
+		return
+	}
+
+	offered := params.Quantity
+	for _, tradeItem := range tradeItems {
+		if tradeItem.CharacterID == character.ID && tradeItem.ItemID == item.ID {
+			offered += tradeItem.Quantity
+		}
+	}
+
+	if offered > owned {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("You only have %d %s", owned, strings.Title(strings.ToLower(item.Name))), nil)
+		return
+	}
+
+	// Changing an offer means both sides have to look again, so the reset
+	// commits with the offer or not at all
+	err = cfg.RunInTx(r.Context(), func(txCfg *ApiConfig) error {
+		err := txCfg.DB.AddTradeItem(r.Context(), database.AddTradeItemParams{
+			TradeID:     trade.ID,
+			CharacterID: character.ID,
+			ItemID:      item.ID,
+			Quantity:    params.Quantity,
+		})
+		if err != nil {
+			return err
+		}
+		return txCfg.DB.ResetTradeConfirmations(r.Context(), trade.ID)
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to add item to trade", err)
+		return
+	}
+
+	message := fmt.Sprintf("%s offered %d %s", character.Name, params.Quantity, strings.Title(strings.ToLower(item.Name)))
+	cfg.sendTradeUpdate(r.Context(), trade, message)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": message,
+	})
+}
+
+func (cfg *ApiConfig) handleConfirmTrade(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	characterName := r.PathValue("character")
+	if err := validation.ValidateCharacterName(characterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	character, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), characterName, userID)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusNotFound, "Character not found", err)
+		}
+		return
+	}
+
+	trade, err := cfg.DB.GetOpenTradeByCharacterId(r.Context(), character.ID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Character has no open trade", err)
+		return
+	}
+
+	trade, err = cfg.DB.ConfirmTrade(r.Context(), database.ConfirmTradeParams{
+		CharacterID: character.ID,
+		ID:          trade.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Trade is no longer open", err)
+		return
+	}
+
+	if !trade.InitiatorConfirmed || !trade.RecipientConfirmed {
+		message := fmt.Sprintf("%s confirmed the trade", character.Name)
+		cfg.sendTradeUpdate(r.Context(), trade, message)
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"message": message,
+			"status":  trade.Status,
+		})
+		return
+	}
+
+	completed, err := cfg.CompleteTrade(r.Context(), trade.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusBadRequest, "Trade is no longer open or its offers changed", nil)
+			return
+		}
+		// Leave the trade open so the offers can be fixed, but make both
+		// sides confirm again
+		resetErr := cfg.DB.ResetTradeConfirmations(r.Context(), trade.ID)
+		if resetErr != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to update trade", resetErr)
+			return
+		}
+		message := fmt.Sprintf("Trade failed: %s", err.Error())
+		cfg.sendTradeUpdate(r.Context(), trade, message)
+		respondWithError(w, http.StatusBadRequest, message, err)
+		return
+	}
+
+	message := "Trade completed"
+	cfg.sendTradeUpdate(r.Context(), completed, message)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": message,
+		"status":  completed.Status,
+	})
+}
+
+func (cfg *ApiConfig) handleCancelTrade(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	characterName := r.PathValue("character")
+	if err := validation.ValidateCharacterName(characterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	character, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), characterName, userID)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusNotFound, "Character not found", err)
+		}
+		return
+	}
+
+	trade, err := cfg.DB.GetOpenTradeByCharacterId(r.Context(), character.ID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Character has no open trade", err)
+		return
+	}
+
+	trade, err = cfg.DB.UpdateOpenTradeStatus(r.Context(), database.UpdateOpenTradeStatusParams{
+		ID:     trade.ID,
+		Status: tradeStatusCancelled,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Trade is no longer open", err)
+		return
+	}
+
+	message := fmt.Sprintf("%s cancelled the trade", character.Name)
+	cfg.sendTradeUpdate(r.Context(), trade, message)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": message,
+		"status":  trade.Status,
+	})
+}
+
+// CompleteTrade swaps every offered item in one transaction. Both
+// inventories are locked before anything moves and every removal must take
+// the items it asked for, so a side that spent them elsewhere fails the
+// trade instead of both players keeping them. Nothing changes if either
+// side is short on items or space.
+func (cfg *ApiConfig) CompleteTrade(ctx context.Context, tradeID pgtype.UUID) (database.Trade, error) {
+	var trade database.Trade
+	var inventories []database.Inventory
+	err := cfg.RunInTx(ctx, func(txCfg *ApiConfig) error {
+		var err error
+		// Only one confirmation can close the trade, the other finds no open
+		// row. Neither can if an offer changed and reset the confirmations
+		trade, err = txCfg.DB.CloseConfirmedTrade(ctx, database.CloseConfirmedTradeParams{
+			ID:     tradeID,
+			Status: tradeStatusCompleted,
+		})
+		if err != nil {
+			return err
+		}
+
+		initiator, err := txCfg.DB.GetCharacterById(ctx, trade.InitiatorID)
+		if err != nil {
+			return err
+		}
+		recipient, err := txCfg.DB.GetCharacterById(ctx, trade.RecipientID)
+		if err != nil {
+			return err
+		}
+		if initiator.PositionX != recipient.PositionX || initiator.PositionY != recipient.PositionY {
+			return fmt.Errorf("%s and %s are no longer in the same place", initiator.Name, recipient.Name)
+		}
+
+		initiatorInventory, err := txCfg.DB.GetInventoryByCharacterId(ctx, initiator.ID)
+		if err != nil {
+			return err
+		}
+		recipientInventory, err := txCfg.DB.GetInventoryByCharacterId(ctx, recipient.ID)
+		if err != nil {
+			return err
+		}
+
+		err = txCfg.lockInventories(ctx, initiatorInventory.ID, recipientInventory.ID)
+		if err != nil {
+			return err
+		}
+		// Re-read the weights now that nobody else can change them
+		initiatorInventory, err = txCfg.DB.GetInventory(ctx, initiatorInventory.ID)
+		if err != nil {
+			return err
+		}
+		recipientInventory, err = txCfg.DB.GetInventory(ctx, recipientInventory.ID)
+		if err != nil {
+			return err
+		}
+		inventories = []database.Inventory{initiatorInventory, recipientInventory}
+
+		tradeItems, err := txCfg.DB.GetTradeItems(ctx, trade.ID)
+		if err != nil {
+			return err
+		}
+
+		weightChange := map[pgtype.UUID]int32{}
+		for _, tradeItem := range tradeItems {
+			from, to := initiatorInventory, recipientInventory
+			if tradeItem.CharacterID == recipient.ID {
+				from, to = recipientInventory, initiatorInventory
+			}
+
+			item, err := txCfg.GetItemById(ctx, tradeItem.ItemID)
+			if err != nil {
+				return err
+			}
+			weightChange[from.ID] -= item.Weight * tradeItem.Quantity
+			weightChange[to.ID] += item.Weight * tradeItem.Quantity
+
+			err = txCfg.moveItems(ctx, from.ID, to.ID, tradeItem.ItemID, tradeItem.Quantity)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, inventory := range inventories {
+			if inventory.Weight+weightChange[inventory.ID] > inventory.Capacity {
+				owner := initiator.Name
+				if inventory.ID == recipientInventory.ID {
+					owner = recipient.Name
+				}
+				return fmt.Errorf("%s doesn't have room for the trade", owner)
+			}
+		}
+		txCfg.InventoryChanged(initiator, recipient)
+		return nil
+	})
+	if err != nil {
+		return trade, err
+	}
+
+	for _, inventory := range inventories {
+		cfg.InvalidateInventoryItemsCache(ctx, inventory.ID)
+		cfg.invalidateCache(ctx, fmt.Sprintf("inventory:char:%s", inventory.CharacterID.String()))
+	}
+
+	return trade, nil
+}
+
+// sendTradeUpdate pushes a trade change to the owners of both characters.
+func (cfg *ApiConfig) sendTradeUpdate(ctx context.Context, trade database.Trade, message string) {
+	sent := map[pgtype.UUID]bool{}
+	for _, characterID := range []pgtype.UUID{trade.InitiatorID, trade.RecipientID} {
+		character, err := cfg.GetCharacterById(ctx, characterID)
+		if err != nil || sent[character.UserID] {
+			continue
+		}
+		sent[character.UserID] = true
+
+		cfg.Hub.SendToUser(character.UserID.Bytes, "trade", map[string]interface{}{
+			"message": message,
+			"status":  trade.Status,
+		})
+	}
+}
+
+func (cfg *ApiConfig) buildTradeResponse(ctx context.Context, trade database.Trade) (tradeResponse, error) {
+	initiator, err := cfg.GetCharacterById(ctx, trade.InitiatorID)
+	if err != nil {
+		return tradeResponse{}, err
+	}
+	recipient, err := cfg.GetCharacterById(ctx, trade.RecipientID)
+	if err != nil {
+		return tradeResponse{}, err
+	}
+
+	tradeItems, err := cfg.DB.GetTradeItems(ctx, trade.ID)
+	if err != nil {
+		return tradeResponse{}, err
+	}
+
+	offers := map[string][]tradeOffer{
+		initiator.Name: {},
+		recipient.Name: {},
+	}
+	for _, tradeItem := range tradeItems {
+		item, err := cfg.GetItemById(ctx, tradeItem.ItemID)
+		if err != nil {
+			return tradeResponse{}, err
+		}
+		owner := initiator.Name
+		if tradeItem.CharacterID == recipient.ID {
+			owner = recipient.Name
+		}
+		offers[owner] = append(offers[owner], tradeOffer{
+			Name:     item.Name,
+			Quantity: tradeItem.Quantity,
+		})
+	}
+
+	return tradeResponse{
+		Status:             trade.Status,
+		Initiator:          initiator.Name,
+		Recipient:          recipient.Name,
+		InitiatorConfirmed: trade.InitiatorConfirmed,
+		RecipientConfirmed: trade.RecipientConfirmed,
+		Offers:             offers,
+	}, nil
+}
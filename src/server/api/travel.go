@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/internal/auth"
+	"github.com/trbute/idler/server/internal/database"
+	"github.com/trbute/idler/server/internal/validation"
+)
+
+var directionOffsets = map[string][2]int32{
+	"N":     {0, 1},
+	"NORTH": {0, 1},
+	"S":     {0, -1},
+	"SOUTH": {0, -1},
+	"E":     {1, 0},
+	"EAST":  {1, 0},
+	"W":     {-1, 0},
+	"WEST":  {-1, 0},
+}
+
+func (cfg *ApiConfig) handleMoveCharacter(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Unable to retrieve token", err)
+		return
+	}
+
+	userID, err := auth.ValidateJWTWithBlacklist(r.Context(), token, cfg.JwtSecret, cfg.Redis)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Token invalid", err)
+		return
+	}
+
+	characterName := r.PathValue("character")
+	if err := validation.ValidateCharacterName(characterName); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	type parameters struct {
+		Direction string `json:"direction"`
+		PositionX *int32 `json:"position_x,omitempty"`
+		PositionY *int32 `json:"position_y,omitempty"`
+	}
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to decode parameters", err)
+		return
+	}
+
+	character, err := cfg.GetCharacterWithOwnershipValidation(r.Context(), characterName, userID)
+	if err != nil {
+		if err.Error() == "character doesn't belong to user" {
+			respondWithError(w, http.StatusUnauthorized, "Character doesn't belong to user", nil)
+		} else {
+			respondWithError(w, http.StatusNotFound, "Character not found", err)
+		}
+		return
+	}
+
+	// Read the character directly so a recently started action isn't hidden by the cache
+	character, err = cfg.DB.GetCharacterById(r.Context(), character.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve character", err)
+		return
+	}
+
+	var targetX, targetY int32
+	params.Direction = strings.TrimSpace(strings.ToUpper(params.Direction))
+	if params.Direction != "" {
+		offset, ok := directionOffsets[params.Direction]
+		if !ok {
+			respondWithError(w, http.StatusBadRequest, "Direction must be north, south, east or west", nil)
+			return
+		}
+		targetX = character.PositionX + offset[0]
+		targetY = character.PositionY + offset[1]
+	} else if params.PositionX != nil && params.PositionY != nil {
+		targetX = *params.PositionX
+		targetY = *params.PositionY
+	} else {
+		respondWithError(w, http.StatusBadRequest, "Direction or position must be provided", nil)
+		return
+	}
+
+	if targetX == character.PositionX && targetY == character.PositionY {
+		respondWithError(w, http.StatusBadRequest, "Character is already at that location", nil)
+		return
+	}
+
+	idleAction, err := cfg.GetActionByName(r.Context(), "IDLE")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to get idle action", err)
+		return
+	}
+
+	if character.ActionID != idleAction.ID {
+		respondWithError(w, http.StatusBadRequest, "Character must be idle before moving", nil)
+		return
+	}
+
+	_, err = cfg.DB.GetGridItem(r.Context(), database.GetGridItemParams{
+		PositionX: targetX,
+		PositionY: targetY,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("There is nothing at (%d, %d)", targetX, targetY), err)
+		return
+	}
+
+	travelAction, err := cfg.GetActionByName(r.Context(), "TRAVELING")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to get travel action", err)
+		return
+	}
+
+	ticks := cfg.TravelTicksBetween(character.PositionX, character.PositionY, targetX, targetY)
+
+	char, err := cfg.DB.StartCharacterTravel(r.Context(), database.StartCharacterTravelParams{
+		ActionID:          travelAction.ID,
+		DestinationX:      pgtype.Int4{Int32: targetX, Valid: true},
+		DestinationY:      pgtype.Int4{Int32: targetY, Valid: true},
+		ActionAmountLimit: pgtype.Int4{Int32: ticks, Valid: true},
+		ID:                character.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Character update failed", err)
+		return
+	}
+
+	cfg.InvalidateActiveCharactersCache(r.Context())
+	cfg.InvalidateCharacterCache(r.Context(), char)
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"name":          char.Name,
+		"action_id":     char.ActionID,
+		"action_name":   travelAction.Name,
+		"destination_x": targetX,
+		"destination_y": targetY,
+		"ticks":         ticks,
+	})
+}
+
+// TravelTicksBetween returns how many ticks a trip takes, scaling the
+// configured per-cell travel time by the grid distance covered.
+func (cfg *ApiConfig) TravelTicksBetween(fromX, fromY, toX, toY int32) int32 {
+	perCell := cfg.TravelTicks
+	if perCell < 1 {
+		perCell = 1
+	}
+
+	return perCell * (abs32(toX-fromX) + abs32(toY-fromY))
+}
+
+func (cfg *ApiConfig) CompleteCharacterTravel(ctx context.Context, characterID pgtype.UUID) (database.Character, error) {
+	idleAction, err := cfg.GetActionByName(ctx, "IDLE")
+	if err != nil {
+		return database.Character{}, err
+	}
+
+	character, err := cfg.DB.CompleteCharacterTravel(ctx, database.CompleteCharacterTravelParams{
+		ActionID: idleAction.ID,
+		ID:       characterID,
+	})
+	if err != nil {
+		return database.Character{}, err
+	}
+
+	err = cfg.DB.UpdateInventoryPositionByCharacterId(ctx, database.UpdateInventoryPositionByCharacterIdParams{
+		CharacterID: character.ID,
+		PositionX:   character.PositionX,
+		PositionY:   character.PositionY,
+	})
+	if err != nil {
+		return character, err
+	}
+
+	cfg.InvalidateActiveCharactersCache(ctx)
+	cfg.InvalidateCharacterCache(ctx, character)
+	cfg.invalidateCache(ctx, fmt.Sprintf("inventory:char:%s", character.ID.String()))
+	cfg.characterChanged(CharacterChange{Character: character, Idled: true})
+
+	return character, nil
+}
+
+func abs32(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,30 @@
+package api
+
+import "testing"
+
+func TestTravelTicksBetween(t *testing.T) {
+	tests := []struct {
+		name        string
+		travelTicks int32
+		fromX       int32
+		fromY       int32
+		toX         int32
+		toY         int32
+		want        int32
+	}{
+		{"adjacent cell", 5, 0, 0, 0, 1, 5},
+		{"diagonal trip", 5, 0, 0, 2, -1, 15},
+		{"negative coordinates", 3, -2, -2, 1, -2, 9},
+		{"unset travel ticks", 0, 0, 0, 1, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &ApiConfig{TravelTicks: tt.travelTicks}
+			got := cfg.TravelTicksBetween(tt.fromX, tt.fromY, tt.toX, tt.toY)
+			if got != tt.want {
+				t.Errorf("TravelTicksBetween() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/trbute/idler/server/internal/database"
+)
+
+// maxTxAttempts is how many times RunInTx tries a transaction that keeps
+// losing to concurrent writes.
+const maxTxAttempts = 3
+
+// Notification is a message for a character's owner, held back until the
+// transaction that caused it commits.
+type Notification struct {
+	Character database.Character
+	Message   string
+	Severity  string
+}
+
+// CharacterChange is something that happened to a character which its
+// owner's client can't tell from the tick's pushes, held back like a
+// notification until the transaction commits.
+type CharacterChange struct {
+	Character database.Character
+	// Idled means the character stopped acting
+	Idled bool
+	// Inventory means the character's inventory changed and should be
+	// fetched again
+	Inventory bool
+}
+
+// RunInTx runs fn in one repeatable read transaction, starting over when
+// Postgres reports a serialization failure or deadlock. fn gets a copy of
+// the config whose queries run in the transaction. Notifications sent and
+// caches invalidated through it are held back until the transaction
+// commits, so nobody reads the old rows back into the cache in between.
+func (cfg *ApiConfig) RunInTx(ctx context.Context, fn func(txCfg *ApiConfig) error) error {
+	notifications, changes, err := cfg.RunInTxQuietly(ctx, fn)
+	if err != nil {
+		return err
+	}
+	cfg.SendNotifications(notifications)
+	cfg.SendCharacterChanges(changes)
+	return nil
+}
+
+// RunInTxQuietly is RunInTx, but hands the notifications and character
+// changes back once the transaction commits rather than sending them, for
+// callers that report them some other way.
+func (cfg *ApiConfig) RunInTxQuietly(ctx context.Context, fn func(txCfg *ApiConfig) error) ([]Notification, []CharacterChange, error) {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		var notifications []Notification
+		var changes []CharacterChange
+		var invalidations []string
+		err = cfg.runTx(ctx, fn, &notifications, &changes, &invalidations)
+		if err == nil {
+			cfg.invalidateCache(ctx, invalidations...)
+			return notifications, changes, nil
+		}
+		if !isRetryableTxError(err) {
+			return nil, nil, err
+		}
+		log.Printf("Transaction attempt %d of %d failed, retrying: %v", attempt, maxTxAttempts, err)
+	}
+	return nil, nil, err
+}
+
+// SendNotifications delivers notifications to whichever owners are connected.
+func (cfg *ApiConfig) SendNotifications(notifications []Notification) {
+	for _, n := range notifications {
+		cfg.Hub.SendNotificationToUser(n.Character.UserID.Bytes, n.Message, n.Severity)
+	}
+}
+
+// SendCharacterChanges tells each owner which of their characters went idle
+// and which inventories to fetch again, in at most one message of each.
+func (cfg *ApiConfig) SendCharacterChanges(changes []CharacterChange) {
+	idled := make(map[uuid.UUID][]string)
+	inventories := make(map[uuid.UUID][]string)
+	for _, change := range changes {
+		userID := uuid.UUID(change.Character.UserID.Bytes)
+		name := change.Character.Name
+		if change.Idled && !slices.Contains(idled[userID], name) {
+			idled[userID] = append(idled[userID], name)
+		}
+		if change.Inventory && !slices.Contains(inventories[userID], name) {
+			inventories[userID] = append(inventories[userID], name)
+		}
+	}
+
+	for userID, names := range idled {
+		cfg.Hub.SendToUser(userID, "character_idle", map[string]interface{}{
+			"characters": names,
+		})
+	}
+	for userID, names := range inventories {
+		cfg.Hub.SendToUser(userID, "inventory_changed", map[string]interface{}{
+			"characters": names,
+		})
+	}
+}
+
+func (cfg *ApiConfig) runTx(ctx context.Context, fn func(txCfg *ApiConfig) error, notifications *[]Notification, changes *[]CharacterChange, invalidations *[]string) error {
+	tx, err := cfg.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	txCfg := *cfg
+	txCfg.DB = cfg.DB.WithTx(tx)
+	txCfg.notifications = notifications
+	txCfg.changes = changes
+	txCfg.invalidations = invalidations
+
+	err = fn(&txCfg)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// isRetryableTxError reports whether a transaction failed only because it
+// raced another one, so running it again can succeed.
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	// serialization_failure and deadlock_detected
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+// invalidateCache drops cached keys, or queues them for after the commit
+// when running inside RunInTx.
+func (cfg *ApiConfig) invalidateCache(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	if cfg.invalidations != nil {
+		*cfg.invalidations = append(*cfg.invalidations, keys...)
+		return
+	}
+	cfg.Redis.Del(ctx, keys...)
+}
+
+// characterChanged sends a character change, or queues it for after the
+// commit when running inside RunInTx.
+func (cfg *ApiConfig) characterChanged(change CharacterChange) {
+	if cfg.changes != nil {
+		*cfg.changes = append(*cfg.changes, change)
+		return
+	}
+	cfg.SendCharacterChanges([]CharacterChange{change})
+}
+
+// InventoryChanged tells the owners of characters whose inventories changed
+// outside a tick to fetch them again.
+func (cfg *ApiConfig) InventoryChanged(characters ...database.Character) {
+	for _, character := range characters {
+		cfg.characterChanged(CharacterChange{Character: character, Inventory: true})
+	}
+}
+
+// NotifyCharacterOwner tells a character's owner what happened to it and
+// keeps the message for their away summary. Recording the message runs in
+// the caller's transaction, so its error has to be returned rather than
+// leave the transaction aborted.
+func (cfg *ApiConfig) NotifyCharacterOwner(ctx context.Context, character database.Character, message string, severity string) error {
+	err := cfg.RecordCharacterEvent(ctx, character.ID, message)
+	if err != nil {
+		return err
+	}
+
+	if cfg.notifications != nil {
+		*cfg.notifications = append(*cfg.notifications, Notification{
+			Character: character,
+			Message:   message,
+			Severity:  severity,
+		})
+	} else {
+		cfg.Hub.SendNotificationToUser(character.UserID.Bytes, message, severity)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"log"
 	"net/http"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -39,6 +40,11 @@ func (cfg *ApiConfig) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	tokenID := claims.ID
 
 	websocket.ServeWS(cfg.Hub, cfg, cfg.Limiter, w, r, userID, tokenID)
+
+	err = cfg.DeliverAwaySummary(r.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to deliver away summary to user %s: %v", userID, err)
+	}
 }
 
 func (cfg *ApiConfig) ValidateSpecificToken(ctx context.Context, tokenID string) error {
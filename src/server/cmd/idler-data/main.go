@@ -0,0 +1,259 @@
+// Command idler-data checks and moves world content between the data/json
+// files and the database.
+//
+//	idler-data lint [-dir data/json]     validate the content files
+//	idler-data diff [-dir data/json]     show what loading them would change
+//	idler-data export [-dir exported]    write the database content as JSON
+//	idler-data generate [-dir data/json] [-width n] [-height n] [-seed n]
+//	                                     write world.json and show the map
+//
+// diff and export connect with the same DB_* variables as the server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/trbute/idler/server/data"
+	"github.com/trbute/idler/server/internal/database"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "lint":
+		err = lint(os.Args[2:])
+	case "diff":
+		err = diff(os.Args[2:])
+	case "export":
+		err = export(os.Args[2:])
+	case "generate":
+		err = generate(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: idler-data lint|diff|export|generate [-dir path]")
+	os.Exit(2)
+}
+
+func parseDir(name string, args []string, fallback string) string {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	dir := flags.String("dir", fallback, "content directory")
+	flags.Parse(args)
+	return *dir
+}
+
+func lint(args []string) error {
+	dir := parseDir("lint", args, "data/json")
+
+	content, err := data.LoadContent(dir)
+	if err != nil {
+		return err
+	}
+
+	err = data.ValidateContent(content)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is valid\n", dir)
+	return nil
+}
+
+func diff(args []string) error {
+	dir := parseDir("diff", args, "data/json")
+
+	content, err := data.LoadContent(dir)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cfg, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cfg.Pool.Close()
+
+	version, err := data.LoadVersion(dir)
+	if err != nil {
+		return err
+	}
+	dbVersion, err := cfg.DB.GetVersion(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Database is at %s, %s is at %s\n", dbVersion.Value, dir, version)
+	if version == dbVersion.Value {
+		fmt.Println("The versions match, so the server won't load these changes until version.json is bumped")
+	}
+
+	d, err := cfg.DiffContent(ctx, content)
+	if err != nil {
+		return err
+	}
+	d.WriteReport(os.Stdout)
+	return nil
+}
+
+func export(args []string) error {
+	dir := parseDir("export", args, "exported")
+
+	ctx := context.Background()
+	cfg, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer cfg.Pool.Close()
+
+	content, err := cfg.ExportContent(ctx)
+	if err != nil {
+		return err
+	}
+	version, err := cfg.DB.GetVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = data.WriteContent(dir, content, version.Value)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported version %s to %s\n", version.Value, dir)
+	return nil
+}
+
+// generate writes world.json with a new seed, or the one given, keeping
+// any other settings already there, and prints the biome map it makes.
+func generate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	dir := flags.String("dir", "data/json", "content directory")
+	width := flags.Int("width", 0, "cells across, defaults to the current width or 16")
+	height := flags.Int("height", 0, "cells down, defaults to the current height or 16")
+	seed := flags.Int64("seed", 0, "seed to reproduce, a new one when unset")
+	flags.Parse(args)
+
+	content, err := data.LoadContent(*dir)
+	if err != nil {
+		return err
+	}
+
+	world := data.World{Width: 16, Height: 16}
+	if content.World != nil {
+		world = *content.World
+	}
+	if len(world.Biomes) == 0 {
+		world.Biomes = nodeBiomes(content.ResourceNodes)
+	}
+	if *width > 0 {
+		world.Width = *width
+	}
+	if *height > 0 {
+		world.Height = *height
+	}
+	world.Seed = *seed
+	if world.Seed == 0 {
+		world.Seed = time.Now().UnixNano()
+	}
+
+	content.World = &world
+	err = data.ValidateContent(content)
+	if err != nil {
+		return err
+	}
+
+	err = data.WriteWorld(*dir, world)
+	if err != nil {
+		return err
+	}
+
+	printMap(world, data.GenerateWorld(world, content.ResourceNodes, content.Creatures))
+	fmt.Printf("Wrote %s with seed %d, bump version.json to load it\n", filepath.Join(*dir, "world.json"), world.Seed)
+	return nil
+}
+
+// nodeBiomes starts a new world with every biome the resource nodes name,
+// or a single one when they name none.
+func nodeBiomes(nodes []data.ResourceNode) []data.Biome {
+	var biomes []data.Biome
+	seen := make(map[string]bool)
+	for _, node := range nodes {
+		if node.Biome != "" && !seen[node.Biome] {
+			seen[node.Biome] = true
+			biomes = append(biomes, data.Biome{Name: node.Biome})
+		}
+	}
+	if len(biomes) == 0 {
+		biomes = append(biomes, data.Biome{Name: "PLAINS"})
+	}
+	return biomes
+}
+
+// printMap draws each cell as the first letter of its biome, with (0, 0)
+// marked, followed by a key.
+func printMap(world data.World, cells []data.GeneratedCell) {
+	letters := make(map[string]byte)
+	for _, biome := range world.Biomes {
+		letters[biome.Name] = biome.Name[0]
+	}
+
+	_, _, maxX, _ := world.Bounds()
+	for _, cell := range cells {
+		letter := letters[cell.Biome]
+		if cell.PositionX == 0 && cell.PositionY == 0 {
+			letter = '@'
+		}
+		fmt.Printf("%c", letter)
+		if cell.PositionX == maxX {
+			fmt.Println()
+		}
+	}
+
+	for _, biome := range world.Biomes {
+		fmt.Printf("%c %s\n", letters[biome.Name], biome.Name)
+	}
+	fmt.Println("@ (0, 0)")
+}
+
+func connect(ctx context.Context) (*data.DataConfig, error) {
+	dbURL := fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+	)
+
+	pool, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("unable to connect to the database: %w", err)
+	}
+
+	return &data.DataConfig{
+		DB:   database.New(pool),
+		Pool: pool,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package data
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Diff is what a content sync changed, kind of entity by kind.
+type Diff struct {
+	Kinds []*KindDiff
+	// Idled are characters set idle because what they were doing was removed
+	Idled []string
+}
+
+// KindDiff lists the entities of one kind that were added, changed or
+// removed, by name.
+type KindDiff struct {
+	Kind    string
+	Added   []string
+	Changed []string
+	Removed []string
+}
+
+func newDiff(kinds ...string) *Diff {
+	diff := &Diff{}
+	for _, kind := range kinds {
+		diff.Kinds = append(diff.Kinds, &KindDiff{Kind: kind})
+	}
+	return diff
+}
+
+// kind returns the changes for one kind, adding it if it isn't listed yet.
+func (d *Diff) kind(kind string) *KindDiff {
+	for _, k := range d.Kinds {
+		if k.Kind == kind {
+			return k
+		}
+	}
+	k := &KindDiff{Kind: kind}
+	d.Kinds = append(d.Kinds, k)
+	return k
+}
+
+func (d *Diff) added(kind, name string) {
+	k := d.kind(kind)
+	k.Added = append(k.Added, name)
+}
+
+// changed marks an entity changed once, however many of its parts changed.
+func (d *Diff) changed(kind, name string) {
+	k := d.kind(kind)
+	for _, changed := range k.Changed {
+		if changed == name {
+			return
+		}
+	}
+	for _, added := range k.Added {
+		if added == name {
+			return
+		}
+	}
+	k.Changed = append(k.Changed, name)
+}
+
+func (d *Diff) removed(kind, name string) {
+	k := d.kind(kind)
+	k.Removed = append(k.Removed, name)
+}
+
+func (k *KindDiff) empty() bool {
+	return len(k.Added) == 0 && len(k.Changed) == 0 && len(k.Removed) == 0
+}
+
+// Empty reports whether the sync left the database as it was.
+func (d *Diff) Empty() bool {
+	for _, k := range d.Kinds {
+		if !k.empty() {
+			return false
+		}
+	}
+	return len(d.Idled) == 0
+}
+
+// WriteReport writes the diff one kind at a time, skipping kinds that didn't
+// change.
+func (d *Diff) WriteReport(w io.Writer) {
+	if d.Empty() {
+		fmt.Fprintln(w, "No content changes")
+		return
+	}
+
+	for _, k := range d.Kinds {
+		if k.empty() {
+			continue
+		}
+		fmt.Fprintf(w, "%s: %d added, %d changed, %d removed\n",
+			k.Kind, len(k.Added), len(k.Changed), len(k.Removed))
+		for _, name := range k.Added {
+			fmt.Fprintf(w, "  + %s\n", name)
+		}
+		for _, name := range k.Changed {
+			fmt.Fprintf(w, "  ~ %s\n", name)
+		}
+		for _, name := range k.Removed {
+			fmt.Fprintf(w, "  - %s\n", name)
+		}
+	}
+
+	if len(d.Idled) > 0 {
+		fmt.Fprintf(w, "characters set idle: %s\n", strings.Join(d.Idled, ", "))
+	}
+}
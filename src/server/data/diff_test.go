@@ -0,0 +1,66 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffReport(t *testing.T) {
+	diff := newDiff(kindItems, kindResourceNodes, kindGrid)
+	diff.added(kindItems, "IRON INGOT")
+	diff.changed(kindItems, "BRONZE AXE")
+	diff.changed(kindItems, "BRONZE AXE")
+	diff.removed(kindItems, "TIN SCRAP")
+	diff.added(kindResourceNodes, "IRON VEIN")
+	diff.changed(kindResourceNodes, "IRON VEIN")
+	diff.Idled = []string{"Ada", "Bo"}
+
+	var out strings.Builder
+	diff.WriteReport(&out)
+
+	want := `items: 1 added, 1 changed, 1 removed
+  + IRON INGOT
+  ~ BRONZE AXE
+  - TIN SCRAP
+resource nodes: 1 added, 0 changed, 0 removed
+  + IRON VEIN
+characters set idle: Ada, Bo
+`
+	if out.String() != want {
+		t.Errorf("report =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestDiffReportEmpty(t *testing.T) {
+	diff := newDiff(kindItems)
+	if !diff.Empty() {
+		t.Fatal("new diff should be empty")
+	}
+
+	var out strings.Builder
+	diff.WriteReport(&out)
+	if out.String() != "No content changes\n" {
+		t.Errorf("report = %q", out.String())
+	}
+}
+
+func TestTableStore(t *testing.T) {
+	type row struct {
+		name   string
+		weight int
+	}
+	tbl := newTable([]row{{"STICKS", 1}, {"ROCKS", 2}}, func(r row) string { return r.name })
+
+	if added, changed := tbl.store("STICKS", row{"STICKS", 1}); added || changed {
+		t.Errorf("unchanged row reported added=%v changed=%v", added, changed)
+	}
+	if added, changed := tbl.store("ROCKS", row{"ROCKS", 3}); added || !changed {
+		t.Errorf("changed row reported added=%v changed=%v", added, changed)
+	}
+	if added, changed := tbl.store("LOGS", row{"LOGS", 2}); !added || changed {
+		t.Errorf("new row reported added=%v changed=%v", added, changed)
+	}
+	if ids := tbl.ids(func(r row) int32 { return int32(r.weight) }); len(ids) != 3 {
+		t.Errorf("ids = %v, want 3", ids)
+	}
+}
@@ -0,0 +1,265 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/trbute/idler/server/internal/database"
+)
+
+// DiffContent reports what SyncContent would change without changing it.
+// The sync runs in a transaction that is always rolled back.
+func (cfg *DataConfig) DiffContent(ctx context.Context, content Content) (*Diff, error) {
+	err := ValidateContent(content)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := cfg.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	txCfg := *cfg
+	txCfg.DB = cfg.DB.WithTx(tx)
+	txCfg.sync, err = newContentSync(ctx, txCfg.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	err = txCfg.storeContent(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return txCfg.sync.diff, nil
+}
+
+// ExportContent reads the content stored in the database back into the
+// shape of the data/json files.
+func (cfg *DataConfig) ExportContent(ctx context.Context) (Content, error) {
+	s, err := newContentSync(ctx, cfg.DB)
+	if err != nil {
+		return Content{}, err
+	}
+
+	content := Content{}
+
+	toolTypeNames := make(map[int32]string)
+	for _, row := range sortedByID(s.toolTypes.existing, func(r database.ToolType) int32 { return r.ID }) {
+		toolTypeNames[row.ID] = row.Name
+		content.ToolTypes = append(content.ToolTypes, ToolType{Name: row.Name})
+	}
+
+	actionNames := make(map[int32]string)
+	for _, row := range sortedByID(s.actions.existing, func(r database.Action) int32 { return r.ID }) {
+		actionNames[row.ID] = row.Name
+		content.Actions = append(content.Actions, Action{
+			Name:             row.Name,
+			RequiredToolType: toolTypeNames[row.RequiredToolTypeID.Int32],
+		})
+	}
+
+	itemNames := make(map[int32]string)
+	for _, row := range sortedByID(s.items.existing, func(r database.Item) int32 { return r.ID }) {
+		itemNames[row.ID] = row.Name
+		content.Items = append(content.Items, Item{
+			Name:          row.Name,
+			Weight:        int(row.Weight),
+			ToolType:      toolTypeNames[row.ToolTypeID.Int32],
+			ToolTier:      int(row.ToolTier),
+			MaxDurability: int(row.MaxDurability.Int32),
+		})
+	}
+
+	ingredients := sortedByID(s.ingredients.existing, func(r database.RecipeIngredient) int32 { return r.ID })
+	for _, row := range sortedByID(s.recipes.existing, func(r database.Recipe) int32 { return r.ID }) {
+		recipe := Recipe{
+			Name:        row.Name,
+			ItemName:    itemNames[row.ItemID],
+			Quantity:    int(row.Quantity),
+			Ingredients: []Ingredient{},
+		}
+		for _, ingredient := range ingredients {
+			if ingredient.RecipeID == row.ID {
+				recipe.Ingredients = append(recipe.Ingredients, Ingredient{
+					Name:     itemNames[ingredient.ItemID],
+					Quantity: int(ingredient.Quantity),
+				})
+			}
+		}
+		content.Recipes = append(content.Recipes, recipe)
+	}
+
+	nodeNames := make(map[int32]string)
+	resources := sortedByID(s.resources.existing, func(r database.Resource) int32 { return r.ID })
+	for _, row := range sortedByID(s.nodes.existing, func(r database.ResourceNode) int32 { return r.ID }) {
+		nodeNames[row.ID] = row.Name
+		node := ResourceNode{
+			Name:          row.Name,
+			ActionName:    actionNames[row.ActionID],
+			Tier:          int(row.Tier),
+			MinToolTier:   int(row.MinToolTier),
+			MinLevel:      int(row.MinLevel),
+			Amount:        int(row.Amount.Int32),
+			RespawnTicks:  int(row.RespawnTicks),
+			IntervalTicks: int(row.IntervalTicks),
+			MinQuantity:   int(row.MinQuantity),
+			MaxQuantity:   int(row.MaxQuantity),
+			Biome:         row.Biome,
+			Drops:         []Drop{},
+		}
+		for _, resource := range resources {
+			if resource.ResourceNodeID == row.ID {
+				node.Drops = append(node.Drops, Drop{
+					Name:   itemNames[resource.ItemID],
+					Chance: int(resource.DropChance),
+				})
+			}
+		}
+		content.ResourceNodes = append(content.ResourceNodes, node)
+	}
+
+	creatureNames := make(map[int32]string)
+	drops := sortedByID(s.drops.existing, func(r database.CreatureDrop) int32 { return r.ID })
+	for _, row := range sortedByID(s.creatures.existing, func(r database.Creature) int32 { return r.ID }) {
+		creatureNames[row.ID] = row.Name
+		creature := Creature{
+			Name:         row.Name,
+			Tier:         int(row.Tier),
+			MaxHP:        int(row.MaxHp),
+			Damage:       int(row.Damage),
+			RespawnTicks: int(row.RespawnTicks),
+			Loot:         []Loot{},
+		}
+		for _, drop := range drops {
+			if drop.CreatureID == row.ID {
+				creature.Loot = append(creature.Loot, Loot{
+					Name:     itemNames[drop.ItemID],
+					Chance:   int(drop.DropChance),
+					Quantity: int(drop.Quantity),
+				})
+			}
+		}
+		content.Creatures = append(content.Creatures, creature)
+	}
+
+	cells := make([]database.Grid, 0, len(s.grid.existing))
+	for cell := range s.grid.existing {
+		cells = append(cells, cell)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].PositionX != cells[j].PositionX {
+			return cells[i].PositionX < cells[j].PositionX
+		}
+		return cells[i].PositionY < cells[j].PositionY
+	})
+	nodeSpawns := sortedByID(s.nodeSpawns.existing, func(r database.ResourceNodeSpawn) int32 { return r.ID })
+	creatureSpawns := sortedByID(s.creatureSpawns.existing, func(r database.CreatureSpawn) int32 { return r.ID })
+	for _, cell := range cells {
+		grid := Grid{
+			PositionX:     int(cell.PositionX),
+			PositionY:     int(cell.PositionY),
+			ResourceNodes: []string{},
+		}
+		for _, spawn := range nodeSpawns {
+			if spawn.PositionX == cell.PositionX && spawn.PositionY == cell.PositionY {
+				grid.ResourceNodes = append(grid.ResourceNodes, nodeNames[spawn.NodeID])
+			}
+		}
+		for _, spawn := range creatureSpawns {
+			if spawn.PositionX == cell.PositionX && spawn.PositionY == cell.PositionY {
+				grid.Creatures = append(grid.Creatures, creatureNames[spawn.CreatureID])
+			}
+		}
+		content.Grid = append(content.Grid, grid)
+	}
+
+	// Cells the generator builds are left to world.json, so only the hand
+	// placed ones go back into grid.json
+	if s.world != nil {
+		content.World = s.world
+		generated := make(map[[2]int]Grid)
+		for _, cell := range GenerateWorld(*s.world, content.ResourceNodes, content.Creatures) {
+			generated[[2]int{cell.PositionX, cell.PositionY}] = cell.Grid
+		}
+		placed := content.Grid[:0]
+		for _, cell := range content.Grid {
+			gen, ok := generated[[2]int{cell.PositionX, cell.PositionY}]
+			if ok && slices.Equal(gen.ResourceNodes, cell.ResourceNodes) && slices.Equal(gen.Creatures, cell.Creatures) {
+				continue
+			}
+			placed = append(placed, cell)
+		}
+		content.Grid = placed
+	}
+
+	return content, nil
+}
+
+// sortedByID returns a table's rows in the order they were created, which
+// is the order they appeared in the content files.
+func sortedByID[K comparable, R any](rows map[K]R, id func(R) int32) []R {
+	sorted := make([]R, 0, len(rows))
+	for _, row := range rows {
+		sorted = append(sorted, row)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return id(sorted[i]) < id(sorted[j]) })
+	return sorted
+}
+
+// WriteContent writes content to dir as the data/json files, along with
+// the version they belong to.
+func WriteContent(dir string, content Content, version string) error {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		v    any
+	}{
+		{toolTypesFile, content.ToolTypes},
+		{actionsFile, content.Actions},
+		{itemsFile, content.Items},
+		{recipesFile, content.Recipes},
+		{resourceNodesFile, content.ResourceNodes},
+		{creaturesFile, content.Creatures},
+		{gridFile, content.Grid},
+		{versionFile, Version{Value: version}},
+	}
+	if content.World != nil {
+		files = append(files, struct {
+			name string
+			v    any
+		}{worldFile, content.World})
+	}
+	for _, file := range files {
+		data, err := json.MarshalIndent(file.v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.name, err)
+		}
+		err = os.WriteFile(filepath.Join(dir, file.name), append(data, '\n'), 0o644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteWorld writes the generator settings to dir, leaving the other
+// content files as they are.
+func WriteWorld(dir string, world World) error {
+	data, err := json.MarshalIndent(world, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, worldFile), append(data, '\n'), 0o644)
+}
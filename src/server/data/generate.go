@@ -0,0 +1,249 @@
+package data
+
+import (
+	"math/rand"
+	"slices"
+)
+
+// World configures the grid generator. The same seed and rules always
+// generate the same grid, so world.json is all it takes to reproduce a map.
+type World struct {
+	Seed   int64 `json:"seed"`
+	Width  int   `json:"width"`
+	Height int   `json:"height"`
+	// RegionSize is roughly how many cells across each patch of one biome
+	// is, 4 when unset
+	RegionSize int `json:"region_size,omitempty"`
+	// TierDistance is how many cells out from (0, 0) the tier goes up by
+	// one, 4 when unset
+	TierDistance int `json:"tier_distance,omitempty"`
+	// NodesPerCell is how many resource nodes each cell gets, 3 when unset
+	NodesPerCell int `json:"nodes_per_cell,omitempty"`
+	// CreaturesPerCell is how many creatures each cell gets, none when unset
+	CreaturesPerCell int     `json:"creatures_per_cell,omitempty"`
+	Biomes           []Biome `json:"biomes"`
+}
+
+type Biome struct {
+	Name string `json:"name"`
+	// Weight is how common the biome is relative to the others, 1 when unset
+	Weight int `json:"weight,omitempty"`
+}
+
+// GeneratedCell is one cell of a generated grid.
+type GeneratedCell struct {
+	Grid
+	Biome string
+	Tier  int
+}
+
+// Bounds returns the corners of the generated grid, which is centred on
+// (0, 0) so new characters start in the middle of the map.
+func (w World) Bounds() (minX, minY, maxX, maxY int) {
+	minX, minY = -(w.Width / 2), -(w.Height / 2)
+	return minX, minY, minX + w.Width - 1, minY + w.Height - 1
+}
+
+func (w World) regionSize() int {
+	return max(defaultIfZero(w.RegionSize, 4), 1)
+}
+
+func (w World) tierDistance() int {
+	return max(defaultIfZero(w.TierDistance, 4), 1)
+}
+
+func (w World) nodesPerCell() int {
+	return defaultIfZero(w.NodesPerCell, 3)
+}
+
+func defaultIfZero(v, fallback int) int {
+	if v == 0 {
+		return fallback
+	}
+	return v
+}
+
+// GenerateWorld builds the grid world describes. Biomes come in patches
+// around a jittered point per region, and each cell's tier rises with its
+// distance from (0, 0). A cell gets resource nodes whose biome matches and
+// whose tier it has reached, favouring its own tier and the one below, and
+// likewise creatures by tier. Every cell draws from its own seeded source,
+// so resizing the world leaves the cells it already had alone.
+func GenerateWorld(world World, nodes []ResourceNode, creatures []Creature) []GeneratedCell {
+	minX, minY, maxX, maxY := world.Bounds()
+
+	var creatureNames []string
+	var creatureTiers []int
+	for _, creature := range creatures {
+		creatureNames = append(creatureNames, creature.Name)
+		creatureTiers = append(creatureTiers, creature.Tier)
+	}
+
+	cells := make([]GeneratedCell, 0, max(world.Width*world.Height, 0))
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			biome := world.biomeAt(x, y)
+			tier := 1 + max(abs(x), abs(y))/world.tierDistance()
+			rng := world.rand(x, y, 0)
+
+			cell := GeneratedCell{
+				Grid: Grid{
+					PositionX:     x,
+					PositionY:     y,
+					ResourceNodes: []string{},
+				},
+				Biome: biome,
+				Tier:  tier,
+			}
+
+			var nodeNames []string
+			var nodeTiers []int
+			for _, node := range nodes {
+				if node.Biome == "" || node.Biome == biome {
+					nodeNames = append(nodeNames, node.Name)
+					nodeTiers = append(nodeTiers, node.Tier)
+				}
+			}
+			cell.ResourceNodes = append(cell.ResourceNodes, pickByTier(rng, nodeNames, nodeTiers, tier, world.nodesPerCell())...)
+
+			cell.Creatures = pickByTier(rng, creatureNames, creatureTiers, tier, world.CreaturesPerCell)
+
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// pickByTier picks up to count names whose tier the cell has reached,
+// preferring its own tier and the one below when there are any. Picks keep
+// the order the names were given in.
+func pickByTier(rng *rand.Rand, names []string, tiers []int, tier, count int) []string {
+	var near, reached []int
+	for i, t := range tiers {
+		if t > tier {
+			continue
+		}
+		reached = append(reached, i)
+		if t >= tier-1 {
+			near = append(near, i)
+		}
+	}
+	candidates := reached
+	if len(near) > 0 {
+		candidates = near
+	}
+	if len(candidates) == 0 || count <= 0 {
+		return nil
+	}
+
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	picked := slices.Clone(candidates[:min(count, len(candidates))])
+	slices.Sort(picked)
+
+	result := make([]string, len(picked))
+	for i, index := range picked {
+		result[i] = names[index]
+	}
+	return result
+}
+
+// biomeAt finds the region point nearest to (x, y) among the regions
+// around it and returns that region's biome.
+func (w World) biomeAt(x, y int) string {
+	size := w.regionSize()
+	regionX, regionY := floorDiv(x, size), floorDiv(y, size)
+
+	best, bestDistance := "", -1
+	for ry := regionY - 1; ry <= regionY+1; ry++ {
+		for rx := regionX - 1; rx <= regionX+1; rx++ {
+			rng := w.rand(rx, ry, 1)
+			px := rx*size + rng.Intn(size)
+			py := ry*size + rng.Intn(size)
+			biome := w.pickBiome(rng)
+
+			distance := (px-x)*(px-x) + (py-y)*(py-y)
+			if bestDistance < 0 || distance < bestDistance {
+				best, bestDistance = biome, distance
+			}
+		}
+	}
+	return best
+}
+
+func (w World) pickBiome(rng *rand.Rand) string {
+	total := 0
+	for _, biome := range w.Biomes {
+		total += defaultIfZero(biome.Weight, 1)
+	}
+	if total <= 0 {
+		return ""
+	}
+
+	roll := rng.Intn(total)
+	for _, biome := range w.Biomes {
+		roll -= defaultIfZero(biome.Weight, 1)
+		if roll < 0 {
+			return biome.Name
+		}
+	}
+	return ""
+}
+
+// rand returns a source seeded by the world seed and a position, with
+// layer keeping cells and regions at the same position apart.
+func (w World) rand(x, y, layer int) *rand.Rand {
+	h := uint64(w.Seed)
+	for _, v := range []int{x, y, layer} {
+		h ^= uint64(int64(v))
+		h *= 0x100000001b3
+		h ^= h >> 29
+	}
+	return rand.New(rand.NewSource(int64(h)))
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// FullGrid is the grid the content defines: the generated world, if any,
+// with the cells in grid.json placed over it.
+func (c Content) FullGrid() []Grid {
+	if c.World == nil {
+		return c.Grid
+	}
+
+	placed := make(map[[2]int]Grid, len(c.Grid))
+	for _, cell := range c.Grid {
+		placed[[2]int{cell.PositionX, cell.PositionY}] = cell
+	}
+
+	grid := make([]Grid, 0, len(c.Grid)+c.World.Width*c.World.Height)
+	for _, cell := range GenerateWorld(*c.World, c.ResourceNodes, c.Creatures) {
+		key := [2]int{cell.PositionX, cell.PositionY}
+		if hand, ok := placed[key]; ok {
+			grid = append(grid, hand)
+			delete(placed, key)
+			continue
+		}
+		grid = append(grid, cell.Grid)
+	}
+	for _, cell := range c.Grid {
+		if _, ok := placed[[2]int{cell.PositionX, cell.PositionY}]; ok {
+			grid = append(grid, cell)
+		}
+	}
+	return grid
+}
@@ -0,0 +1,165 @@
+package data
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+var testNodes = []ResourceNode{
+	{Name: "STICKS", Tier: 1},
+	{Name: "OAK", Tier: 1, Biome: "FOREST"},
+	{Name: "GRANITE", Tier: 1, Biome: "HILLS"},
+	{Name: "IRON", Tier: 3, Biome: "HILLS"},
+}
+
+var testCreatures = []Creature{
+	{Name: "RABBIT", Tier: 1},
+	{Name: "WOLF", Tier: 3},
+}
+
+func testWorld(seed int64) World {
+	return World{
+		Seed:             seed,
+		Width:            20,
+		Height:           12,
+		RegionSize:       3,
+		TierDistance:     3,
+		NodesPerCell:     2,
+		CreaturesPerCell: 1,
+		Biomes:           []Biome{{Name: "PLAINS", Weight: 2}, {Name: "FOREST"}, {Name: "HILLS"}},
+	}
+}
+
+func TestGenerateWorldIsReproducible(t *testing.T) {
+	first := GenerateWorld(testWorld(7), testNodes, testCreatures)
+	again := GenerateWorld(testWorld(7), testNodes, testCreatures)
+	if !reflect.DeepEqual(first, again) {
+		t.Error("the same seed generated different grids")
+	}
+
+	other := GenerateWorld(testWorld(8), testNodes, testCreatures)
+	if reflect.DeepEqual(first, other) {
+		t.Error("different seeds generated the same grid")
+	}
+}
+
+func TestGenerateWorldFollowsRules(t *testing.T) {
+	world := testWorld(7)
+	cells := GenerateWorld(world, testNodes, testCreatures)
+	if len(cells) != world.Width*world.Height {
+		t.Fatalf("got %d cells, want %d", len(cells), world.Width*world.Height)
+	}
+
+	minX, minY, maxX, maxY := world.Bounds()
+	if minX > 0 || maxX < 0 || minY > 0 || maxY < 0 || maxX-minX+1 != world.Width || maxY-minY+1 != world.Height {
+		t.Fatalf("Bounds() = %d, %d, %d, %d, want a %dx%d grid around (0, 0)", minX, minY, maxX, maxY, world.Width, world.Height)
+	}
+
+	nodes := make(map[string]ResourceNode)
+	for _, node := range testNodes {
+		nodes[node.Name] = node
+	}
+	biomes := make(map[string]bool)
+	for _, cell := range cells {
+		biomes[cell.Biome] = true
+		if len(cell.ResourceNodes) == 0 || len(cell.ResourceNodes) > world.NodesPerCell {
+			t.Errorf("%v has %d resource nodes, want 1 to %d", cell.Grid, len(cell.ResourceNodes), world.NodesPerCell)
+		}
+		for _, name := range cell.ResourceNodes {
+			node := nodes[name]
+			if node.Biome != "" && node.Biome != cell.Biome {
+				t.Errorf("%s placed in %s at (%d, %d)", name, cell.Biome, cell.PositionX, cell.PositionY)
+			}
+			if node.Tier > cell.Tier {
+				t.Errorf("tier %d %s placed in a tier %d cell", node.Tier, name, cell.Tier)
+			}
+		}
+		if cell.PositionX == 0 && cell.PositionY == 0 && cell.Tier != 1 {
+			t.Errorf("(0, 0) is tier %d, want 1", cell.Tier)
+		}
+		if cell.Tier >= 3 && !slices.Equal(cell.Creatures, []string{"WOLF"}) {
+			t.Errorf("tier %d cell creatures = %v, want WOLF", cell.Tier, cell.Creatures)
+		}
+	}
+	if len(biomes) != len(world.Biomes) {
+		t.Errorf("generated biomes %v, want all of %v", biomes, world.Biomes)
+	}
+}
+
+func TestGenerateWorldKeepsCellsWhenResized(t *testing.T) {
+	small := testWorld(7)
+	large := testWorld(7)
+	large.Width, large.Height = small.Width+10, small.Height+10
+
+	cells := make(map[[2]int]GeneratedCell)
+	for _, cell := range GenerateWorld(large, testNodes, testCreatures) {
+		cells[[2]int{cell.PositionX, cell.PositionY}] = cell
+	}
+	for _, cell := range GenerateWorld(small, testNodes, testCreatures) {
+		if got := cells[[2]int{cell.PositionX, cell.PositionY}]; !reflect.DeepEqual(got, cell) {
+			t.Errorf("(%d, %d) = %+v after resizing, want %+v", cell.PositionX, cell.PositionY, got, cell)
+		}
+	}
+}
+
+func TestFullGridPlacesHandCellsOverGenerated(t *testing.T) {
+	world := testWorld(7)
+	world.Width, world.Height = 3, 3
+	content := Content{
+		ResourceNodes: testNodes,
+		Grid: []Grid{
+			{PositionX: 0, PositionY: 0, ResourceNodes: []string{"STICKS"}},
+			{PositionX: 10, PositionY: 10, ResourceNodes: []string{"OAK"}},
+		},
+		World: &world,
+	}
+
+	grid := content.FullGrid()
+	if len(grid) != 10 {
+		t.Fatalf("got %d cells, want 9 generated and 1 outside", len(grid))
+	}
+	for _, cell := range grid {
+		if cell.PositionX == 0 && cell.PositionY == 0 && !slices.Equal(cell.ResourceNodes, []string{"STICKS"}) {
+			t.Errorf("(0, 0) = %v, want the hand placed cell", cell.ResourceNodes)
+		}
+	}
+	if last := grid[len(grid)-1]; last.PositionX != 10 || last.PositionY != 10 {
+		t.Errorf("last cell = (%d, %d), want (10, 10)", last.PositionX, last.PositionY)
+	}
+}
+
+func TestValidateContentChecksWorld(t *testing.T) {
+	content := Content{
+		Actions:       []Action{{Name: "IDLE"}, {Name: "GATHERING"}},
+		Items:         []Item{{Name: "STICKS"}},
+		ResourceNodes: []ResourceNode{{Name: "STICKS", ActionName: "GATHERING", Biome: "SWAMP", Drops: []Drop{{Name: "STICKS", Chance: 100}}}},
+		World: &World{
+			Width:  0,
+			Height: 4,
+			Biomes: []Biome{{Name: "PLAINS"}, {Name: "PLAINS", Weight: -1}},
+		},
+	}
+
+	err := ValidateContent(content)
+	var problems ContentErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("ValidateContent() = %v, want ContentErrors", err)
+	}
+
+	want := []string{
+		"world.json: width and height must be more than 0",
+		"world.json: PLAINS (entry 2): duplicate name",
+		"world.json: PLAINS (entry 2): weight can't be negative",
+		"resource_nodes.json: STICKS (entry 1): biome SWAMP is not in world.json",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %d problems, want %d:\n%v", len(problems), len(want), err)
+	}
+	for i, problem := range problems {
+		if problem.Error() != want[i] {
+			t.Errorf("problem %d = %q, want %q", i, problem.Error(), want[i])
+		}
+	}
+}
@@ -1,33 +1,63 @@
 package data
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/trbute/idler/server/internal/database"
 )
 
 type DataConfig struct {
-	DB *database.Queries
+	DB   *database.Queries
+	Pool *pgxpool.Pool
+
+	// sync tracks what the Store functions write during SyncContent
+	sync *contentSync
 }
 
-type Action struct {
+type ToolType struct {
 	Name string `json:"name"`
 }
 
+type Action struct {
+	Name             string `json:"name"`
+	RequiredToolType string `json:"required_tool_type,omitempty"`
+}
+
 type Item struct {
-	Name   string `json:"name"`
-	Weight int    `json:"weight"`
+	Name          string `json:"name"`
+	Weight        int    `json:"weight"`
+	ToolType      string `json:"tool_type,omitempty"`
+	ToolTier      int    `json:"tool_tier,omitempty"`
+	MaxDurability int    `json:"max_durability,omitempty"`
 }
 
 type ResourceNode struct {
-	Name       string `json:"name"`
-	ActionName string `json:"action_name"`
-	Tier       int    `json:"tier"`
-	Drops      []Drop `json:"drops"`
+	Name          string `json:"name"`
+	ActionName    string `json:"action_name"`
+	Tier          int    `json:"tier"`
+	MinToolTier   int    `json:"min_tool_tier,omitempty"`
+	MinLevel      int    `json:"min_level,omitempty"`
+	Amount        int    `json:"amount,omitempty"`
+	RespawnTicks  int    `json:"respawn_ticks,omitempty"`
+	IntervalTicks int    `json:"interval_ticks,omitempty"`
+	MinQuantity   int    `json:"min_quantity,omitempty"`
+	MaxQuantity   int    `json:"max_quantity,omitempty"`
+	// Biome limits where the world generator places the node, empty
+	// places it in any biome
+	Biome string `json:"biome,omitempty"`
+	Drops []Drop `json:"drops"`
 }
 
 type Drop struct {
@@ -35,19 +65,64 @@ type Drop struct {
 	Chance int    `json:"chance"`
 }
 
+type Creature struct {
+	Name         string `json:"name"`
+	Tier         int    `json:"tier"`
+	MaxHP        int    `json:"max_hp"`
+	Damage       int    `json:"damage"`
+	RespawnTicks int    `json:"respawn_ticks,omitempty"`
+	Loot         []Loot `json:"loot"`
+}
+
+type Loot struct {
+	Name     string `json:"name"`
+	Chance   int    `json:"chance"`
+	Quantity int    `json:"quantity"`
+}
+
+type Recipe struct {
+	Name        string       `json:"name"`
+	ItemName    string       `json:"item_name"`
+	Quantity    int          `json:"quantity"`
+	Ingredients []Ingredient `json:"ingredients"`
+}
+
+type Ingredient struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
 type Grid struct {
 	PositionX     int      `json:"position_x"`
 	PositionY     int      `json:"position_y"`
 	ResourceNodes []string `json:"resource_nodes"`
+	Creatures     []string `json:"creatures,omitempty"`
 }
 
 type Version struct {
 	Value string `json:"value"`
 }
 
+// Content is everything defined in the data/json files.
+type Content struct {
+	ToolTypes     []ToolType
+	Actions       []Action
+	Items         []Item
+	Recipes       []Recipe
+	ResourceNodes []ResourceNode
+	Creatures     []Creature
+	Grid          []Grid
+	// World generates the grid around the hand placed cells, nil when
+	// there is no world.json
+	World *World
+}
+
 func (cfg *DataConfig) InitData() {
-	version := Version{}
-	cfg.loadJSONData("data/json/version.json", &version)
+	version, err := LoadVersion("data/json")
+	if err != nil {
+		fmt.Println("Error loading version", err)
+		panic(err)
+	}
 
 	dbVersion, err := cfg.DB.GetVersion(context.Background())
 	if err != nil {
@@ -55,111 +130,493 @@ func (cfg *DataConfig) InitData() {
 		panic(err)
 	}
 
-	if version.Value == dbVersion.Value {
+	if version == dbVersion.Value {
 		fmt.Println("World up to date")
 		return
 	}
 
-	Actions := []Action{}
-	cfg.loadJSONData("data/json/actions.json", &Actions)
-	cfg.StoreActions(Actions)
+	content, err := LoadContent("data/json")
+	if err != nil {
+		fmt.Println("Error loading world data", err)
+		panic(err)
+	}
 
-	Items := []Item{}
-	cfg.loadJSONData("data/json/items.json", &Items)
-	cfg.StoreItems(Items)
+	diff, err := cfg.SyncContent(context.Background(), content, version)
+	if err != nil {
+		fmt.Println("Error syncing world data:", err)
+		panic(err)
+	}
 
-	ResourceNodes := []ResourceNode{}
-	cfg.loadJSONData("data/json/resource_nodes.json", &ResourceNodes)
-	cfg.StoreResourceNodes(ResourceNodes)
+	fmt.Printf("World data updated from %s to %s\n", dbVersion.Value, version)
+	diff.WriteReport(os.Stdout)
+}
 
-	GridItems := []Grid{}
-	cfg.loadJSONData("data/json/grid.json", &GridItems)
-	cfg.StoreGridItems(GridItems)
+// ReloadContent syncs the database with the content in dir whether or not
+// its version changed, so edits can be picked up without a restart.
+func (cfg *DataConfig) ReloadContent(ctx context.Context, dir string) (*Diff, error) {
+	content, err := LoadContent(dir)
+	if err != nil {
+		return nil, err
+	}
+	version, err := LoadVersion(dir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.SyncContent(ctx, content, version)
+}
 
-	cfg.DB.UpdateVersion(context.Background(), version.Value)
+// LoadVersion reads the content version in dir.
+func LoadVersion(dir string) (string, error) {
+	version := Version{}
+	err := loadJSONData(filepath.Join(dir, versionFile), &version)
+	return version.Value, err
 }
 
-func (cfg *DataConfig) loadJSONData(path string, v interface{}) {
+// LoadContent reads every content file in dir. Fields the types don't
+// know are an error, so a misspelt key isn't silently dropped. Every file
+// is read even when one fails, and the failures come back together as
+// ContentErrors.
+func LoadContent(dir string) (Content, error) {
+	content := Content{}
+	files := []struct {
+		name string
+		v    any
+	}{
+		{toolTypesFile, &content.ToolTypes},
+		{actionsFile, &content.Actions},
+		{itemsFile, &content.Items},
+		{recipesFile, &content.Recipes},
+		{resourceNodesFile, &content.ResourceNodes},
+		{creaturesFile, &content.Creatures},
+		{gridFile, &content.Grid},
+	}
+	var problems ContentErrors
+	for _, file := range files {
+		err := decodeJSONFile(filepath.Join(dir, file.name), file.v)
+		if err != nil {
+			problems = append(problems, ContentError{File: file.name, Message: err.Error()})
+		}
+	}
+
+	world := World{}
+	err := decodeJSONFile(filepath.Join(dir, worldFile), &world)
+	if err == nil {
+		content.World = &world
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		problems = append(problems, ContentError{File: worldFile, Message: err.Error()})
+	}
+
+	if len(problems) > 0 {
+		return Content{}, problems
+	}
+	return content, nil
+}
+
+func loadJSONData(path string, v interface{}) error {
+	err := decodeJSONFile(path, v)
+	var pathErr *fs.PathError
+	if err != nil && !errors.As(err, &pathErr) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return err
+}
+
+// decodeJSONFile decodes the single JSON value in a file into v.
+func decodeJSONFile(path string, v any) error {
 	jsonFile, err := os.Open(path)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	defer jsonFile.Close()
 
-	byteValue, _ := io.ReadAll(jsonFile)
-	json.Unmarshal(byteValue, v)
+	byteValue, err := io.ReadAll(jsonFile)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(byteValue))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(v)
+	if err != nil {
+		return jsonErrorContext(byteValue, err)
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after the top level value")
+	}
+	return nil
 }
 
-func (cfg *DataConfig) StoreActions(actions []Action) {
+// jsonErrorContext adds the line a decoding error was found on.
+func jsonErrorContext(data []byte, err error) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		// Unknown fields carry no offset, so find where the field is named
+		field, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+		if !ok {
+			return err
+		}
+		offset = int64(bytes.Index(data, []byte(field)))
+		if offset < 0 {
+			return err
+		}
+	}
+	line := 1 + bytes.Count(data[:min(offset, int64(len(data)))], []byte("\n"))
+	return fmt.Errorf("line %d: %w", line, err)
+}
+
+func (cfg *DataConfig) StoreToolTypes(ctx context.Context, toolTypes []ToolType) error {
+	if cfg.sync == nil {
+		return errNoSync
+	}
+
+	for _, toolType := range toolTypes {
+		row, err := cfg.DB.UpsertToolType(ctx, toolType.Name)
+		if err != nil {
+			return fmt.Errorf("tool type %s: %w", toolType.Name, err)
+		}
+		cfg.record(kindToolTypes, toolType.Name)(cfg.sync.toolTypes.store(row.Name, row))
+	}
+	return nil
+}
+
+func (cfg *DataConfig) StoreActions(ctx context.Context, actions []Action) error {
+	if cfg.sync == nil {
+		return errNoSync
+	}
+
 	for _, action := range actions {
-		cfg.DB.CreateAction(context.Background(), action.Name)
+		toolTypeID, err := cfg.toolTypeID(action.RequiredToolType)
+		if err != nil {
+			return fmt.Errorf("action %s: %w", action.Name, err)
+		}
+
+		row, err := cfg.DB.UpsertAction(ctx, database.UpsertActionParams{
+			Name:               action.Name,
+			RequiredToolTypeID: toolTypeID,
+		})
+		if err != nil {
+			return fmt.Errorf("action %s: %w", action.Name, err)
+		}
+		cfg.record(kindActions, action.Name)(cfg.sync.actions.store(row.Name, row))
 	}
+	return nil
 }
 
-func (cfg *DataConfig) StoreItems(items []Item) {
+func (cfg *DataConfig) StoreItems(ctx context.Context, items []Item) error {
+	if cfg.sync == nil {
+		return errNoSync
+	}
+
 	for _, item := range items {
-		cfg.DB.CreateItem(context.Background(), database.CreateItemParams{
-			Name:   item.Name,
-			Weight: int32(item.Weight),
+		toolTypeID, err := cfg.toolTypeID(item.ToolType)
+		if err != nil {
+			return fmt.Errorf("item %s: %w", item.Name, err)
+		}
+
+		row, err := cfg.DB.UpsertItem(ctx, database.UpsertItemParams{
+			Name:          item.Name,
+			Weight:        int32(item.Weight),
+			ToolTypeID:    toolTypeID,
+			ToolTier:      int32(item.ToolTier),
+			MaxDurability: pgtype.Int4{Int32: int32(item.MaxDurability), Valid: item.MaxDurability > 0},
 		})
+		if err != nil {
+			return fmt.Errorf("item %s: %w", item.Name, err)
+		}
+		cfg.record(kindItems, item.Name)(cfg.sync.items.store(row.Name, row))
 	}
+	return nil
 }
 
-func (cfg *DataConfig) StoreResourceNodes(resourceNodes []ResourceNode) {
-	for _, resourceNode := range resourceNodes {
-		action, err := cfg.DB.GetActionByName(context.Background(), resourceNode.ActionName)
+// record returns a func that notes the result of storing an entity in the
+// diff.
+func (cfg *DataConfig) record(kind, name string) func(added, changed bool) {
+	return func(added, changed bool) {
+		if added {
+			cfg.sync.diff.added(kind, name)
+		} else if changed {
+			cfg.sync.diff.changed(kind, name)
+		}
+	}
+}
+
+func (cfg *DataConfig) toolTypeID(name string) (pgtype.Int4, error) {
+	if name == "" {
+		return pgtype.Int4{Valid: false}, nil
+	}
+
+	toolType, ok := cfg.sync.toolTypes.stored[name]
+	if !ok {
+		return pgtype.Int4{}, fmt.Errorf("unknown tool type %s", name)
+	}
+
+	return pgtype.Int4{Int32: toolType.ID, Valid: true}, nil
+}
+
+func (cfg *DataConfig) itemID(name string) (int32, error) {
+	item, ok := cfg.sync.items.stored[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown item %s", name)
+	}
+	return item.ID, nil
+}
+
+// markDroppedChildren marks a parent changed when it had children in the
+// database that the content no longer gives it.
+func markDroppedChildren[R comparable](cfg *DataConfig, kind, name string, parentID int32, children *table[childKey, R]) {
+	for key := range children.existing {
+		if key.parent != parentID {
+			continue
+		}
+		if _, ok := children.stored[key]; !ok {
+			cfg.sync.diff.changed(kind, name)
+			return
+		}
+	}
+}
+
+func (cfg *DataConfig) StoreRecipes(ctx context.Context, recipes []Recipe) error {
+	if cfg.sync == nil {
+		return errNoSync
+	}
+
+	for _, recipe := range recipes {
+		itemID, err := cfg.itemID(recipe.ItemName)
+		if err != nil {
+			return fmt.Errorf("recipe %s: %w", recipe.Name, err)
+		}
+
+		recipeRecord, err := cfg.DB.UpsertRecipe(ctx, database.UpsertRecipeParams{
+			Name:     recipe.Name,
+			ItemID:   itemID,
+			Quantity: int32(recipe.Quantity),
+		})
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("recipe %s: %w", recipe.Name, err)
 		}
+		record := cfg.record(kindRecipes, recipe.Name)
+		record(cfg.sync.recipes.store(recipeRecord.Name, recipeRecord))
+
+		for _, ingredient := range recipe.Ingredients {
+			itemID, err := cfg.itemID(ingredient.Name)
+			if err != nil {
+				return fmt.Errorf("recipe %s: %w", recipe.Name, err)
+			}
+
+			row, err := cfg.DB.UpsertRecipeIngredient(ctx, database.UpsertRecipeIngredientParams{
+				RecipeID: recipeRecord.ID,
+				ItemID:   itemID,
+				Quantity: int32(ingredient.Quantity),
+			})
+			if err != nil {
+				return fmt.Errorf("recipe %s ingredient %s: %w", recipe.Name, ingredient.Name, err)
+			}
+			added, changed := cfg.sync.ingredients.store(childKey{row.RecipeID, row.ItemID}, row)
+			record(false, added || changed)
+		}
+		markDroppedChildren(cfg, kindRecipes, recipe.Name, recipeRecord.ID, cfg.sync.ingredients)
+	}
+	return nil
+}
 
-		cfg.DB.CreateResourceNode(context.Background(), database.CreateResourceNodeParams{
-			Name:     resourceNode.Name,
-			ActionID: action.ID,
-			Tier:     int32(resourceNode.Tier),
+func (cfg *DataConfig) StoreResourceNodes(ctx context.Context, resourceNodes []ResourceNode) error {
+	if cfg.sync == nil {
+		return errNoSync
+	}
+
+	for _, resourceNode := range resourceNodes {
+		action, ok := cfg.sync.actions.stored[resourceNode.ActionName]
+		if !ok {
+			return fmt.Errorf("resource node %s: unknown action %s", resourceNode.Name, resourceNode.ActionName)
+		}
+
+		resourceNodeRecord, err := cfg.DB.UpsertResourceNode(ctx, database.UpsertResourceNodeParams{
+			Name:        resourceNode.Name,
+			ActionID:    action.ID,
+			Tier:        int32(resourceNode.Tier),
+			MinToolTier: int32(resourceNode.MinToolTier),
+			MinLevel:    int32(max(resourceNode.MinLevel, 1)),
+			Amount: pgtype.Int4{
+				Int32: int32(resourceNode.Amount),
+				Valid: resourceNode.Amount > 0,
+			},
+			RespawnTicks:  int32(resourceNode.RespawnTicks),
+			IntervalTicks: int32(max(resourceNode.IntervalTicks, 1)),
+			MinQuantity:   int32(max(resourceNode.MinQuantity, 1)),
+			MaxQuantity:   int32(max(resourceNode.MaxQuantity, resourceNode.MinQuantity, 1)),
+			Biome:         resourceNode.Biome,
 		})
-		resourceNodeRecord, err := cfg.DB.GetResourceNodeByName(
-			context.Background(),
-			resourceNode.Name,
-		)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("resource node %s: %w", resourceNode.Name, err)
 		}
+		record := cfg.record(kindResourceNodes, resourceNode.Name)
+		record(cfg.sync.nodes.store(resourceNodeRecord.Name, resourceNodeRecord))
 
 		for _, drop := range resourceNode.Drops {
-			item, err := cfg.DB.GetItemByName(context.Background(), drop.Name)
+			itemID, err := cfg.itemID(drop.Name)
 			if err != nil {
-				panic(err)
+				return fmt.Errorf("resource node %s: %w", resourceNode.Name, err)
 			}
 
-			cfg.DB.CreateResource(context.Background(), database.CreateResourceParams{
+			row, err := cfg.DB.UpsertResource(ctx, database.UpsertResourceParams{
 				ResourceNodeID: resourceNodeRecord.ID,
-				ItemID:         item.ID,
+				ItemID:         itemID,
 				DropChance:     int32(drop.Chance),
 			})
+			if err != nil {
+				return fmt.Errorf("resource node %s drop %s: %w", resourceNode.Name, drop.Name, err)
+			}
+			added, changed := cfg.sync.resources.store(childKey{row.ResourceNodeID, row.ItemID}, row)
+			record(false, added || changed)
 		}
+		markDroppedChildren(cfg, kindResourceNodes, resourceNode.Name, resourceNodeRecord.ID, cfg.sync.resources)
 	}
+	return nil
 }
 
-func (cfg *DataConfig) StoreGridItems(gridItems []Grid) {
+func (cfg *DataConfig) StoreCreatures(ctx context.Context, creatures []Creature) error {
+	if cfg.sync == nil {
+		return errNoSync
+	}
+
+	for _, creature := range creatures {
+		creatureRecord, err := cfg.DB.UpsertCreature(ctx, database.UpsertCreatureParams{
+			Name:         creature.Name,
+			Tier:         int32(creature.Tier),
+			MaxHp:        int32(creature.MaxHP),
+			Damage:       int32(creature.Damage),
+			RespawnTicks: int32(creature.RespawnTicks),
+		})
+		if err != nil {
+			return fmt.Errorf("creature %s: %w", creature.Name, err)
+		}
+		record := cfg.record(kindCreatures, creature.Name)
+		record(cfg.sync.creatures.store(creatureRecord.Name, creatureRecord))
+
+		for _, loot := range creature.Loot {
+			itemID, err := cfg.itemID(loot.Name)
+			if err != nil {
+				return fmt.Errorf("creature %s: %w", creature.Name, err)
+			}
+
+			row, err := cfg.DB.UpsertCreatureDrop(ctx, database.UpsertCreatureDropParams{
+				CreatureID: creatureRecord.ID,
+				ItemID:     itemID,
+				DropChance: int32(loot.Chance),
+				Quantity:   int32(loot.Quantity),
+			})
+			if err != nil {
+				return fmt.Errorf("creature %s loot %s: %w", creature.Name, loot.Name, err)
+			}
+			added, changed := cfg.sync.drops.store(childKey{row.CreatureID, row.ItemID}, row)
+			record(false, added || changed)
+		}
+		markDroppedChildren(cfg, kindCreatures, creature.Name, creatureRecord.ID, cfg.sync.drops)
+	}
+	return nil
+}
+
+func (cfg *DataConfig) StoreGridItems(ctx context.Context, gridItems []Grid) error {
+	if cfg.sync == nil {
+		return errNoSync
+	}
+
 	for _, gridItem := range gridItems {
-		cfg.DB.CreateGridItem(context.Background(), database.CreateGridItemParams{
+		cell := database.Grid{
 			PositionX: int32(gridItem.PositionX),
 			PositionY: int32(gridItem.PositionY),
+		}
+		err := cfg.DB.UpsertGridItem(ctx, database.UpsertGridItemParams{
+			PositionX: cell.PositionX,
+			PositionY: cell.PositionY,
 		})
-		for _, resourceNode := range gridItem.ResourceNodes {
-			resourceNode, err := cfg.DB.GetResourceNodeByName(context.Background(), resourceNode)
+		if err != nil {
+			return fmt.Errorf("grid %s: %w", cellName(cell.PositionX, cell.PositionY), err)
+		}
+		cfg.record(kindGrid, cellName(cell.PositionX, cell.PositionY))(cfg.sync.grid.store(cell, cell))
+
+		for _, name := range gridItem.ResourceNodes {
+			resourceNode, ok := cfg.sync.nodes.stored[name]
+			if !ok {
+				return fmt.Errorf("grid %s: unknown resource node %s", cellName(cell.PositionX, cell.PositionY), name)
+			}
+
+			row, err := cfg.DB.UpsertResourceNodeSpawn(ctx, database.UpsertResourceNodeSpawnParams{
+				NodeID:    resourceNode.ID,
+				PositionX: cell.PositionX,
+				PositionY: cell.PositionY,
+				Remaining: resourceNode.Amount,
+			})
+			if err != nil {
+				return fmt.Errorf("grid %s resource node %s: %w", cellName(cell.PositionX, cell.PositionY), name, err)
+			}
+			key := spawnKey{row.NodeID, row.PositionX, row.PositionY}
+			added, _ := cfg.sync.nodeSpawns.store(key, row)
+			cfg.record(kindResourceSpawns, spawnName(name, row.PositionX, row.PositionY))(added, false)
+		}
+
+		for _, name := range gridItem.Creatures {
+			creature, ok := cfg.sync.creatures.stored[name]
+			if !ok {
+				return fmt.Errorf("grid %s: unknown creature %s", cellName(cell.PositionX, cell.PositionY), name)
+			}
+
+			row, err := cfg.DB.UpsertCreatureSpawn(ctx, database.UpsertCreatureSpawnParams{
+				CreatureID: creature.ID,
+				PositionX:  cell.PositionX,
+				PositionY:  cell.PositionY,
+				Hp:         creature.MaxHp,
+			})
 			if err != nil {
-				panic(err)
+				return fmt.Errorf("grid %s creature %s: %w", cellName(cell.PositionX, cell.PositionY), name, err)
 			}
+			key := spawnKey{row.CreatureID, row.PositionX, row.PositionY}
+			added, _ := cfg.sync.creatureSpawns.store(key, row)
+			cfg.record(kindCreatureSpawns, spawnName(name, row.PositionX, row.PositionY))(added, false)
+		}
+	}
+	return nil
+}
+
+// StoreWorldGeneration records the generator the grid was built with, so
+// the seed that reproduces the map is kept alongside it. A nil world
+// clears the record.
+func (cfg *DataConfig) StoreWorldGeneration(ctx context.Context, world *World) error {
+	if cfg.sync == nil {
+		return errNoSync
+	}
 
-			cfg.DB.CreateResourceNodeSpawn(
-				context.Background(),
-				database.CreateResourceNodeSpawnParams{
-					NodeID:    resourceNode.ID,
-					PositionX: int32(gridItem.PositionX),
-					PositionY: int32(gridItem.PositionY),
-				},
-			)
+	existing := cfg.sync.world
+	if world == nil {
+		if existing == nil {
+			return nil
+		}
+		err := cfg.DB.DeleteWorldGeneration(ctx)
+		if err != nil {
+			return fmt.Errorf("world generation: %w", err)
 		}
+		cfg.sync.diff.removed(kindWorld, worldName(*existing))
+		return nil
+	}
+
+	config, err := json.Marshal(world)
+	if err != nil {
+		return fmt.Errorf("world generation: %w", err)
+	}
+	err = cfg.DB.UpsertWorldGeneration(ctx, database.UpsertWorldGenerationParams{
+		Seed:   world.Seed,
+		Config: config,
+	})
+	if err != nil {
+		return fmt.Errorf("world generation: %w", err)
 	}
+	cfg.record(kindWorld, worldName(*world))(existing == nil, existing != nil && !reflect.DeepEqual(*existing, *world))
+	return nil
 }
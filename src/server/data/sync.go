@@ -0,0 +1,497 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/trbute/idler/server/internal/database"
+)
+
+// Kinds of entity, in the order they are reported
+const (
+	kindToolTypes      = "tool types"
+	kindActions        = "actions"
+	kindItems          = "items"
+	kindRecipes        = "recipes"
+	kindResourceNodes  = "resource nodes"
+	kindCreatures      = "creatures"
+	kindGrid           = "grid"
+	kindResourceSpawns = "resource node spawns"
+	kindCreatureSpawns = "creature spawns"
+	kindWorld          = "world generation"
+)
+
+// childKey identifies a drop or ingredient by its parent and item.
+type childKey struct {
+	parent int32
+	item   int32
+}
+
+// spawnKey identifies a spawn by what spawns and where.
+type spawnKey struct {
+	id int32
+	x  int32
+	y  int32
+}
+
+// table follows one table through a sync: the rows it held before and the
+// rows the content defines, both by natural key.
+type table[K comparable, R comparable] struct {
+	existing map[K]R
+	stored   map[K]R
+}
+
+func newTable[K comparable, R comparable](rows []R, key func(R) K) *table[K, R] {
+	t := &table[K, R]{
+		existing: make(map[K]R, len(rows)),
+		stored:   make(map[K]R),
+	}
+	for _, row := range rows {
+		t.existing[key(row)] = row
+	}
+	return t
+}
+
+// store keeps a row the content defines and reports whether it is new or
+// differs from what was there.
+func (t *table[K, R]) store(key K, row R) (added bool, changed bool) {
+	t.stored[key] = row
+	old, ok := t.existing[key]
+	return !ok, ok && old != row
+}
+
+// ids lists the IDs of every stored row. It is never nil, so a query given
+// it matches no IDs rather than NULL.
+func (t *table[K, R]) ids(id func(R) int32) []int32 {
+	ids := make([]int32, 0, len(t.stored))
+	for _, row := range t.stored {
+		ids = append(ids, id(row))
+	}
+	return ids
+}
+
+// contentSync tracks a content load as the Store functions run.
+type contentSync struct {
+	diff *Diff
+
+	toolTypes      *table[string, database.ToolType]
+	actions        *table[string, database.Action]
+	items          *table[string, database.Item]
+	recipes        *table[string, database.Recipe]
+	ingredients    *table[childKey, database.RecipeIngredient]
+	nodes          *table[string, database.ResourceNode]
+	resources      *table[childKey, database.Resource]
+	creatures      *table[string, database.Creature]
+	drops          *table[childKey, database.CreatureDrop]
+	grid           *table[database.Grid, database.Grid]
+	nodeSpawns     *table[spawnKey, database.ResourceNodeSpawn]
+	creatureSpawns *table[spawnKey, database.CreatureSpawn]
+	// world is the generator the grid was last built with, nil if none
+	world *World
+}
+
+// errNoSync is returned by Store functions called outside SyncContent.
+var errNoSync = errors.New("content can only be stored inside SyncContent")
+
+// newContentSync reads every content table as it stands.
+func newContentSync(ctx context.Context, db *database.Queries) (*contentSync, error) {
+	s := &contentSync{
+		diff: newDiff(kindToolTypes, kindActions, kindItems, kindRecipes,
+			kindResourceNodes, kindCreatures, kindGrid, kindResourceSpawns, kindCreatureSpawns, kindWorld),
+	}
+
+	toolTypes, err := db.ListToolTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.toolTypes = newTable(toolTypes, func(r database.ToolType) string { return r.Name })
+
+	actions, err := db.ListActions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.actions = newTable(actions, func(r database.Action) string { return r.Name })
+
+	items, err := db.ListItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.items = newTable(items, func(r database.Item) string { return r.Name })
+
+	recipes, err := db.GetAllRecipes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.recipes = newTable(recipes, func(r database.Recipe) string { return r.Name })
+
+	ingredients, err := db.ListRecipeIngredients(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.ingredients = newTable(ingredients, func(r database.RecipeIngredient) childKey {
+		return childKey{r.RecipeID, r.ItemID}
+	})
+
+	nodes, err := db.ListResourceNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.nodes = newTable(nodes, func(r database.ResourceNode) string { return r.Name })
+
+	resources, err := db.ListResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.resources = newTable(resources, func(r database.Resource) childKey {
+		return childKey{r.ResourceNodeID, r.ItemID}
+	})
+
+	creatures, err := db.ListCreatures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.creatures = newTable(creatures, func(r database.Creature) string { return r.Name })
+
+	drops, err := db.ListCreatureDrops(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.drops = newTable(drops, func(r database.CreatureDrop) childKey {
+		return childKey{r.CreatureID, r.ItemID}
+	})
+
+	grid, err := db.GetGrid(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.grid = newTable(grid, func(r database.Grid) database.Grid { return r })
+
+	nodeSpawns, err := db.GetResourceNodeSpawns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.nodeSpawns = newTable(nodeSpawns, func(r database.ResourceNodeSpawn) spawnKey {
+		return spawnKey{r.NodeID, r.PositionX, r.PositionY}
+	})
+
+	creatureSpawns, err := db.ListCreatureSpawns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.creatureSpawns = newTable(creatureSpawns, func(r database.CreatureSpawn) spawnKey {
+		return spawnKey{r.CreatureID, r.PositionX, r.PositionY}
+	})
+
+	generations, err := db.GetWorldGeneration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, generation := range generations {
+		s.world = &World{}
+		err = json.Unmarshal(generation.Config, s.world)
+		if err != nil {
+			return nil, fmt.Errorf("world generation: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// SyncContent makes the database match the content in one transaction.
+// Content is validated first and nothing is written if it has problems.
+// Entities are upserted by name, or by parent and position for drops and
+// spawns, so IDs stay put. Whatever the content no longer defines is
+// removed, idling any character busy with it first. The version is only
+// recorded if everything else succeeds.
+func (cfg *DataConfig) SyncContent(ctx context.Context, content Content, version string) (*Diff, error) {
+	err := ValidateContent(content)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := cfg.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	txCfg := *cfg
+	txCfg.DB = cfg.DB.WithTx(tx)
+	txCfg.sync, err = newContentSync(ctx, txCfg.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	err = txCfg.storeContent(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = txCfg.DB.UpdateVersion(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return txCfg.sync.diff, nil
+}
+
+// storeContent stores every kind of entity, parents first, then removes
+// what the content no longer defines.
+func (cfg *DataConfig) storeContent(ctx context.Context, content Content) error {
+	steps := []func() error{
+		func() error { return cfg.StoreToolTypes(ctx, content.ToolTypes) },
+		func() error { return cfg.StoreActions(ctx, content.Actions) },
+		func() error { return cfg.StoreItems(ctx, content.Items) },
+		func() error { return cfg.StoreRecipes(ctx, content.Recipes) },
+		func() error { return cfg.StoreResourceNodes(ctx, content.ResourceNodes) },
+		func() error { return cfg.StoreCreatures(ctx, content.Creatures) },
+		func() error { return cfg.StoreGridItems(ctx, content.FullGrid()) },
+		func() error { return cfg.StoreWorldGeneration(ctx, content.World) },
+		func() error { return cfg.removeUndefined(ctx) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeUndefined deletes everything the content stopped defining, children
+// before parents. Characters doing something that goes away are idled first,
+// since most references to content cascade to the character.
+func (cfg *DataConfig) removeUndefined(ctx context.Context) error {
+	s := cfg.sync
+
+	idle, ok := s.actions.stored["IDLE"]
+	if !ok {
+		return errors.New("actions must define IDLE")
+	}
+
+	idled, err := cfg.DB.IdleCharactersUsingRemovedContent(ctx, database.IdleCharactersUsingRemovedContentParams{
+		IdleActionID:         idle.ID,
+		ActionIds:            s.actions.ids(func(r database.Action) int32 { return r.ID }),
+		ResourceNodeSpawnIds: s.nodeSpawns.ids(func(r database.ResourceNodeSpawn) int32 { return r.ID }),
+		CreatureSpawnIds:     s.creatureSpawns.ids(func(r database.CreatureSpawn) int32 { return r.ID }),
+		RecipeIds:            s.recipes.ids(func(r database.Recipe) int32 { return r.ID }),
+	})
+	if err != nil {
+		return err
+	}
+	for _, character := range idled {
+		s.diff.Idled = append(s.diff.Idled, character.Name)
+	}
+	sort.Strings(s.diff.Idled)
+
+	nodeNames := make(map[int32]string)
+	for _, table := range []map[string]database.ResourceNode{s.nodes.existing, s.nodes.stored} {
+		for name, node := range table {
+			nodeNames[node.ID] = name
+		}
+	}
+	removedNodeSpawns, err := cfg.DB.DeleteResourceNodeSpawnsExcept(ctx,
+		s.nodeSpawns.ids(func(r database.ResourceNodeSpawn) int32 { return r.ID }))
+	if err != nil {
+		return err
+	}
+	for _, spawn := range removedNodeSpawns {
+		s.diff.removed(kindResourceSpawns, spawnName(nodeNames[spawn.NodeID], spawn.PositionX, spawn.PositionY))
+	}
+
+	creatureNames := make(map[int32]string)
+	for _, table := range []map[string]database.Creature{s.creatures.existing, s.creatures.stored} {
+		for name, creature := range table {
+			creatureNames[creature.ID] = name
+		}
+	}
+	removedCreatureSpawns, err := cfg.DB.DeleteCreatureSpawnsExcept(ctx,
+		s.creatureSpawns.ids(func(r database.CreatureSpawn) int32 { return r.ID }))
+	if err != nil {
+		return err
+	}
+	for _, spawn := range removedCreatureSpawns {
+		s.diff.removed(kindCreatureSpawns, spawnName(creatureNames[spawn.CreatureID], spawn.PositionX, spawn.PositionY))
+	}
+
+	err = cfg.DB.DeleteResourcesExcept(ctx, s.resources.ids(func(r database.Resource) int32 { return r.ID }))
+	if err != nil {
+		return err
+	}
+	err = cfg.DB.DeleteCreatureDropsExcept(ctx, s.drops.ids(func(r database.CreatureDrop) int32 { return r.ID }))
+	if err != nil {
+		return err
+	}
+	err = cfg.DB.DeleteRecipeIngredientsExcept(ctx, s.ingredients.ids(func(r database.RecipeIngredient) int32 { return r.ID }))
+	if err != nil {
+		return err
+	}
+
+	err = cfg.removeUndefinedGrid(ctx)
+	if err != nil {
+		return err
+	}
+
+	removedCreatures, err := cfg.DB.DeleteCreaturesExcept(ctx, s.creatures.ids(func(r database.Creature) int32 { return r.ID }))
+	if err != nil {
+		return err
+	}
+	for _, row := range removedCreatures {
+		s.diff.removed(kindCreatures, row.Name)
+	}
+
+	removedNodes, err := cfg.DB.DeleteResourceNodesExcept(ctx, s.nodes.ids(func(r database.ResourceNode) int32 { return r.ID }))
+	if err != nil {
+		return err
+	}
+	for _, row := range removedNodes {
+		s.diff.removed(kindResourceNodes, row.Name)
+	}
+
+	removedRecipes, err := cfg.DB.DeleteRecipesExcept(ctx, s.recipes.ids(func(r database.Recipe) int32 { return r.ID }))
+	if err != nil {
+		return err
+	}
+	for _, row := range removedRecipes {
+		s.diff.removed(kindRecipes, row.Name)
+	}
+
+	err = cfg.removeUndefinedItems(ctx)
+	if err != nil {
+		return err
+	}
+
+	removedActions, err := cfg.DB.DeleteActionsExcept(ctx, s.actions.ids(func(r database.Action) int32 { return r.ID }))
+	if err != nil {
+		return err
+	}
+	for _, row := range removedActions {
+		s.diff.removed(kindActions, row.Name)
+	}
+
+	removedToolTypes, err := cfg.DB.DeleteToolTypesExcept(ctx, s.toolTypes.ids(func(r database.ToolType) int32 { return r.ID }))
+	if err != nil {
+		return err
+	}
+	for _, row := range removedToolTypes {
+		s.diff.removed(kindToolTypes, row.Name)
+	}
+
+	return nil
+}
+
+// removeUndefinedGrid deletes grid cells the content dropped. Characters and
+// stashes would be deleted along with their cell, so a cell still holding
+// any stops the sync instead.
+func (cfg *DataConfig) removeUndefinedGrid(ctx context.Context) error {
+	s := cfg.sync
+
+	var removed []database.Grid
+	for cell := range s.grid.existing {
+		if _, ok := s.grid.stored[cell]; !ok {
+			removed = append(removed, cell)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i].PositionX != removed[j].PositionX {
+			return removed[i].PositionX < removed[j].PositionX
+		}
+		return removed[i].PositionY < removed[j].PositionY
+	})
+
+	occupied, err := cfg.DB.GetOccupiedGridItems(ctx)
+	if err != nil {
+		return err
+	}
+	var blocked []string
+	for _, cell := range occupied {
+		if _, ok := s.grid.stored[cell]; !ok {
+			blocked = append(blocked, cellName(cell.PositionX, cell.PositionY))
+		}
+	}
+	if len(blocked) > 0 {
+		return fmt.Errorf("can't remove grid cells that still hold characters or stashes: %s", strings.Join(blocked, ", "))
+	}
+
+	for _, cell := range removed {
+		err := cfg.DB.DeleteGridItem(ctx, database.DeleteGridItemParams{
+			PositionX: cell.PositionX,
+			PositionY: cell.PositionY,
+		})
+		if err != nil {
+			return err
+		}
+		s.diff.removed(kindGrid, cellName(cell.PositionX, cell.PositionY))
+	}
+
+	return nil
+}
+
+// removeUndefinedItems deletes items the content dropped. Held stacks would
+// be deleted along with their item, so an item anyone still holds stops the
+// sync instead. Inventory weights are worked out again whenever an item
+// changed or went, since they were added up with the old weights.
+func (cfg *DataConfig) removeUndefinedItems(ctx context.Context) error {
+	s := cfg.sync
+	ids := s.items.ids(func(r database.Item) int32 { return r.ID })
+
+	holders, err := cfg.DB.GetItemHoldersExcept(ctx, ids)
+	if err != nil {
+		return err
+	}
+	var blocked []string
+	for _, holder := range holders {
+		blocked = append(blocked, fmt.Sprintf("%s held by %s", holder.ItemName, holderName(holder)))
+	}
+	if len(blocked) > 0 {
+		return fmt.Errorf("can't remove items that are still held: %s", strings.Join(blocked, ", "))
+	}
+
+	removedItems, err := cfg.DB.DeleteItemsExcept(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, row := range removedItems {
+		s.diff.removed(kindItems, row.Name)
+	}
+
+	items := s.diff.kind(kindItems)
+	if len(items.Changed) == 0 && len(items.Removed) == 0 {
+		return nil
+	}
+	return cfg.DB.RecomputeInventoryWeights(ctx)
+}
+
+func holderName(holder database.GetItemHoldersExceptRow) string {
+	switch {
+	case holder.CharacterName.Valid:
+		return holder.CharacterName.String
+	case holder.UserID.Valid:
+		return "a stash at " + cellName(holder.PositionX, holder.PositionY)
+	default:
+		return "the ground at " + cellName(holder.PositionX, holder.PositionY)
+	}
+}
+
+func cellName(x, y int32) string {
+	return fmt.Sprintf("(%d, %d)", x, y)
+}
+
+func spawnName(name string, x, y int32) string {
+	return fmt.Sprintf("%s at %s", name, cellName(x, y))
+}
+
+func worldName(world World) string {
+	return fmt.Sprintf("seed %d, %dx%d", world.Seed, world.Width, world.Height)
+}
@@ -0,0 +1,277 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Content files, relative to the data directory
+const (
+	toolTypesFile     = "tool_types.json"
+	actionsFile       = "actions.json"
+	itemsFile         = "items.json"
+	recipesFile       = "recipes.json"
+	resourceNodesFile = "resource_nodes.json"
+	creaturesFile     = "creatures.json"
+	gridFile          = "grid.json"
+	worldFile         = "world.json"
+	versionFile       = "version.json"
+)
+
+// ContentError is one problem found in the content files.
+type ContentError struct {
+	File    string
+	Entry   string
+	Message string
+}
+
+func (e ContentError) Error() string {
+	if e.Entry == "" {
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.File, e.Entry, e.Message)
+}
+
+// ContentErrors is every problem found in the content, in file order.
+type ContentErrors []ContentError
+
+func (e ContentErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return fmt.Sprintf("%d problems in world data:\n%s", len(e), strings.Join(lines, "\n"))
+}
+
+// contentCheck collects problems while content is validated.
+type contentCheck struct {
+	errs ContentErrors
+}
+
+func (c *contentCheck) fail(file, entry, format string, args ...any) {
+	c.errs = append(c.errs, ContentError{File: file, Entry: entry, Message: fmt.Sprintf(format, args...)})
+}
+
+func entryName(index int, name string) string {
+	if name == "" {
+		return fmt.Sprintf("entry %d", index+1)
+	}
+	return fmt.Sprintf("%s (entry %d)", name, index+1)
+}
+
+// names checks every entry of a file has a unique name and returns the
+// set of names.
+func (c *contentCheck) names(file string, count int, name func(int) string) map[string]bool {
+	seen := make(map[string]bool, count)
+	for i := range count {
+		n := name(i)
+		if n == "" {
+			c.fail(file, entryName(i, ""), "name is missing")
+			continue
+		}
+		if seen[n] {
+			c.fail(file, entryName(i, n), "duplicate name")
+			continue
+		}
+		seen[n] = true
+	}
+	return seen
+}
+
+// ValidateContent checks every reference and rule across the content
+// files before anything is written. It returns all the problems at once
+// so they can be fixed in one go, or nil when there are none.
+func ValidateContent(content Content) error {
+	c := &contentCheck{}
+
+	toolTypes := c.names(toolTypesFile, len(content.ToolTypes), func(i int) string { return content.ToolTypes[i].Name })
+
+	actions := c.names(actionsFile, len(content.Actions), func(i int) string { return content.Actions[i].Name })
+	if !actions["IDLE"] {
+		c.fail(actionsFile, "", "IDLE must be defined")
+	}
+	for i, action := range content.Actions {
+		if action.RequiredToolType != "" && !toolTypes[action.RequiredToolType] {
+			c.fail(actionsFile, entryName(i, action.Name), "required_tool_type %s is not in %s", action.RequiredToolType, toolTypesFile)
+		}
+	}
+
+	items := c.names(itemsFile, len(content.Items), func(i int) string { return content.Items[i].Name })
+	for i, item := range content.Items {
+		entry := entryName(i, item.Name)
+		if item.ToolType != "" && !toolTypes[item.ToolType] {
+			c.fail(itemsFile, entry, "tool_type %s is not in %s", item.ToolType, toolTypesFile)
+		}
+		if item.Weight < 0 {
+			c.fail(itemsFile, entry, "weight can't be negative")
+		}
+		if item.ToolTier < 0 {
+			c.fail(itemsFile, entry, "tool_tier can't be negative")
+		}
+		if item.MaxDurability < 0 {
+			c.fail(itemsFile, entry, "max_durability can't be negative")
+		}
+	}
+
+	c.names(recipesFile, len(content.Recipes), func(i int) string { return content.Recipes[i].Name })
+	for i, recipe := range content.Recipes {
+		entry := entryName(i, recipe.Name)
+		if !items[recipe.ItemName] {
+			c.fail(recipesFile, entry, "item_name %s is not in %s", recipe.ItemName, itemsFile)
+		}
+		if recipe.Quantity <= 0 {
+			c.fail(recipesFile, entry, "quantity must be more than 0")
+		}
+		if len(recipe.Ingredients) == 0 {
+			c.fail(recipesFile, entry, "has no ingredients")
+		}
+		seen := make(map[string]bool)
+		for _, ingredient := range recipe.Ingredients {
+			if !items[ingredient.Name] {
+				c.fail(recipesFile, entry, "ingredient %s is not in %s", ingredient.Name, itemsFile)
+			}
+			if seen[ingredient.Name] {
+				c.fail(recipesFile, entry, "ingredient %s is listed twice", ingredient.Name)
+			}
+			seen[ingredient.Name] = true
+			if ingredient.Quantity <= 0 {
+				c.fail(recipesFile, entry, "ingredient %s quantity must be more than 0", ingredient.Name)
+			}
+		}
+	}
+
+	nodes := c.names(resourceNodesFile, len(content.ResourceNodes), func(i int) string { return content.ResourceNodes[i].Name })
+	for i, node := range content.ResourceNodes {
+		entry := entryName(i, node.Name)
+		if !actions[node.ActionName] {
+			c.fail(resourceNodesFile, entry, "action_name %s is not in %s", node.ActionName, actionsFile)
+		}
+		if node.MinLevel < 0 {
+			c.fail(resourceNodesFile, entry, "min_level can't be negative")
+		}
+		if node.IntervalTicks < 0 {
+			c.fail(resourceNodesFile, entry, "interval_ticks can't be negative")
+		}
+		if node.MaxQuantity != 0 && node.MaxQuantity < node.MinQuantity {
+			c.fail(resourceNodesFile, entry, "max_quantity is less than min_quantity")
+		}
+
+		total := 0
+		seen := make(map[string]bool)
+		for _, drop := range node.Drops {
+			if !items[drop.Name] {
+				c.fail(resourceNodesFile, entry, "drop %s is not in %s", drop.Name, itemsFile)
+			}
+			if seen[drop.Name] {
+				c.fail(resourceNodesFile, entry, "drop %s is listed twice", drop.Name)
+			}
+			seen[drop.Name] = true
+			if drop.Chance < 0 {
+				c.fail(resourceNodesFile, entry, "drop %s chance can't be negative", drop.Name)
+			}
+			total += drop.Chance
+		}
+		if total <= 0 {
+			c.fail(resourceNodesFile, entry, "drop chances must add up to more than 0")
+		}
+	}
+
+	creatures := c.names(creaturesFile, len(content.Creatures), func(i int) string { return content.Creatures[i].Name })
+	for i, creature := range content.Creatures {
+		entry := entryName(i, creature.Name)
+		if creature.MaxHP <= 0 {
+			c.fail(creaturesFile, entry, "max_hp must be more than 0")
+		}
+
+		total := 0
+		seen := make(map[string]bool)
+		for _, loot := range creature.Loot {
+			if !items[loot.Name] {
+				c.fail(creaturesFile, entry, "loot %s is not in %s", loot.Name, itemsFile)
+			}
+			if seen[loot.Name] {
+				c.fail(creaturesFile, entry, "loot %s is listed twice", loot.Name)
+			}
+			seen[loot.Name] = true
+			if loot.Chance < 0 {
+				c.fail(creaturesFile, entry, "loot %s chance can't be negative", loot.Name)
+			}
+			if loot.Quantity <= 0 {
+				c.fail(creaturesFile, entry, "loot %s quantity must be more than 0", loot.Name)
+			}
+			total += loot.Chance
+		}
+		if len(creature.Loot) > 0 && total <= 0 {
+			c.fail(creaturesFile, entry, "loot chances must add up to more than 0")
+		}
+	}
+
+	cells := make(map[[2]int]bool)
+	for i, cell := range content.Grid {
+		entry := fmt.Sprintf("(%d, %d) (entry %d)", cell.PositionX, cell.PositionY, i+1)
+		key := [2]int{cell.PositionX, cell.PositionY}
+		if cells[key] {
+			c.fail(gridFile, entry, "duplicate position")
+		}
+		cells[key] = true
+
+		seen := make(map[string]bool)
+		for _, name := range cell.ResourceNodes {
+			if !nodes[name] {
+				c.fail(gridFile, entry, "resource node %s is not in %s", name, resourceNodesFile)
+			}
+			if seen[name] {
+				c.fail(gridFile, entry, "resource node %s is listed twice", name)
+			}
+			seen[name] = true
+		}
+
+		seen = make(map[string]bool)
+		for _, name := range cell.Creatures {
+			if !creatures[name] {
+				c.fail(gridFile, entry, "creature %s is not in %s", name, creaturesFile)
+			}
+			if seen[name] {
+				c.fail(gridFile, entry, "creature %s is listed twice", name)
+			}
+			seen[name] = true
+		}
+	}
+	if content.World != nil {
+		c.world(*content.World, content.ResourceNodes)
+	} else if !cells[[2]int{0, 0}] {
+		c.fail(gridFile, "", "(0, 0) must be defined, new characters and stashes start there")
+	}
+
+	if len(c.errs) > 0 {
+		return c.errs
+	}
+	return nil
+}
+
+// world checks the generator settings and that every biome a resource
+// node asks for is one the generator places.
+func (c *contentCheck) world(world World, nodes []ResourceNode) {
+	if world.Width <= 0 || world.Height <= 0 {
+		c.fail(worldFile, "", "width and height must be more than 0")
+	}
+	if world.RegionSize < 0 || world.TierDistance < 0 || world.NodesPerCell < 0 || world.CreaturesPerCell < 0 {
+		c.fail(worldFile, "", "region_size, tier_distance, nodes_per_cell and creatures_per_cell can't be negative")
+	}
+
+	if len(world.Biomes) == 0 {
+		c.fail(worldFile, "", "at least one biome must be defined")
+	}
+	biomes := c.names(worldFile, len(world.Biomes), func(i int) string { return world.Biomes[i].Name })
+	for i, biome := range world.Biomes {
+		if biome.Weight < 0 {
+			c.fail(worldFile, entryName(i, biome.Name), "weight can't be negative")
+		}
+	}
+
+	for i, node := range nodes {
+		if node.Biome != "" && !biomes[node.Biome] {
+			c.fail(resourceNodesFile, entryName(i, node.Name), "biome %s is not in %s", node.Biome, worldFile)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package data
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShippedContentIsValid(t *testing.T) {
+	content, err := LoadContent("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateContent(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateContentReportsEveryProblem(t *testing.T) {
+	content := Content{
+		ToolTypes: []ToolType{{Name: "AXE"}},
+		Actions: []Action{
+			{Name: "IDLE"},
+			{Name: "WOODCUTTING", RequiredToolType: "AXE"},
+			{Name: "MINING", RequiredToolType: "PICKAXE"},
+		},
+		Items: []Item{{Name: "LOGS", Weight: 1}, {Name: "LOGS", Weight: 2}},
+		ResourceNodes: []ResourceNode{
+			{Name: "OAK", ActionName: "WOODCUTING", Drops: []Drop{{Name: "LOGS", Chance: 100}}},
+			{Name: "ELM", ActionName: "WOODCUTTING", Drops: []Drop{{Name: "LOG", Chance: 0}}},
+		},
+		Grid: []Grid{{PositionX: 0, PositionY: 0, ResourceNodes: []string{"OAK", "PINE"}}},
+	}
+
+	err := ValidateContent(content)
+	var problems ContentErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("ValidateContent() = %v, want ContentErrors", err)
+	}
+
+	want := []string{
+		"actions.json: MINING (entry 3): required_tool_type PICKAXE is not in tool_types.json",
+		"items.json: LOGS (entry 2): duplicate name",
+		"resource_nodes.json: OAK (entry 1): action_name WOODCUTING is not in actions.json",
+		"resource_nodes.json: ELM (entry 2): drop LOG is not in items.json",
+		"resource_nodes.json: ELM (entry 2): drop chances must add up to more than 0",
+		"grid.json: (0, 0) (entry 1): resource node PINE is not in resource_nodes.json",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %d problems, want %d:\n%v", len(problems), len(want), err)
+	}
+	for i, problem := range problems {
+		if problem.Error() != want[i] {
+			t.Errorf("problem %d = %q, want %q", i, problem.Error(), want[i])
+		}
+	}
+}
+
+func TestLoadJSONDataRejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.json")
+	data := "[\n  {\n    \"name\": \"LOGS\",\n    \"wieght\": 1\n  }\n]\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var items []Item
+	err := loadJSONData(path, &items)
+	if err == nil {
+		t.Fatal("loadJSONData() = nil, want an unknown field error")
+	}
+	if !strings.Contains(err.Error(), "line 4") || !strings.Contains(err.Error(), "wieght") {
+		t.Errorf("error %q should name the field and its line", err)
+	}
+}
+
+func TestValidateContentRejectsNegativeNumbers(t *testing.T) {
+	content := Content{
+		Actions: []Action{{Name: "IDLE"}, {Name: "HUNTING"}},
+		Items:   []Item{{Name: "BOW", ToolTier: -1, MaxDurability: -5}, {Name: "HIDE", Weight: 1}},
+		ResourceNodes: []ResourceNode{
+			{Name: "DEER", ActionName: "HUNTING", MinLevel: -1, IntervalTicks: -2, Drops: []Drop{{Name: "HIDE", Chance: 1}}},
+		},
+		Creatures: []Creature{
+			{Name: "WOLF", MaxHP: 5, Loot: []Loot{{Name: "HIDE", Chance: 1, Quantity: 0}}},
+		},
+		Grid: []Grid{{PositionX: 0, PositionY: 0}},
+	}
+
+	err := ValidateContent(content)
+	var problems ContentErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("ValidateContent() = %v, want ContentErrors", err)
+	}
+
+	want := []string{
+		"items.json: BOW (entry 1): tool_tier can't be negative",
+		"items.json: BOW (entry 1): max_durability can't be negative",
+		"resource_nodes.json: DEER (entry 1): min_level can't be negative",
+		"resource_nodes.json: DEER (entry 1): interval_ticks can't be negative",
+		"creatures.json: WOLF (entry 1): loot HIDE quantity must be more than 0",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %d problems, want %d:\n%v", len(problems), len(want), err)
+	}
+	for i, problem := range problems {
+		if problem.Error() != want[i] {
+			t.Errorf("problem %d = %q, want %q", i, problem.Error(), want[i])
+		}
+	}
+}
+
+func TestLoadContentReportsEveryBadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		toolTypesFile:     "[]",
+		actionsFile:       "[{\"name\": \"IDLE\", \"speed\": 1}]",
+		itemsFile:         "[]",
+		recipesFile:       "[",
+		resourceNodesFile: "[]",
+		creaturesFile:     "[]",
+		gridFile:          "[]",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := LoadContent(dir)
+	var problems ContentErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("LoadContent() = %v, want ContentErrors", err)
+	}
+	if len(problems) != 2 || problems[0].File != actionsFile || problems[1].File != recipesFile {
+		t.Errorf("LoadContent() = %v, want problems in %s and %s", err, actionsFile, recipesFile)
+	}
+}
@@ -7,15 +7,33 @@ package database
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createAction = `-- name: CreateAction :exec
-INSERT INTO actions (name) VALUES ($1)
+const deleteActionsExcept = `-- name: DeleteActionsExcept :many
+DELETE FROM actions WHERE id <> ALL($1::INTEGER[])
+RETURNING id, name, required_tool_type_id
 `
 
-func (q *Queries) CreateAction(ctx context.Context, name string) error {
-	_, err := q.db.Exec(ctx, createAction, name)
-	return err
+func (q *Queries) DeleteActionsExcept(ctx context.Context, ids []int32) ([]Action, error) {
+	rows, err := q.db.Query(ctx, deleteActionsExcept, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Action
+	for rows.Next() {
+		var i Action
+		if err := rows.Scan(&i.ID, &i.Name, &i.RequiredToolTypeID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getActionById = `-- name: GetActionById :one
@@ -70,3 +88,45 @@ func (q *Queries) GetAllActions(ctx context.Context) ([]GetAllActionsRow, error)
 	}
 	return items, nil
 }
+
+const listActions = `-- name: ListActions :many
+SELECT id, name, required_tool_type_id FROM actions ORDER BY id
+`
+
+func (q *Queries) ListActions(ctx context.Context) ([]Action, error) {
+	rows, err := q.db.Query(ctx, listActions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Action
+	for rows.Next() {
+		var i Action
+		if err := rows.Scan(&i.ID, &i.Name, &i.RequiredToolTypeID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertAction = `-- name: UpsertAction :one
+INSERT INTO actions (name, required_tool_type_id) VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE SET required_tool_type_id = EXCLUDED.required_tool_type_id
+RETURNING id, name, required_tool_type_id
+`
+
+type UpsertActionParams struct {
+	Name               string
+	RequiredToolTypeID pgtype.Int4
+}
+
+func (q *Queries) UpsertAction(ctx context.Context, arg UpsertActionParams) (Action, error) {
+	row := q.db.QueryRow(ctx, upsertAction, arg.Name, arg.RequiredToolTypeID)
+	var i Action
+	err := row.Scan(&i.ID, &i.Name, &i.RequiredToolTypeID)
+	return i, err
+}
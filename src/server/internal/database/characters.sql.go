@@ -11,12 +11,68 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const batchDamageCharacters = `-- name: BatchDamageCharacters :many
+UPDATE characters AS c
+SET hp = GREATEST(c.hp - d.damage, 0),
+	updated_at = NOW()
+FROM (
+	SELECT unnest($1::UUID[]) AS id, unnest($2::INTEGER[]) AS damage
+) AS d
+WHERE c.id = d.id
+RETURNING c.id, c.user_id, c.name, c.position_x, c.position_y, c.action_id, c.action_target, c.created_at, c.updated_at, c.action_amount_limit, c.action_amount_progress, c.destination_x, c.destination_y, c.action_recipe_id, c.hp, c.max_hp, c.action_creature_target, c.action_ticks
+`
+
+type BatchDamageCharactersParams struct {
+	Ids     []pgtype.UUID
+	Damages []int32
+}
+
+func (q *Queries) BatchDamageCharacters(ctx context.Context, arg BatchDamageCharactersParams) ([]Character, error) {
+	rows, err := q.db.Query(ctx, batchDamageCharacters, arg.Ids, arg.Damages)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Character
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.PositionX,
+			&i.PositionY,
+			&i.ActionID,
+			&i.ActionTarget,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ActionAmountLimit,
+			&i.ActionAmountProgress,
+			&i.DestinationX,
+			&i.DestinationY,
+			&i.ActionRecipeID,
+			&i.Hp,
+			&i.MaxHp,
+			&i.ActionCreatureTarget,
+			&i.ActionTicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const batchUpdateCharacterProgress = `-- name: BatchUpdateCharacterProgress :exec
 UPDATE characters AS c
 SET action_amount_progress = updates.progress,
+	action_ticks = updates.ticks,
 	updated_at = NOW()
 FROM (
-	SELECT unnest($1::UUID[]) AS id, unnest($2::INTEGER[]) AS progress
+	SELECT unnest($1::UUID[]) AS id, unnest($2::INTEGER[]) AS progress, unnest($3::INTEGER[]) AS ticks
 ) AS updates
 WHERE c.id = updates.id
 `
@@ -24,13 +80,60 @@ WHERE c.id = updates.id
 type BatchUpdateCharacterProgressParams struct {
 	Column1 []pgtype.UUID
 	Column2 []int32
+	Column3 []int32
 }
 
 func (q *Queries) BatchUpdateCharacterProgress(ctx context.Context, arg BatchUpdateCharacterProgressParams) error {
-	_, err := q.db.Exec(ctx, batchUpdateCharacterProgress, arg.Column1, arg.Column2)
+	_, err := q.db.Exec(ctx, batchUpdateCharacterProgress, arg.Column1, arg.Column2, arg.Column3)
 	return err
 }
 
+const completeCharacterTravel = `-- name: CompleteCharacterTravel :one
+UPDATE characters
+SET position_x = destination_x,
+	position_y = destination_y,
+	action_id = $1,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_amount_limit = NULL,
+	action_amount_progress = 0,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE id = $2 AND destination_x IS NOT NULL AND destination_y IS NOT NULL
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
+`
+
+type CompleteCharacterTravelParams struct {
+	ActionID int32
+	ID       pgtype.UUID
+}
+
+func (q *Queries) CompleteCharacterTravel(ctx context.Context, arg CompleteCharacterTravelParams) (Character, error) {
+	row := q.db.QueryRow(ctx, completeCharacterTravel, arg.ActionID, arg.ID)
+	var i Character
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.PositionX,
+		&i.PositionY,
+		&i.ActionID,
+		&i.ActionTarget,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ActionAmountLimit,
+		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
+	)
+	return i, err
+}
+
 const createCharacter = `-- name: CreateCharacter :one
 INSERT INTO characters(id, user_id, name, created_at, updated_at)
 VALUES (
@@ -40,7 +143,7 @@ VALUES (
 	NOW(),
 	NOW()
 )
-RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
 `
 
 type CreateCharacterParams struct {
@@ -63,12 +166,19 @@ func (q *Queries) CreateCharacter(ctx context.Context, arg CreateCharacterParams
 		&i.UpdatedAt,
 		&i.ActionAmountLimit,
 		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
 	)
 	return i, err
 }
 
 const getActiveCharacters = `-- name: GetActiveCharacters :many
-SELECT id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress FROM characters
+SELECT id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks FROM characters
 WHERE action_id != 1
 `
 
@@ -93,6 +203,13 @@ func (q *Queries) GetActiveCharacters(ctx context.Context) ([]Character, error)
 			&i.UpdatedAt,
 			&i.ActionAmountLimit,
 			&i.ActionAmountProgress,
+			&i.DestinationX,
+			&i.DestinationY,
+			&i.ActionRecipeID,
+			&i.Hp,
+			&i.MaxHp,
+			&i.ActionCreatureTarget,
+			&i.ActionTicks,
 		); err != nil {
 			return nil, err
 		}
@@ -105,7 +222,7 @@ func (q *Queries) GetActiveCharacters(ctx context.Context) ([]Character, error)
 }
 
 const getCharacterById = `-- name: GetCharacterById :one
-SELECT id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress from characters
+SELECT id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks from characters
 WHERE id = $1
 `
 
@@ -124,12 +241,19 @@ func (q *Queries) GetCharacterById(ctx context.Context, id pgtype.UUID) (Charact
 		&i.UpdatedAt,
 		&i.ActionAmountLimit,
 		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
 	)
 	return i, err
 }
 
 const getCharacterByName = `-- name: GetCharacterByName :one
-SELECT id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress from characters
+SELECT id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks from characters
 where name = $1
 `
 
@@ -148,12 +272,63 @@ func (q *Queries) GetCharacterByName(ctx context.Context, name string) (Characte
 		&i.UpdatedAt,
 		&i.ActionAmountLimit,
 		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
 	)
 	return i, err
 }
 
+const getCharactersByActionTarget = `-- name: GetCharactersByActionTarget :many
+SELECT id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks FROM characters
+WHERE action_target = $1
+`
+
+func (q *Queries) GetCharactersByActionTarget(ctx context.Context, actionTarget pgtype.Int4) ([]Character, error) {
+	rows, err := q.db.Query(ctx, getCharactersByActionTarget, actionTarget)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Character
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.PositionX,
+			&i.PositionY,
+			&i.ActionID,
+			&i.ActionTarget,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ActionAmountLimit,
+			&i.ActionAmountProgress,
+			&i.DestinationX,
+			&i.DestinationY,
+			&i.ActionRecipeID,
+			&i.Hp,
+			&i.MaxHp,
+			&i.ActionCreatureTarget,
+			&i.ActionTicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getCharactersByCoordinates = `-- name: GetCharactersByCoordinates :many
-SELECT id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress from characters
+SELECT id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks from characters
 WHERE position_x = $1 AND position_y = $2
 `
 
@@ -183,6 +358,57 @@ func (q *Queries) GetCharactersByCoordinates(ctx context.Context, arg GetCharact
 			&i.UpdatedAt,
 			&i.ActionAmountLimit,
 			&i.ActionAmountProgress,
+			&i.DestinationX,
+			&i.DestinationY,
+			&i.ActionRecipeID,
+			&i.Hp,
+			&i.MaxHp,
+			&i.ActionCreatureTarget,
+			&i.ActionTicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCharactersByCreatureTarget = `-- name: GetCharactersByCreatureTarget :many
+SELECT id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks FROM characters
+WHERE action_creature_target = $1
+`
+
+func (q *Queries) GetCharactersByCreatureTarget(ctx context.Context, actionCreatureTarget pgtype.Int4) ([]Character, error) {
+	rows, err := q.db.Query(ctx, getCharactersByCreatureTarget, actionCreatureTarget)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Character
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.PositionX,
+			&i.PositionY,
+			&i.ActionID,
+			&i.ActionTarget,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ActionAmountLimit,
+			&i.ActionAmountProgress,
+			&i.DestinationX,
+			&i.DestinationY,
+			&i.ActionRecipeID,
+			&i.Hp,
+			&i.MaxHp,
+			&i.ActionCreatureTarget,
+			&i.ActionTicks,
 		); err != nil {
 			return nil, err
 		}
@@ -194,15 +420,160 @@ func (q *Queries) GetCharactersByCoordinates(ctx context.Context, arg GetCharact
 	return items, nil
 }
 
+const idleCharactersUsingRemovedContent = `-- name: IdleCharactersUsingRemovedContent :many
+UPDATE characters
+SET action_id = $1,
+	action_target = NULL,
+	action_amount_limit = NULL,
+	action_amount_progress = 0,
+	action_recipe_id = NULL,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_creature_target = NULL,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE action_id <> ALL($2::INTEGER[])
+	OR action_target <> ALL($3::INTEGER[])
+	OR action_creature_target <> ALL($4::INTEGER[])
+	OR action_recipe_id <> ALL($5::INTEGER[])
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
+`
+
+type IdleCharactersUsingRemovedContentParams struct {
+	IdleActionID         int32
+	ActionIds            []int32
+	ResourceNodeSpawnIds []int32
+	CreatureSpawnIds     []int32
+	RecipeIds            []int32
+}
+
+func (q *Queries) IdleCharactersUsingRemovedContent(ctx context.Context, arg IdleCharactersUsingRemovedContentParams) ([]Character, error) {
+	rows, err := q.db.Query(ctx, idleCharactersUsingRemovedContent,
+		arg.IdleActionID,
+		arg.ActionIds,
+		arg.ResourceNodeSpawnIds,
+		arg.CreatureSpawnIds,
+		arg.RecipeIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Character
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.PositionX,
+			&i.PositionY,
+			&i.ActionID,
+			&i.ActionTarget,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ActionAmountLimit,
+			&i.ActionAmountProgress,
+			&i.DestinationX,
+			&i.DestinationY,
+			&i.ActionRecipeID,
+			&i.Hp,
+			&i.MaxHp,
+			&i.ActionCreatureTarget,
+			&i.ActionTicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const regenerateCharacterHealth = `-- name: RegenerateCharacterHealth :exec
+UPDATE characters
+SET hp = LEAST(hp + 1, max_hp)
+WHERE hp < max_hp AND action_creature_target IS NULL
+`
+
+func (q *Queries) RegenerateCharacterHealth(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, regenerateCharacterHealth)
+	return err
+}
+
+const respawnCharacter = `-- name: RespawnCharacter :one
+UPDATE characters
+SET hp = max_hp,
+	position_x = $1,
+	position_y = $2,
+	action_id = $3,
+	action_target = NULL,
+	action_creature_target = NULL,
+	action_recipe_id = NULL,
+	action_amount_limit = NULL,
+	action_amount_progress = 0,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE id = $4
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
+`
+
+type RespawnCharacterParams struct {
+	PositionX int32
+	PositionY int32
+	ActionID  int32
+	ID        pgtype.UUID
+}
+
+func (q *Queries) RespawnCharacter(ctx context.Context, arg RespawnCharacterParams) (Character, error) {
+	row := q.db.QueryRow(ctx, respawnCharacter,
+		arg.PositionX,
+		arg.PositionY,
+		arg.ActionID,
+		arg.ID,
+	)
+	var i Character
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.PositionX,
+		&i.PositionY,
+		&i.ActionID,
+		&i.ActionTarget,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ActionAmountLimit,
+		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
+	)
+	return i, err
+}
+
 const setCharacterToIdleAndResetGathering = `-- name: SetCharacterToIdleAndResetGathering :one
 UPDATE characters
 SET action_id = $1,
 	action_target = NULL,
 	action_amount_limit = NULL,
 	action_amount_progress = 0,
+	action_recipe_id = NULL,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_creature_target = NULL,
+	action_ticks = 0,
 	updated_at = NOW()
 WHERE id = $2
-RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
 `
 
 type SetCharacterToIdleAndResetGatheringParams struct {
@@ -225,6 +596,171 @@ func (q *Queries) SetCharacterToIdleAndResetGathering(ctx context.Context, arg S
 		&i.UpdatedAt,
 		&i.ActionAmountLimit,
 		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
+	)
+	return i, err
+}
+
+const startCharacterCombat = `-- name: StartCharacterCombat :one
+UPDATE characters
+SET action_id = $1,
+	action_creature_target = $2,
+	action_target = NULL,
+	action_recipe_id = NULL,
+	action_amount_limit = NULL,
+	action_amount_progress = 0,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE id = $3
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
+`
+
+type StartCharacterCombatParams struct {
+	ActionID             int32
+	ActionCreatureTarget pgtype.Int4
+	ID                   pgtype.UUID
+}
+
+func (q *Queries) StartCharacterCombat(ctx context.Context, arg StartCharacterCombatParams) (Character, error) {
+	row := q.db.QueryRow(ctx, startCharacterCombat, arg.ActionID, arg.ActionCreatureTarget, arg.ID)
+	var i Character
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.PositionX,
+		&i.PositionY,
+		&i.ActionID,
+		&i.ActionTarget,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ActionAmountLimit,
+		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
+	)
+	return i, err
+}
+
+const startCharacterCrafting = `-- name: StartCharacterCrafting :one
+UPDATE characters
+SET action_id = $1,
+	action_target = NULL,
+	action_recipe_id = $2,
+	action_amount_limit = $3,
+	action_amount_progress = 0,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_creature_target = NULL,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE id = $4
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
+`
+
+type StartCharacterCraftingParams struct {
+	ActionID          int32
+	ActionRecipeID    pgtype.Int4
+	ActionAmountLimit pgtype.Int4
+	ID                pgtype.UUID
+}
+
+func (q *Queries) StartCharacterCrafting(ctx context.Context, arg StartCharacterCraftingParams) (Character, error) {
+	row := q.db.QueryRow(ctx, startCharacterCrafting,
+		arg.ActionID,
+		arg.ActionRecipeID,
+		arg.ActionAmountLimit,
+		arg.ID,
+	)
+	var i Character
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.PositionX,
+		&i.PositionY,
+		&i.ActionID,
+		&i.ActionTarget,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ActionAmountLimit,
+		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
+	)
+	return i, err
+}
+
+const startCharacterTravel = `-- name: StartCharacterTravel :one
+UPDATE characters
+SET action_id = $1,
+	action_target = NULL,
+	action_recipe_id = NULL,
+	destination_x = $2,
+	destination_y = $3,
+	action_amount_limit = $4,
+	action_amount_progress = 0,
+	action_creature_target = NULL,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE id = $5
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
+`
+
+type StartCharacterTravelParams struct {
+	ActionID          int32
+	DestinationX      pgtype.Int4
+	DestinationY      pgtype.Int4
+	ActionAmountLimit pgtype.Int4
+	ID                pgtype.UUID
+}
+
+func (q *Queries) StartCharacterTravel(ctx context.Context, arg StartCharacterTravelParams) (Character, error) {
+	row := q.db.QueryRow(ctx, startCharacterTravel,
+		arg.ActionID,
+		arg.DestinationX,
+		arg.DestinationY,
+		arg.ActionAmountLimit,
+		arg.ID,
+	)
+	var i Character
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.PositionX,
+		&i.PositionY,
+		&i.ActionID,
+		&i.ActionTarget,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ActionAmountLimit,
+		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
 	)
 	return i, err
 }
@@ -234,7 +770,7 @@ UPDATE characters
 SET action_id = $1, 
 	updated_at = NOW()
 WHERE id = $2
-RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
 `
 
 type UpdateCharacterByIdParams struct {
@@ -257,6 +793,13 @@ func (q *Queries) UpdateCharacterById(ctx context.Context, arg UpdateCharacterBy
 		&i.UpdatedAt,
 		&i.ActionAmountLimit,
 		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
 	)
 	return i, err
 }
@@ -267,9 +810,14 @@ SET action_id = $1,
 	action_target = $2,
 	action_amount_limit = $3,
 	action_amount_progress = 0,
+	action_recipe_id = NULL,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_creature_target = NULL,
+	action_ticks = 0,
 	updated_at = NOW()
 WHERE id = $4
-RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
 `
 
 type UpdateCharacterByIdWithTargetAndAmountParams struct {
@@ -299,6 +847,13 @@ func (q *Queries) UpdateCharacterByIdWithTargetAndAmount(ctx context.Context, ar
 		&i.UpdatedAt,
 		&i.ActionAmountLimit,
 		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
 	)
 	return i, err
 }
@@ -308,7 +863,7 @@ UPDATE characters
 SET action_amount_progress = $1,
 	updated_at = NOW()
 WHERE id = $2
-RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress
+RETURNING id, user_id, name, position_x, position_y, action_id, action_target, created_at, updated_at, action_amount_limit, action_amount_progress, destination_x, destination_y, action_recipe_id, hp, max_hp, action_creature_target, action_ticks
 `
 
 type UpdateCharacterProgressParams struct {
@@ -331,6 +886,13 @@ func (q *Queries) UpdateCharacterProgress(ctx context.Context, arg UpdateCharact
 		&i.UpdatedAt,
 		&i.ActionAmountLimit,
 		&i.ActionAmountProgress,
+		&i.DestinationX,
+		&i.DestinationY,
+		&i.ActionRecipeID,
+		&i.Hp,
+		&i.MaxHp,
+		&i.ActionCreatureTarget,
+		&i.ActionTicks,
 	)
 	return i, err
 }
@@ -9,17 +9,17 @@ import (
 	"context"
 )
 
-const createGridItem = `-- name: CreateGridItem :exec
-INSERT INTO grid (position_x, position_y) VALUES ($1, $2)
+const deleteGridItem = `-- name: DeleteGridItem :exec
+DELETE FROM grid WHERE position_x = $1 AND position_y = $2
 `
 
-type CreateGridItemParams struct {
+type DeleteGridItemParams struct {
 	PositionX int32
 	PositionY int32
 }
 
-func (q *Queries) CreateGridItem(ctx context.Context, arg CreateGridItemParams) error {
-	_, err := q.db.Exec(ctx, createGridItem, arg.PositionX, arg.PositionY)
+func (q *Queries) DeleteGridItem(ctx context.Context, arg DeleteGridItemParams) error {
+	_, err := q.db.Exec(ctx, deleteGridItem, arg.PositionX, arg.PositionY)
 	return err
 }
 
@@ -46,3 +46,62 @@ func (q *Queries) GetGrid(ctx context.Context) ([]Grid, error) {
 	}
 	return items, nil
 }
+
+const getGridItem = `-- name: GetGridItem :one
+SELECT position_x, position_y FROM grid
+WHERE position_x = $1 AND position_y = $2
+`
+
+type GetGridItemParams struct {
+	PositionX int32
+	PositionY int32
+}
+
+func (q *Queries) GetGridItem(ctx context.Context, arg GetGridItemParams) (Grid, error) {
+	row := q.db.QueryRow(ctx, getGridItem, arg.PositionX, arg.PositionY)
+	var i Grid
+	err := row.Scan(&i.PositionX, &i.PositionY)
+	return i, err
+}
+
+const getOccupiedGridItems = `-- name: GetOccupiedGridItems :many
+SELECT position_x, position_y FROM grid AS g
+WHERE EXISTS (SELECT 1 FROM characters AS c WHERE c.position_x = g.position_x AND c.position_y = g.position_y)
+	OR EXISTS (SELECT 1 FROM inventories AS i WHERE i.user_id IS NOT NULL AND i.position_x = g.position_x AND i.position_y = g.position_y)
+ORDER BY g.position_x, g.position_y
+`
+
+func (q *Queries) GetOccupiedGridItems(ctx context.Context) ([]Grid, error) {
+	rows, err := q.db.Query(ctx, getOccupiedGridItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Grid
+	for rows.Next() {
+		var i Grid
+		if err := rows.Scan(&i.PositionX, &i.PositionY); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertGridItem = `-- name: UpsertGridItem :exec
+INSERT INTO grid (position_x, position_y) VALUES ($1, $2)
+ON CONFLICT (position_x, position_y) DO NOTHING
+`
+
+type UpsertGridItemParams struct {
+	PositionX int32
+	PositionY int32
+}
+
+func (q *Queries) UpsertGridItem(ctx context.Context, arg UpsertGridItemParams) error {
+	_, err := q.db.Exec(ctx, upsertGridItem, arg.PositionX, arg.PositionY)
+	return err
+}
@@ -11,6 +11,37 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const createGroundInventory = `-- name: CreateGroundInventory :one
+INSERT INTO inventories(id, character_id, position_x, position_y, capacity, created_at, updated_at)
+VALUES (gen_random_uuid(), NULL, $1, $2, $3, NOW(), NOW())
+ON CONFLICT (position_x, position_y) WHERE character_id IS NULL AND user_id IS NULL
+DO UPDATE SET updated_at = NOW()
+RETURNING id, character_id, position_x, position_y, weight, capacity, created_at, updated_at, user_id
+`
+
+type CreateGroundInventoryParams struct {
+	PositionX int32
+	PositionY int32
+	Capacity  int32
+}
+
+func (q *Queries) CreateGroundInventory(ctx context.Context, arg CreateGroundInventoryParams) (Inventory, error) {
+	row := q.db.QueryRow(ctx, createGroundInventory, arg.PositionX, arg.PositionY, arg.Capacity)
+	var i Inventory
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.PositionX,
+		&i.PositionY,
+		&i.Weight,
+		&i.Capacity,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+	)
+	return i, err
+}
+
 const createInventory = `-- name: CreateInventory :one
 INSERT INTO inventories(id, character_id, position_x, position_y, capacity, created_at, updated_at)
 VALUES (
@@ -22,7 +53,7 @@ VALUES (
 	NOW(),
 	NOW()
 )
-RETURNING id, character_id, position_x, position_y, weight, capacity, created_at, updated_at
+RETURNING id, character_id, position_x, position_y, weight, capacity, created_at, updated_at, user_id
 `
 
 type CreateInventoryParams struct {
@@ -49,12 +80,70 @@ func (q *Queries) CreateInventory(ctx context.Context, arg CreateInventoryParams
 		&i.Capacity,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const createStash = `-- name: CreateStash :one
+INSERT INTO inventories(id, user_id, position_x, position_y, capacity, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, 0, 0, $2, NOW(), NOW())
+ON CONFLICT (user_id) WHERE user_id IS NOT NULL
+DO UPDATE SET updated_at = NOW()
+RETURNING id, character_id, position_x, position_y, weight, capacity, created_at, updated_at, user_id
+`
+
+type CreateStashParams struct {
+	UserID   pgtype.UUID
+	Capacity int32
+}
+
+func (q *Queries) CreateStash(ctx context.Context, arg CreateStashParams) (Inventory, error) {
+	row := q.db.QueryRow(ctx, createStash, arg.UserID, arg.Capacity)
+	var i Inventory
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.PositionX,
+		&i.PositionY,
+		&i.Weight,
+		&i.Capacity,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const getGroundInventoryByCoordinates = `-- name: GetGroundInventoryByCoordinates :one
+SELECT id, character_id, position_x, position_y, weight, capacity, created_at, updated_at, user_id FROM inventories
+WHERE character_id IS NULL AND user_id IS NULL AND position_x = $1 AND position_y = $2
+`
+
+type GetGroundInventoryByCoordinatesParams struct {
+	PositionX int32
+	PositionY int32
+}
+
+func (q *Queries) GetGroundInventoryByCoordinates(ctx context.Context, arg GetGroundInventoryByCoordinatesParams) (Inventory, error) {
+	row := q.db.QueryRow(ctx, getGroundInventoryByCoordinates, arg.PositionX, arg.PositionY)
+	var i Inventory
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.PositionX,
+		&i.PositionY,
+		&i.Weight,
+		&i.Capacity,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
 	)
 	return i, err
 }
 
 const getInventory = `-- name: GetInventory :one
-SELECT id, character_id, position_x, position_y, weight, capacity, created_at, updated_at FROM inventories
+SELECT id, character_id, position_x, position_y, weight, capacity, created_at, updated_at, user_id FROM inventories
 WHERE id = $1
 `
 
@@ -70,12 +159,13 @@ func (q *Queries) GetInventory(ctx context.Context, id pgtype.UUID) (Inventory,
 		&i.Capacity,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UserID,
 	)
 	return i, err
 }
 
 const getInventoryByCharacterId = `-- name: GetInventoryByCharacterId :one
-SELECT id, character_id, position_x, position_y, weight, capacity, created_at, updated_at FROM inventories
+SELECT id, character_id, position_x, position_y, weight, capacity, created_at, updated_at, user_id FROM inventories
 WHERE character_id = $1
 `
 
@@ -91,10 +181,88 @@ func (q *Queries) GetInventoryByCharacterId(ctx context.Context, characterID pgt
 		&i.Capacity,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UserID,
 	)
 	return i, err
 }
 
+const getStashByUserId = `-- name: GetStashByUserId :one
+SELECT id, character_id, position_x, position_y, weight, capacity, created_at, updated_at, user_id FROM inventories
+WHERE user_id = $1
+`
+
+func (q *Queries) GetStashByUserId(ctx context.Context, userID pgtype.UUID) (Inventory, error) {
+	row := q.db.QueryRow(ctx, getStashByUserId, userID)
+	var i Inventory
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.PositionX,
+		&i.PositionY,
+		&i.Weight,
+		&i.Capacity,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const lockInventory = `-- name: LockInventory :one
+SELECT id, character_id, position_x, position_y, weight, capacity, created_at, updated_at, user_id FROM inventories
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockInventory(ctx context.Context, id pgtype.UUID) (Inventory, error) {
+	row := q.db.QueryRow(ctx, lockInventory, id)
+	var i Inventory
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.PositionX,
+		&i.PositionY,
+		&i.Weight,
+		&i.Capacity,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const recomputeInventoryWeights = `-- name: RecomputeInventoryWeights :exec
+UPDATE inventories
+SET weight = COALESCE((
+	SELECT SUM(ii.quantity * it.weight)
+	FROM inventory_items ii
+	JOIN items it ON it.id = ii.item_id
+	WHERE ii.inventory_id = inventories.id
+), 0)::INTEGER
+`
+
+func (q *Queries) RecomputeInventoryWeights(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, recomputeInventoryWeights)
+	return err
+}
+
+const updateInventoryPositionByCharacterId = `-- name: UpdateInventoryPositionByCharacterId :exec
+UPDATE inventories
+SET position_x = $2, position_y = $3, updated_at = NOW()
+WHERE character_id = $1
+`
+
+type UpdateInventoryPositionByCharacterIdParams struct {
+	CharacterID pgtype.UUID
+	PositionX   int32
+	PositionY   int32
+}
+
+func (q *Queries) UpdateInventoryPositionByCharacterId(ctx context.Context, arg UpdateInventoryPositionByCharacterIdParams) error {
+	_, err := q.db.Exec(ctx, updateInventoryPositionByCharacterId, arg.CharacterID, arg.PositionX, arg.PositionY)
+	return err
+}
+
 const updateInventoryWeight = `-- name: UpdateInventoryWeight :exec
 UPDATE inventories
 SET weight = weight + $2, updated_at = NOW()
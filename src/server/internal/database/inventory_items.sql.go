@@ -14,11 +14,12 @@ import (
 const addItemsToInventory = `-- name: AddItemsToInventory :one
 INSERT INTO inventory_items(id, inventory_id, item_id, quantity, updated_at, created_at)
 VALUES (gen_random_uuid(), $1, $2, $3, NOW(), NOW())
-ON CONFLICT (inventory_id, item_id) 
+ON CONFLICT (inventory_id, item_id) WHERE durability IS NULL
 DO UPDATE SET
 	quantity = inventory_items.quantity + EXCLUDED.quantity,
+	ticks_untouched = 0,
 	updated_at = NOW()
-RETURNING id, item_id, inventory_id, quantity, created_at, updated_at
+RETURNING id, item_id, inventory_id, quantity, created_at, updated_at, durability, ticks_untouched
 `
 
 type AddItemsToInventoryParams struct {
@@ -37,16 +38,35 @@ func (q *Queries) AddItemsToInventory(ctx context.Context, arg AddItemsToInvento
 		&i.Quantity,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Durability,
+		&i.TicksUntouched,
 	)
 	return i, err
 }
 
+const batchAddItemInstancesToInventory = `-- name: BatchAddItemInstancesToInventory :exec
+INSERT INTO inventory_items(id, inventory_id, item_id, quantity, durability, updated_at, created_at)
+SELECT gen_random_uuid(), unnest($1::UUID[]), unnest($2::INTEGER[]), 1, unnest($3::INTEGER[]), NOW(), NOW()
+`
+
+type BatchAddItemInstancesToInventoryParams struct {
+	InventoryIds []pgtype.UUID
+	ItemIds      []int32
+	Durabilities []int32
+}
+
+func (q *Queries) BatchAddItemInstancesToInventory(ctx context.Context, arg BatchAddItemInstancesToInventoryParams) error {
+	_, err := q.db.Exec(ctx, batchAddItemInstancesToInventory, arg.InventoryIds, arg.ItemIds, arg.Durabilities)
+	return err
+}
+
 const batchAddItemsToInventory = `-- name: BatchAddItemsToInventory :exec
 INSERT INTO inventory_items(id, inventory_id, item_id, quantity, updated_at, created_at)
 SELECT gen_random_uuid(), unnest($1::UUID[]), unnest($2::INTEGER[]), unnest($3::INTEGER[]), NOW(), NOW()
-ON CONFLICT (inventory_id, item_id) 
+ON CONFLICT (inventory_id, item_id) WHERE durability IS NULL
 DO UPDATE SET
 	quantity = inventory_items.quantity + EXCLUDED.quantity,
+	ticks_untouched = 0,
 	updated_at = NOW()
 `
 
@@ -61,6 +81,42 @@ func (q *Queries) BatchAddItemsToInventory(ctx context.Context, arg BatchAddItem
 	return err
 }
 
+const batchWearInventoryItems = `-- name: BatchWearInventoryItems :many
+UPDATE inventory_items
+SET durability = durability - 1, updated_at = NOW()
+WHERE id = ANY($1::UUID[]) AND durability IS NOT NULL
+RETURNING id, item_id, inventory_id, quantity, created_at, updated_at, durability, ticks_untouched
+`
+
+func (q *Queries) BatchWearInventoryItems(ctx context.Context, ids []pgtype.UUID) ([]InventoryItem, error) {
+	rows, err := q.db.Query(ctx, batchWearInventoryItems, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InventoryItem
+	for rows.Next() {
+		var i InventoryItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.ItemID,
+			&i.InventoryID,
+			&i.Quantity,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Durability,
+			&i.TicksUntouched,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deleteEmptyInventoryItems = `-- name: DeleteEmptyInventoryItems :exec
 DELETE FROM inventory_items
 WHERE inventory_id = $1 AND quantity <= 0
@@ -71,8 +127,55 @@ func (q *Queries) DeleteEmptyInventoryItems(ctx context.Context, inventoryID pgt
 	return err
 }
 
+const deleteExpiredGroundItems = `-- name: DeleteExpiredGroundItems :many
+DELETE FROM inventory_items AS ii
+USING inventories AS i
+WHERE ii.inventory_id = i.id AND i.character_id IS NULL AND i.user_id IS NULL
+	AND ii.ticks_untouched >= $1::INTEGER
+RETURNING ii.id, ii.item_id, ii.inventory_id, ii.quantity, ii.created_at, ii.updated_at, ii.durability, ii.ticks_untouched
+`
+
+func (q *Queries) DeleteExpiredGroundItems(ctx context.Context, expiryTicks int32) ([]InventoryItem, error) {
+	rows, err := q.db.Query(ctx, deleteExpiredGroundItems, expiryTicks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InventoryItem
+	for rows.Next() {
+		var i InventoryItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.ItemID,
+			&i.InventoryID,
+			&i.Quantity,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Durability,
+			&i.TicksUntouched,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteInventoryItemsById = `-- name: DeleteInventoryItemsById :exec
+DELETE FROM inventory_items
+WHERE id = ANY($1::UUID[])
+`
+
+func (q *Queries) DeleteInventoryItemsById(ctx context.Context, ids []pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteInventoryItemsById, ids)
+	return err
+}
+
 const getInventoryItemQuantity = `-- name: GetInventoryItemQuantity :one
-SELECT quantity FROM inventory_items
+SELECT COALESCE(SUM(quantity), 0)::INTEGER AS quantity FROM inventory_items
 WHERE inventory_id = $1 AND item_id = $2
 `
 
@@ -89,7 +192,7 @@ func (q *Queries) GetInventoryItemQuantity(ctx context.Context, arg GetInventory
 }
 
 const getInventoryItemsByInventoryId = `-- name: GetInventoryItemsByInventoryId :many
-SELECT id, item_id, inventory_id, quantity, created_at, updated_at FROM inventory_items
+SELECT id, item_id, inventory_id, quantity, created_at, updated_at, durability, ticks_untouched FROM inventory_items
 WHERE inventory_id = $1
 `
 
@@ -109,6 +212,48 @@ func (q *Queries) GetInventoryItemsByInventoryId(ctx context.Context, inventoryI
 			&i.Quantity,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Durability,
+			&i.TicksUntouched,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockInventoryItems = `-- name: LockInventoryItems :many
+SELECT id, item_id, inventory_id, quantity, created_at, updated_at, durability, ticks_untouched FROM inventory_items
+WHERE inventory_id = $1 AND item_id = $2
+FOR UPDATE
+`
+
+type LockInventoryItemsParams struct {
+	InventoryID pgtype.UUID
+	ItemID      int32
+}
+
+func (q *Queries) LockInventoryItems(ctx context.Context, arg LockInventoryItemsParams) ([]InventoryItem, error) {
+	rows, err := q.db.Query(ctx, lockInventoryItems, arg.InventoryID, arg.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InventoryItem
+	for rows.Next() {
+		var i InventoryItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.ItemID,
+			&i.InventoryID,
+			&i.Quantity,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Durability,
+			&i.TicksUntouched,
 		); err != nil {
 			return nil, err
 		}
@@ -120,10 +265,26 @@ func (q *Queries) GetInventoryItemsByInventoryId(ctx context.Context, inventoryI
 	return items, nil
 }
 
-const removeItemsFromInventory = `-- name: RemoveItemsFromInventory :exec
+const moveInventoryItemsById = `-- name: MoveInventoryItemsById :exec
+UPDATE inventory_items
+SET inventory_id = $1, ticks_untouched = 0, updated_at = NOW()
+WHERE id = ANY($2::UUID[])
+`
+
+type MoveInventoryItemsByIdParams struct {
+	InventoryID pgtype.UUID
+	Ids         []pgtype.UUID
+}
+
+func (q *Queries) MoveInventoryItemsById(ctx context.Context, arg MoveInventoryItemsByIdParams) error {
+	_, err := q.db.Exec(ctx, moveInventoryItemsById, arg.InventoryID, arg.Ids)
+	return err
+}
+
+const removeItemsFromInventory = `-- name: RemoveItemsFromInventory :execrows
 UPDATE inventory_items 
-SET quantity = quantity - $3, updated_at = NOW()
-WHERE inventory_id = $1 AND item_id = $2 AND quantity >= $3
+SET quantity = quantity - $3, ticks_untouched = 0, updated_at = NOW()
+WHERE inventory_id = $1 AND item_id = $2 AND quantity >= $3 AND durability IS NULL
 `
 
 type RemoveItemsFromInventoryParams struct {
@@ -132,7 +293,22 @@ type RemoveItemsFromInventoryParams struct {
 	Quantity    int32
 }
 
-func (q *Queries) RemoveItemsFromInventory(ctx context.Context, arg RemoveItemsFromInventoryParams) error {
-	_, err := q.db.Exec(ctx, removeItemsFromInventory, arg.InventoryID, arg.ItemID, arg.Quantity)
+func (q *Queries) RemoveItemsFromInventory(ctx context.Context, arg RemoveItemsFromInventoryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeItemsFromInventory, arg.InventoryID, arg.ItemID, arg.Quantity)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const tickGroundItems = `-- name: TickGroundItems :exec
+UPDATE inventory_items AS ii
+SET ticks_untouched = ii.ticks_untouched + 1
+FROM inventories AS i
+WHERE ii.inventory_id = i.id AND i.character_id IS NULL AND i.user_id IS NULL
+`
+
+func (q *Queries) TickGroundItems(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, tickGroundItems)
 	return err
 }
@@ -7,24 +7,44 @@ package database
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createItem = `-- name: CreateItem :exec
-INSERT INTO items (name, weight) VALUES ($1, $2)
+const deleteItemsExcept = `-- name: DeleteItemsExcept :many
+DELETE FROM items WHERE id <> ALL($1::INTEGER[])
+RETURNING id, name, weight, tool_type_id, tool_tier, max_durability
 `
 
-type CreateItemParams struct {
-	Name   string
-	Weight int32
-}
-
-func (q *Queries) CreateItem(ctx context.Context, arg CreateItemParams) error {
-	_, err := q.db.Exec(ctx, createItem, arg.Name, arg.Weight)
-	return err
+func (q *Queries) DeleteItemsExcept(ctx context.Context, ids []int32) ([]Item, error) {
+	rows, err := q.db.Query(ctx, deleteItemsExcept, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Item
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Weight,
+			&i.ToolTypeID,
+			&i.ToolTier,
+			&i.MaxDurability,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getItemById = `-- name: GetItemById :one
-SELECT id, name, weight, tool_type_id FROM items
+SELECT id, name, weight, tool_type_id, tool_tier, max_durability FROM items
 WHERE id = $1
 `
 
@@ -36,12 +56,14 @@ func (q *Queries) GetItemById(ctx context.Context, id int32) (Item, error) {
 		&i.Name,
 		&i.Weight,
 		&i.ToolTypeID,
+		&i.ToolTier,
+		&i.MaxDurability,
 	)
 	return i, err
 }
 
 const getItemByName = `-- name: GetItemByName :one
-SELECT id, name, weight, tool_type_id FROM items
+SELECT id, name, weight, tool_type_id, tool_tier, max_durability FROM items
 WHERE name = $1
 `
 
@@ -53,12 +75,14 @@ func (q *Queries) GetItemByName(ctx context.Context, name string) (Item, error)
 		&i.Name,
 		&i.Weight,
 		&i.ToolTypeID,
+		&i.ToolTier,
+		&i.MaxDurability,
 	)
 	return i, err
 }
 
 const getItemByResourceId = `-- name: GetItemByResourceId :one
-SELECT id, name, weight, tool_type_id FROM items
+SELECT id, name, weight, tool_type_id, tool_tier, max_durability FROM items
 WHERE id = (SELECT item_id FROM resources WHERE resources.id = $1)
 `
 
@@ -70,6 +94,121 @@ func (q *Queries) GetItemByResourceId(ctx context.Context, id int32) (Item, erro
 		&i.Name,
 		&i.Weight,
 		&i.ToolTypeID,
+		&i.ToolTier,
+		&i.MaxDurability,
+	)
+	return i, err
+}
+
+const getItemHoldersExcept = `-- name: GetItemHoldersExcept :many
+SELECT it.name AS item_name, c.name AS character_name, i.user_id, i.position_x, i.position_y
+FROM inventory_items ii
+JOIN items it ON it.id = ii.item_id
+JOIN inventories i ON i.id = ii.inventory_id
+LEFT JOIN characters c ON c.id = i.character_id
+WHERE ii.item_id <> ALL($1::INTEGER[]) AND ii.quantity > 0
+ORDER BY it.name, c.name, i.position_x, i.position_y
+`
+
+type GetItemHoldersExceptRow struct {
+	ItemName      string
+	CharacterName pgtype.Text
+	UserID        pgtype.UUID
+	PositionX     int32
+	PositionY     int32
+}
+
+func (q *Queries) GetItemHoldersExcept(ctx context.Context, ids []int32) ([]GetItemHoldersExceptRow, error) {
+	rows, err := q.db.Query(ctx, getItemHoldersExcept, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetItemHoldersExceptRow
+	for rows.Next() {
+		var i GetItemHoldersExceptRow
+		if err := rows.Scan(
+			&i.ItemName,
+			&i.CharacterName,
+			&i.UserID,
+			&i.PositionX,
+			&i.PositionY,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listItems = `-- name: ListItems :many
+SELECT id, name, weight, tool_type_id, tool_tier, max_durability FROM items ORDER BY id
+`
+
+func (q *Queries) ListItems(ctx context.Context) ([]Item, error) {
+	rows, err := q.db.Query(ctx, listItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Item
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Weight,
+			&i.ToolTypeID,
+			&i.ToolTier,
+			&i.MaxDurability,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertItem = `-- name: UpsertItem :one
+INSERT INTO items (name, weight, tool_type_id, tool_tier, max_durability) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (name) DO UPDATE SET
+	weight = EXCLUDED.weight,
+	tool_type_id = EXCLUDED.tool_type_id,
+	tool_tier = EXCLUDED.tool_tier,
+	max_durability = EXCLUDED.max_durability
+RETURNING id, name, weight, tool_type_id, tool_tier, max_durability
+`
+
+type UpsertItemParams struct {
+	Name          string
+	Weight        int32
+	ToolTypeID    pgtype.Int4
+	ToolTier      int32
+	MaxDurability pgtype.Int4
+}
+
+func (q *Queries) UpsertItem(ctx context.Context, arg UpsertItemParams) (Item, error) {
+	row := q.db.QueryRow(ctx, upsertItem,
+		arg.Name,
+		arg.Weight,
+		arg.ToolTypeID,
+		arg.ToolTier,
+		arg.MaxDurability,
+	)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Weight,
+		&i.ToolTypeID,
+		&i.ToolTier,
+		&i.MaxDurability,
 	)
 	return i, err
 }
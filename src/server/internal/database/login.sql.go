@@ -10,7 +10,7 @@ import (
 )
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, hashed_password, created_at, updated_at, surname FROM users
+SELECT id, email, hashed_password, created_at, updated_at, surname, last_seen_at FROM users
 WHERE email = $1
 `
 
@@ -24,6 +24,7 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Surname,
+		&i.LastSeenAt,
 	)
 	return i, err
 }
@@ -26,6 +26,60 @@ type Character struct {
 	UpdatedAt            pgtype.Timestamp
 	ActionAmountLimit    pgtype.Int4
 	ActionAmountProgress pgtype.Int4
+	DestinationX         pgtype.Int4
+	DestinationY         pgtype.Int4
+	ActionRecipeID       pgtype.Int4
+	Hp                   int32
+	MaxHp                int32
+	ActionCreatureTarget pgtype.Int4
+	ActionTicks          int32
+}
+
+type CharacterEvent struct {
+	ID          int32
+	CharacterID pgtype.UUID
+	Message     string
+	CreatedAt   pgtype.Timestamptz
+}
+
+type CharacterGain struct {
+	CharacterID pgtype.UUID
+	ItemID      int32
+	Quantity    int32
+}
+
+type CharacterSkill struct {
+	CharacterID pgtype.UUID
+	ActionID    int32
+	Experience  int32
+	CreatedAt   pgtype.Timestamp
+	UpdatedAt   pgtype.Timestamp
+}
+
+type Creature struct {
+	ID           int32
+	Name         string
+	Tier         int32
+	MaxHp        int32
+	Damage       int32
+	RespawnTicks int32
+}
+
+type CreatureDrop struct {
+	ID         int32
+	CreatureID int32
+	ItemID     int32
+	DropChance int32
+	Quantity   int32
+}
+
+type CreatureSpawn struct {
+	ID               int32
+	CreatureID       int32
+	PositionX        int32
+	PositionY        int32
+	Hp               int32
+	RespawnTicksLeft int32
 }
 
 type Grid struct {
@@ -42,22 +96,41 @@ type Inventory struct {
 	Capacity    int32
 	CreatedAt   pgtype.Timestamp
 	UpdatedAt   pgtype.Timestamp
+	UserID      pgtype.UUID
 }
 
 type InventoryItem struct {
-	ID          pgtype.UUID
-	ItemID      int32
-	InventoryID pgtype.UUID
-	Quantity    int32
-	CreatedAt   pgtype.Timestamp
-	UpdatedAt   pgtype.Timestamp
+	ID             pgtype.UUID
+	ItemID         int32
+	InventoryID    pgtype.UUID
+	Quantity       int32
+	CreatedAt      pgtype.Timestamp
+	UpdatedAt      pgtype.Timestamp
+	Durability     pgtype.Int4
+	TicksUntouched int32
 }
 
 type Item struct {
-	ID         int32
-	Name       string
-	Weight     int32
-	ToolTypeID pgtype.Int4
+	ID            int32
+	Name          string
+	Weight        int32
+	ToolTypeID    pgtype.Int4
+	ToolTier      int32
+	MaxDurability pgtype.Int4
+}
+
+type Recipe struct {
+	ID       int32
+	Name     string
+	ItemID   int32
+	Quantity int32
+}
+
+type RecipeIngredient struct {
+	ID       int32
+	RecipeID int32
+	ItemID   int32
+	Quantity int32
 }
 
 type RefreshToken struct {
@@ -77,18 +150,32 @@ type Resource struct {
 }
 
 type ResourceNode struct {
-	ID          int32
-	Name        string
-	ActionID    int32
-	Tier        int32
-	MinToolTier int32
+	ID            int32
+	Name          string
+	ActionID      int32
+	Tier          int32
+	MinToolTier   int32
+	MinLevel      int32
+	Amount        pgtype.Int4
+	RespawnTicks  int32
+	IntervalTicks int32
+	MinQuantity   int32
+	MaxQuantity   int32
+	Biome         string
 }
 
 type ResourceNodeSpawn struct {
-	ID        int32
-	NodeID    int32
-	PositionX int32
-	PositionY int32
+	ID               int32
+	NodeID           int32
+	PositionX        int32
+	PositionY        int32
+	Remaining        pgtype.Int4
+	RespawnTicksLeft int32
+}
+
+type TickShard struct {
+	Shard int32
+	Epoch int64
 }
 
 type ToolType struct {
@@ -97,6 +184,24 @@ type ToolType struct {
 	Tier int32
 }
 
+type Trade struct {
+	ID                 pgtype.UUID
+	InitiatorID        pgtype.UUID
+	RecipientID        pgtype.UUID
+	InitiatorConfirmed bool
+	RecipientConfirmed bool
+	Status             string
+	CreatedAt          pgtype.Timestamp
+	UpdatedAt          pgtype.Timestamp
+}
+
+type TradeItem struct {
+	TradeID     pgtype.UUID
+	CharacterID pgtype.UUID
+	ItemID      int32
+	Quantity    int32
+}
+
 type User struct {
 	ID             pgtype.UUID
 	Email          string
@@ -104,6 +209,7 @@ type User struct {
 	CreatedAt      pgtype.Timestamp
 	UpdatedAt      pgtype.Timestamp
 	Surname        pgtype.Text
+	LastSeenAt     pgtype.Timestamptz
 }
 
 type Version struct {
@@ -112,3 +218,16 @@ type Version struct {
 	CreatedAt pgtype.Timestamp
 	UpdatedAt pgtype.Timestamp
 }
+
+type WorldGeneration struct {
+	ID        int32
+	Seed      int64
+	Config    []byte
+	UpdatedAt pgtype.Timestamp
+}
+
+type WorldState struct {
+	ID         int32
+	LastTick   int64
+	LastTickAt pgtype.Timestamptz
+}
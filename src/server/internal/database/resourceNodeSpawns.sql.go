@@ -7,25 +7,44 @@ package database
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createResourceNodeSpawn = `-- name: CreateResourceNodeSpawn :exec
-INSERT INTO resource_node_spawns (node_id, position_x, position_y) VALUES ($1, $2, $3)
+const deleteResourceNodeSpawnsExcept = `-- name: DeleteResourceNodeSpawnsExcept :many
+DELETE FROM resource_node_spawns WHERE id <> ALL($1::INTEGER[])
+RETURNING id, node_id, position_x, position_y, remaining, respawn_ticks_left
 `
 
-type CreateResourceNodeSpawnParams struct {
-	NodeID    int32
-	PositionX int32
-	PositionY int32
-}
-
-func (q *Queries) CreateResourceNodeSpawn(ctx context.Context, arg CreateResourceNodeSpawnParams) error {
-	_, err := q.db.Exec(ctx, createResourceNodeSpawn, arg.NodeID, arg.PositionX, arg.PositionY)
-	return err
+func (q *Queries) DeleteResourceNodeSpawnsExcept(ctx context.Context, ids []int32) ([]ResourceNodeSpawn, error) {
+	rows, err := q.db.Query(ctx, deleteResourceNodeSpawnsExcept, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ResourceNodeSpawn
+	for rows.Next() {
+		var i ResourceNodeSpawn
+		if err := rows.Scan(
+			&i.ID,
+			&i.NodeID,
+			&i.PositionX,
+			&i.PositionY,
+			&i.Remaining,
+			&i.RespawnTicksLeft,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getResourceNodeSpawnByCoordsAndNodeId = `-- name: GetResourceNodeSpawnByCoordsAndNodeId :one
-SELECT id, node_id, position_x, position_y FROM resource_node_spawns WHERE position_x = $1 AND position_y = $2 AND node_id = $3
+SELECT id, node_id, position_x, position_y, remaining, respawn_ticks_left FROM resource_node_spawns WHERE position_x = $1 AND position_y = $2 AND node_id = $3
 `
 
 type GetResourceNodeSpawnByCoordsAndNodeIdParams struct {
@@ -42,12 +61,14 @@ func (q *Queries) GetResourceNodeSpawnByCoordsAndNodeId(ctx context.Context, arg
 		&i.NodeID,
 		&i.PositionX,
 		&i.PositionY,
+		&i.Remaining,
+		&i.RespawnTicksLeft,
 	)
 	return i, err
 }
 
 const getResourceNodeSpawnById = `-- name: GetResourceNodeSpawnById :one
-SELECT id, node_id, position_x, position_y FROM resource_node_spawns WHERE id = $1
+SELECT id, node_id, position_x, position_y, remaining, respawn_ticks_left FROM resource_node_spawns WHERE id = $1
 `
 
 func (q *Queries) GetResourceNodeSpawnById(ctx context.Context, id int32) (ResourceNodeSpawn, error) {
@@ -58,12 +79,14 @@ func (q *Queries) GetResourceNodeSpawnById(ctx context.Context, id int32) (Resou
 		&i.NodeID,
 		&i.PositionX,
 		&i.PositionY,
+		&i.Remaining,
+		&i.RespawnTicksLeft,
 	)
 	return i, err
 }
 
 const getResourceNodeSpawns = `-- name: GetResourceNodeSpawns :many
-SELECT id, node_id, position_x, position_y FROM resource_node_spawns
+SELECT id, node_id, position_x, position_y, remaining, respawn_ticks_left FROM resource_node_spawns
 `
 
 func (q *Queries) GetResourceNodeSpawns(ctx context.Context) ([]ResourceNodeSpawn, error) {
@@ -80,6 +103,8 @@ func (q *Queries) GetResourceNodeSpawns(ctx context.Context) ([]ResourceNodeSpaw
 			&i.NodeID,
 			&i.PositionX,
 			&i.PositionY,
+			&i.Remaining,
+			&i.RespawnTicksLeft,
 		); err != nil {
 			return nil, err
 		}
@@ -92,7 +117,7 @@ func (q *Queries) GetResourceNodeSpawns(ctx context.Context) ([]ResourceNodeSpaw
 }
 
 const getResourceNodeSpawnsByCoordinates = `-- name: GetResourceNodeSpawnsByCoordinates :many
-SELECT id, node_id, position_x, position_y FROM resource_node_spawns WHERE position_x = $1 AND position_y = $2
+SELECT id, node_id, position_x, position_y, remaining, respawn_ticks_left FROM resource_node_spawns WHERE position_x = $1 AND position_y = $2
 `
 
 type GetResourceNodeSpawnsByCoordinatesParams struct {
@@ -114,6 +139,91 @@ func (q *Queries) GetResourceNodeSpawnsByCoordinates(ctx context.Context, arg Ge
 			&i.NodeID,
 			&i.PositionX,
 			&i.PositionY,
+			&i.Remaining,
+			&i.RespawnTicksLeft,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const harvestResourceNodeSpawn = `-- name: HarvestResourceNodeSpawn :one
+UPDATE resource_node_spawns
+SET remaining = remaining - $1::INTEGER,
+	respawn_ticks_left = $2::INTEGER
+WHERE id = $3::INTEGER AND remaining >= $1::INTEGER
+RETURNING id, node_id, position_x, position_y, remaining, respawn_ticks_left
+`
+
+type HarvestResourceNodeSpawnParams struct {
+	Quantity     int32
+	RespawnTicks int32
+	ID           int32
+}
+
+func (q *Queries) HarvestResourceNodeSpawn(ctx context.Context, arg HarvestResourceNodeSpawnParams) (ResourceNodeSpawn, error) {
+	row := q.db.QueryRow(ctx, harvestResourceNodeSpawn, arg.Quantity, arg.RespawnTicks, arg.ID)
+	var i ResourceNodeSpawn
+	err := row.Scan(
+		&i.ID,
+		&i.NodeID,
+		&i.PositionX,
+		&i.PositionY,
+		&i.Remaining,
+		&i.RespawnTicksLeft,
+	)
+	return i, err
+}
+
+const lockResourceNodeSpawn = `-- name: LockResourceNodeSpawn :one
+SELECT id, node_id, position_x, position_y, remaining, respawn_ticks_left FROM resource_node_spawns WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockResourceNodeSpawn(ctx context.Context, id int32) (ResourceNodeSpawn, error) {
+	row := q.db.QueryRow(ctx, lockResourceNodeSpawn, id)
+	var i ResourceNodeSpawn
+	err := row.Scan(
+		&i.ID,
+		&i.NodeID,
+		&i.PositionX,
+		&i.PositionY,
+		&i.Remaining,
+		&i.RespawnTicksLeft,
+	)
+	return i, err
+}
+
+const tickResourceNodeRespawns = `-- name: TickResourceNodeRespawns :many
+UPDATE resource_node_spawns AS s
+SET respawn_ticks_left = GREATEST(s.respawn_ticks_left - 1, 0),
+	remaining = CASE WHEN s.respawn_ticks_left <= 1 THEN n.amount ELSE s.remaining END
+FROM resource_nodes AS n
+WHERE s.node_id = n.id AND s.remaining = 0
+RETURNING s.id, s.node_id, s.position_x, s.position_y, s.remaining, s.respawn_ticks_left
+`
+
+func (q *Queries) TickResourceNodeRespawns(ctx context.Context) ([]ResourceNodeSpawn, error) {
+	rows, err := q.db.Query(ctx, tickResourceNodeRespawns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ResourceNodeSpawn
+	for rows.Next() {
+		var i ResourceNodeSpawn
+		if err := rows.Scan(
+			&i.ID,
+			&i.NodeID,
+			&i.PositionX,
+			&i.PositionY,
+			&i.Remaining,
+			&i.RespawnTicksLeft,
 		); err != nil {
 			return nil, err
 		}
@@ -124,3 +234,39 @@ func (q *Queries) GetResourceNodeSpawnsByCoordinates(ctx context.Context, arg Ge
 	}
 	return items, nil
 }
+
+const upsertResourceNodeSpawn = `-- name: UpsertResourceNodeSpawn :one
+INSERT INTO resource_node_spawns (node_id, position_x, position_y, remaining) VALUES ($1, $2, $3, $4)
+ON CONFLICT (node_id, position_x, position_y) DO UPDATE SET
+	remaining = CASE
+		WHEN EXCLUDED.remaining IS NULL OR resource_node_spawns.remaining IS NULL THEN EXCLUDED.remaining
+		ELSE LEAST(resource_node_spawns.remaining, EXCLUDED.remaining)
+	END
+RETURNING id, node_id, position_x, position_y, remaining, respawn_ticks_left
+`
+
+type UpsertResourceNodeSpawnParams struct {
+	NodeID    int32
+	PositionX int32
+	PositionY int32
+	Remaining pgtype.Int4
+}
+
+func (q *Queries) UpsertResourceNodeSpawn(ctx context.Context, arg UpsertResourceNodeSpawnParams) (ResourceNodeSpawn, error) {
+	row := q.db.QueryRow(ctx, upsertResourceNodeSpawn,
+		arg.NodeID,
+		arg.PositionX,
+		arg.PositionY,
+		arg.Remaining,
+	)
+	var i ResourceNodeSpawn
+	err := row.Scan(
+		&i.ID,
+		&i.NodeID,
+		&i.PositionX,
+		&i.PositionY,
+		&i.Remaining,
+		&i.RespawnTicksLeft,
+	)
+	return i, err
+}
@@ -7,25 +7,50 @@ package database
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createResourceNode = `-- name: CreateResourceNode :exec
-INSERT INTO resource_nodes (name, action_id, tier) VALUES ($1, $2, $3)
+const deleteResourceNodesExcept = `-- name: DeleteResourceNodesExcept :many
+DELETE FROM resource_nodes WHERE id <> ALL($1::INTEGER[])
+RETURNING id, name, action_id, tier, min_tool_tier, min_level, amount, respawn_ticks, interval_ticks, min_quantity, max_quantity, biome
 `
 
-type CreateResourceNodeParams struct {
-	Name     string
-	ActionID int32
-	Tier     int32
-}
-
-func (q *Queries) CreateResourceNode(ctx context.Context, arg CreateResourceNodeParams) error {
-	_, err := q.db.Exec(ctx, createResourceNode, arg.Name, arg.ActionID, arg.Tier)
-	return err
+func (q *Queries) DeleteResourceNodesExcept(ctx context.Context, ids []int32) ([]ResourceNode, error) {
+	rows, err := q.db.Query(ctx, deleteResourceNodesExcept, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ResourceNode
+	for rows.Next() {
+		var i ResourceNode
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ActionID,
+			&i.Tier,
+			&i.MinToolTier,
+			&i.MinLevel,
+			&i.Amount,
+			&i.RespawnTicks,
+			&i.IntervalTicks,
+			&i.MinQuantity,
+			&i.MaxQuantity,
+			&i.Biome,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getResourceNodeById = `-- name: GetResourceNodeById :one
-SELECT id, name, action_id, tier, min_tool_tier FROM resource_nodes WHERE id = $1
+SELECT id, name, action_id, tier, min_tool_tier, min_level, amount, respawn_ticks, interval_ticks, min_quantity, max_quantity, biome FROM resource_nodes WHERE id = $1
 `
 
 func (q *Queries) GetResourceNodeById(ctx context.Context, id int32) (ResourceNode, error) {
@@ -37,12 +62,19 @@ func (q *Queries) GetResourceNodeById(ctx context.Context, id int32) (ResourceNo
 		&i.ActionID,
 		&i.Tier,
 		&i.MinToolTier,
+		&i.MinLevel,
+		&i.Amount,
+		&i.RespawnTicks,
+		&i.IntervalTicks,
+		&i.MinQuantity,
+		&i.MaxQuantity,
+		&i.Biome,
 	)
 	return i, err
 }
 
 const getResourceNodeByName = `-- name: GetResourceNodeByName :one
-SELECT id, name, action_id, tier, min_tool_tier FROM resource_nodes WHERE name = $1
+SELECT id, name, action_id, tier, min_tool_tier, min_level, amount, respawn_ticks, interval_ticks, min_quantity, max_quantity, biome FROM resource_nodes WHERE name = $1
 `
 
 func (q *Queries) GetResourceNodeByName(ctx context.Context, name string) (ResourceNode, error) {
@@ -54,6 +86,112 @@ func (q *Queries) GetResourceNodeByName(ctx context.Context, name string) (Resou
 		&i.ActionID,
 		&i.Tier,
 		&i.MinToolTier,
+		&i.MinLevel,
+		&i.Amount,
+		&i.RespawnTicks,
+		&i.IntervalTicks,
+		&i.MinQuantity,
+		&i.MaxQuantity,
+		&i.Biome,
+	)
+	return i, err
+}
+
+const listResourceNodes = `-- name: ListResourceNodes :many
+SELECT id, name, action_id, tier, min_tool_tier, min_level, amount, respawn_ticks, interval_ticks, min_quantity, max_quantity, biome FROM resource_nodes ORDER BY id
+`
+
+func (q *Queries) ListResourceNodes(ctx context.Context) ([]ResourceNode, error) {
+	rows, err := q.db.Query(ctx, listResourceNodes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ResourceNode
+	for rows.Next() {
+		var i ResourceNode
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ActionID,
+			&i.Tier,
+			&i.MinToolTier,
+			&i.MinLevel,
+			&i.Amount,
+			&i.RespawnTicks,
+			&i.IntervalTicks,
+			&i.MinQuantity,
+			&i.MaxQuantity,
+			&i.Biome,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertResourceNode = `-- name: UpsertResourceNode :one
+INSERT INTO resource_nodes (name, action_id, tier, min_tool_tier, min_level, amount, respawn_ticks, interval_ticks, min_quantity, max_quantity, biome) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (name) DO UPDATE SET
+	action_id = EXCLUDED.action_id,
+	tier = EXCLUDED.tier,
+	min_tool_tier = EXCLUDED.min_tool_tier,
+	min_level = EXCLUDED.min_level,
+	amount = EXCLUDED.amount,
+	respawn_ticks = EXCLUDED.respawn_ticks,
+	interval_ticks = EXCLUDED.interval_ticks,
+	min_quantity = EXCLUDED.min_quantity,
+	max_quantity = EXCLUDED.max_quantity,
+	biome = EXCLUDED.biome
+RETURNING id, name, action_id, tier, min_tool_tier, min_level, amount, respawn_ticks, interval_ticks, min_quantity, max_quantity, biome
+`
+
+type UpsertResourceNodeParams struct {
+	Name          string
+	ActionID      int32
+	Tier          int32
+	MinToolTier   int32
+	MinLevel      int32
+	Amount        pgtype.Int4
+	RespawnTicks  int32
+	IntervalTicks int32
+	MinQuantity   int32
+	MaxQuantity   int32
+	Biome         string
+}
+
+func (q *Queries) UpsertResourceNode(ctx context.Context, arg UpsertResourceNodeParams) (ResourceNode, error) {
+	row := q.db.QueryRow(ctx, upsertResourceNode,
+		arg.Name,
+		arg.ActionID,
+		arg.Tier,
+		arg.MinToolTier,
+		arg.MinLevel,
+		arg.Amount,
+		arg.RespawnTicks,
+		arg.IntervalTicks,
+		arg.MinQuantity,
+		arg.MaxQuantity,
+		arg.Biome,
+	)
+	var i ResourceNode
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ActionID,
+		&i.Tier,
+		&i.MinToolTier,
+		&i.MinLevel,
+		&i.Amount,
+		&i.RespawnTicks,
+		&i.IntervalTicks,
+		&i.MinQuantity,
+		&i.MaxQuantity,
+		&i.Biome,
 	)
 	return i, err
 }
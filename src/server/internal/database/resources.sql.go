@@ -9,18 +9,12 @@ import (
 	"context"
 )
 
-const createResource = `-- name: CreateResource :exec
-INSERT INTO resources (resource_node_id, item_id, drop_chance) VALUES ($1, $2, $3)
+const deleteResourcesExcept = `-- name: DeleteResourcesExcept :exec
+DELETE FROM resources WHERE id <> ALL($1::INTEGER[])
 `
 
-type CreateResourceParams struct {
-	ResourceNodeID int32
-	ItemID         int32
-	DropChance     int32
-}
-
-func (q *Queries) CreateResource(ctx context.Context, arg CreateResourceParams) error {
-	_, err := q.db.Exec(ctx, createResource, arg.ResourceNodeID, arg.ItemID, arg.DropChance)
+func (q *Queries) DeleteResourcesExcept(ctx context.Context, ids []int32) error {
+	_, err := q.db.Exec(ctx, deleteResourcesExcept, ids)
 	return err
 }
 
@@ -53,3 +47,56 @@ func (q *Queries) GetResourcesByNodeId(ctx context.Context, resourceNodeID int32
 	}
 	return items, nil
 }
+
+const listResources = `-- name: ListResources :many
+SELECT id, resource_node_id, item_id, drop_chance FROM resources ORDER BY id
+`
+
+func (q *Queries) ListResources(ctx context.Context) ([]Resource, error) {
+	rows, err := q.db.Query(ctx, listResources)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Resource
+	for rows.Next() {
+		var i Resource
+		if err := rows.Scan(
+			&i.ID,
+			&i.ResourceNodeID,
+			&i.ItemID,
+			&i.DropChance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertResource = `-- name: UpsertResource :one
+INSERT INTO resources (resource_node_id, item_id, drop_chance) VALUES ($1, $2, $3)
+ON CONFLICT (resource_node_id, item_id) DO UPDATE SET drop_chance = EXCLUDED.drop_chance
+RETURNING id, resource_node_id, item_id, drop_chance
+`
+
+type UpsertResourceParams struct {
+	ResourceNodeID int32
+	ItemID         int32
+	DropChance     int32
+}
+
+func (q *Queries) UpsertResource(ctx context.Context, arg UpsertResourceParams) (Resource, error) {
+	row := q.db.QueryRow(ctx, upsertResource, arg.ResourceNodeID, arg.ItemID, arg.DropChance)
+	var i Resource
+	err := row.Scan(
+		&i.ID,
+		&i.ResourceNodeID,
+		&i.ItemID,
+		&i.DropChance,
+	)
+	return i, err
+}
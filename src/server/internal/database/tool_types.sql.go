@@ -9,6 +9,31 @@ import (
 	"context"
 )
 
+const deleteToolTypesExcept = `-- name: DeleteToolTypesExcept :many
+DELETE FROM tool_types WHERE id <> ALL($1::INTEGER[])
+RETURNING id, name, tier
+`
+
+func (q *Queries) DeleteToolTypesExcept(ctx context.Context, ids []int32) ([]ToolType, error) {
+	rows, err := q.db.Query(ctx, deleteToolTypesExcept, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ToolType
+	for rows.Next() {
+		var i ToolType
+		if err := rows.Scan(&i.ID, &i.Name, &i.Tier); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getToolTypeById = `-- name: GetToolTypeById :one
 SELECT id, name, tier FROM tool_types WHERE id = $1
 `
@@ -19,3 +44,51 @@ func (q *Queries) GetToolTypeById(ctx context.Context, id int32) (ToolType, erro
 	err := row.Scan(&i.ID, &i.Name, &i.Tier)
 	return i, err
 }
+
+const getToolTypeByName = `-- name: GetToolTypeByName :one
+SELECT id, name, tier FROM tool_types WHERE name = $1
+`
+
+func (q *Queries) GetToolTypeByName(ctx context.Context, name string) (ToolType, error) {
+	row := q.db.QueryRow(ctx, getToolTypeByName, name)
+	var i ToolType
+	err := row.Scan(&i.ID, &i.Name, &i.Tier)
+	return i, err
+}
+
+const listToolTypes = `-- name: ListToolTypes :many
+SELECT id, name, tier FROM tool_types ORDER BY id
+`
+
+func (q *Queries) ListToolTypes(ctx context.Context) ([]ToolType, error) {
+	rows, err := q.db.Query(ctx, listToolTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ToolType
+	for rows.Next() {
+		var i ToolType
+		if err := rows.Scan(&i.ID, &i.Name, &i.Tier); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertToolType = `-- name: UpsertToolType :one
+INSERT INTO tool_types (name) VALUES ($1)
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, name, tier
+`
+
+func (q *Queries) UpsertToolType(ctx context.Context, name string) (ToolType, error) {
+	row := q.db.QueryRow(ctx, upsertToolType, name)
+	var i ToolType
+	err := row.Scan(&i.ID, &i.Name, &i.Tier)
+	return i, err
+}
@@ -21,7 +21,7 @@ VALUES (
 	$2,
 	$3
 )
-RETURNING id, email, hashed_password, created_at, updated_at, surname
+RETURNING id, email, hashed_password, created_at, updated_at, surname, last_seen_at
 `
 
 type CreateUserParams struct {
@@ -40,6 +40,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Surname,
+		&i.LastSeenAt,
 	)
 	return i, err
 }
@@ -57,7 +58,7 @@ func (q *Queries) GetSurnameById(ctx context.Context, id pgtype.UUID) (pgtype.Te
 }
 
 const getUserById = `-- name: GetUserById :one
-SELECT id, email, hashed_password, created_at, updated_at, surname from users
+SELECT id, email, hashed_password, created_at, updated_at, surname, last_seen_at from users
 WHERE id = $1
 `
 
@@ -71,10 +72,44 @@ func (q *Queries) GetUserById(ctx context.Context, id pgtype.UUID) (User, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Surname,
+		&i.LastSeenAt,
 	)
 	return i, err
 }
 
+const markOnlineUsersSeen = `-- name: MarkOnlineUsersSeen :exec
+UPDATE users
+SET last_seen_at = NOW()
+WHERE last_seen_at IS NULL
+`
+
+func (q *Queries) MarkOnlineUsersSeen(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, markOnlineUsersSeen)
+	return err
+}
+
+const markUserOnline = `-- name: MarkUserOnline :exec
+UPDATE users
+SET last_seen_at = NULL
+WHERE id = $1
+`
+
+func (q *Queries) MarkUserOnline(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markUserOnline, id)
+	return err
+}
+
+const markUserSeen = `-- name: MarkUserSeen :exec
+UPDATE users
+SET last_seen_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkUserSeen(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markUserSeen, id)
+	return err
+}
+
 const resetUsers = `-- name: ResetUsers :exec
 DELETE FROM users
 `
@@ -90,7 +125,7 @@ SET email = $2,
 	hashed_password = $3,
 	updated_at = NOW()
 WHERE id = $1
-RETURNING id, email, hashed_password, created_at, updated_at, surname
+RETURNING id, email, hashed_password, created_at, updated_at, surname, last_seen_at
 `
 
 type UpdateUserByIdParams struct {
@@ -109,6 +144,7 @@ func (q *Queries) UpdateUserById(ctx context.Context, arg UpdateUserByIdParams)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Surname,
+		&i.LastSeenAt,
 	)
 	return i, err
 }
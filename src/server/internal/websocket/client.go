@@ -48,6 +48,7 @@ type Provider interface {
 	GetSurnameById(ctx context.Context, userID uuid.UUID) (string, error)
 	ValidateCharacterOwnership(ctx context.Context, characterName string, userID uuid.UUID) (bool, error)
 	ValidateSpecificToken(ctx context.Context, tokenID string) error
+	MarkUserAway(ctx context.Context, userID uuid.UUID)
 }
 
 type RateLimiter interface {
@@ -194,7 +195,7 @@ func (c *Client) readPump() {
 
 			message.To = "all"
 
-			c.hub.broadcast <- &message
+			c.hub.publish(&message)
 		case "ping":
 			c.send <- &Message{
 				Type: "pong",
@@ -1,19 +1,46 @@
 package websocket
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 )
 
+// hubChannel carries every message between replicas. Each hub delivers what
+// it receives to whichever of its own clients the message is for.
+const hubChannel = "ws:messages"
+
+// replicasKey scores every running replica by when it last heartbeat. A
+// replica that stops heartbeating is taken to have died, and whichever
+// replica notices first clears out the connections it left behind.
+const replicasKey = "ws:replicas"
+
+const (
+	replicaHeartbeat = 10 * time.Second
+	replicaTTL       = 3 * replicaHeartbeat
+)
+
+// disconnectType is published to close a connection on whichever replica
+// holds it. Clients never see it.
+const disconnectType = "disconnect"
+
 type Hub struct {
-	clients          map[string]*Client
-	userConnections  map[uuid.UUID][]*ClientInfo
-	broadcast        chan *Message
-	register         chan *Client
-	unregister       chan *Client
-	maxConnections   int
+	clients         map[string]*Client
+	userConnections map[uuid.UUID][]*Client
+	broadcast       chan *Message
+	register        chan *Client
+	unregister      chan *Client
+	maxConnections  int
+	redis           *redis.Client
+	replicaID       string
+	provider        Provider
 }
 
 type Message struct {
@@ -23,77 +50,127 @@ type Message struct {
 	Data   map[string]interface{} `json:"data"`
 }
 
-type ClientInfo struct {
-	client    *Client
-	timestamp time.Time
-}
-
-func NewHub() *Hub {
+func NewHub(rdb *redis.Client) *Hub {
 	return &Hub{
 		clients:         make(map[string]*Client),
-		userConnections: make(map[uuid.UUID][]*ClientInfo),
+		userConnections: make(map[uuid.UUID][]*Client),
 		broadcast:       make(chan *Message, 256),
 		register:        make(chan *Client),
 		unregister:      make(chan *Client),
 		maxConnections:  5,
+		redis:           rdb,
+		replicaID:       uuid.NewString(),
 	}
 }
 
+func userConnectionsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("ws:connections:user:%s", userID.String())
+}
+
+// replicaConnectionsKey holds the connections on one replica, as
+// "<user id> <token id>", so they can be found again if it dies.
+func replicaConnectionsKey(replicaID string) string {
+	return fmt.Sprintf("ws:connections:replica:%s", replicaID)
+}
+
+// Start registers this replica and clears out the connections of any that
+// died, marking their users away through provider. It reports whether any
+// other replica is running, when none is nobody can be connected at all.
+func (h *Hub) Start(ctx context.Context, provider Provider) (bool, error) {
+	h.provider = provider
+
+	err := h.heartbeat(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	others, err := h.redis.ZCard(ctx, replicasKey).Result()
+	if err != nil {
+		return false, err
+	}
+	return others > 1, nil
+}
+
 func (h *Hub) Run() {
+	go h.subscribe()
+	go h.keepAlive()
+
 	for {
 		select {
 		case client := <-h.register:
-			h.enforceConnectionLimit(client)
 			h.clients[client.tokenID] = client
-			
-			clientInfo := &ClientInfo{
-				client:    client,
-				timestamp: time.Now(),
-			}
-			h.userConnections[client.userID] = append(h.userConnections[client.userID], clientInfo)
+			h.userConnections[client.userID] = append(h.userConnections[client.userID], client)
+			go h.trackConnection(client)
 
 		case client := <-h.unregister:
 			if _, ok := h.clients[client.tokenID]; ok {
-				delete(h.clients, client.tokenID)
-				h.removeUserConnection(client)
-				close(client.send)
+				h.removeClient(client)
 			}
 
 		case message := <-h.broadcast:
+			if message.Type == disconnectType {
+				h.disconnectLocal(message)
+				continue
+			}
+
+			var targets []*Client
 			if message.To == "all" {
 				for _, client := range h.clients {
-					select {
-					case client.send <- message:
-					default:
-						close(client.send)
-						delete(h.clients, client.tokenID)
-					}
+					targets = append(targets, client)
 				}
 			} else if message.To != "" {
 				if targetID, err := uuid.Parse(message.To); err == nil {
-					for _, client := range h.clients {
-						if client.userID == targetID {
-							select {
-							case client.send <- message:
-							default:
-								close(client.send)
-								delete(h.clients, client.tokenID)
-							}
-						}
-					}
+					targets = append(targets, h.userConnections[targetID]...)
+				}
+			}
+
+			for _, client := range targets {
+				select {
+				case client.send <- message:
+				default:
+					h.removeClient(client)
 				}
 			}
 		}
 	}
 }
 
+// subscribe feeds messages published by any replica, this one included, to
+// the hub's local delivery.
+func (h *Hub) subscribe() {
+	pubsub := h.redis.Subscribe(context.Background(), hubChannel)
+	defer pubsub.Close()
+
+	for received := range pubsub.Channel() {
+		var message Message
+		if err := json.Unmarshal([]byte(received.Payload), &message); err != nil {
+			log.Printf("Failed to decode hub message: %v", err)
+			continue
+		}
+		h.broadcast <- &message
+	}
+}
+
+// publish sends a message to every replica. If Redis can't be reached the
+// message still goes to this replica's clients.
+func (h *Hub) publish(message *Message) {
+	payload, err := json.Marshal(message)
+	if err == nil {
+		err = h.redis.Publish(context.Background(), hubChannel, payload).Err()
+	}
+	if err != nil {
+		log.Printf("Failed to publish %s message, delivering locally: %v", message.Type, err)
+		h.broadcast <- message
+	}
+}
+
 func (h *Hub) SendToUser(userID uuid.UUID, msgType string, data map[string]interface{}) {
 	message := &Message{
 		Type: msgType,
 		To:   userID.String(),
 		Data: data,
 	}
-	h.broadcast <- message
+	h.publish(message)
 }
 
 func (h *Hub) SendToAll(msgType string, data map[string]interface{}) {
@@ -102,7 +179,7 @@ func (h *Hub) SendToAll(msgType string, data map[string]interface{}) {
 		To:   "all",
 		Data: data,
 	}
-	h.broadcast <- message
+	h.publish(message)
 }
 
 func (h *Hub) SendNotificationToUser(userID uuid.UUID, message string, severity string) {
@@ -120,60 +197,193 @@ func (h *Hub) SendSystemMessage(message string) {
 	h.SendToAll("system", data)
 }
 
+// DisconnectClientByToken closes the connection using a token, on whichever
+// replica holds it.
 func (h *Hub) DisconnectClientByToken(tokenID string) {
-	if client, ok := h.clients[tokenID]; ok {
-		select {
-		case client.send <- &Message{
-			Type: "error",
-			Data: map[string]interface{}{"message": "Session expired. Please reconnect."},
-		}:
-		default:
+	h.publish(&Message{
+		Type: disconnectType,
+		Data: map[string]interface{}{
+			"token_id": tokenID,
+			"message":  "Session expired. Please reconnect.",
+		},
+	})
+}
+
+// disconnectLocal closes the connection named by a disconnect message if it
+// is held here, telling the client why first.
+func (h *Hub) disconnectLocal(message *Message) {
+	tokenID, _ := message.Data["token_id"].(string)
+	client, ok := h.clients[tokenID]
+	if !ok {
+		return
+	}
+
+	select {
+	case client.send <- &Message{
+		Type: "error",
+		Data: map[string]interface{}{"message": message.Data["message"]},
+	}:
+	default:
+	}
+
+	h.removeClient(client)
+}
+
+func (h *Hub) removeClient(client *Client) {
+	delete(h.clients, client.tokenID)
+	h.removeUserConnection(client)
+	close(client.send)
+	go h.untrackConnection(client)
+}
+
+// keepAlive heartbeats this replica until the process exits.
+func (h *Hub) keepAlive() {
+	ticker := time.NewTicker(replicaHeartbeat)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := h.heartbeat(context.Background())
+		if err != nil {
+			log.Printf("Failed to send hub heartbeat: %v", err)
 		}
-		
-		h.unregister <- client
-	}
-}
-
-func (h *Hub) enforceConnectionLimit(newClient *Client) {
-	userConnections := h.userConnections[newClient.userID]
-	
-	if len(userConnections) >= h.maxConnections {
-		oldestIndex := 0
-		oldestTime := userConnections[0].timestamp
-		
-		for i, conn := range userConnections {
-			if conn.timestamp.Before(oldestTime) {
-				oldestIndex = i
-				oldestTime = conn.timestamp
-			}
+	}
+}
+
+// heartbeat marks this replica alive and prunes any that have stopped.
+func (h *Hub) heartbeat(ctx context.Context) error {
+	now := time.Now()
+	err := h.redis.ZAdd(ctx, replicasKey, redis.Z{Score: float64(now.UnixMilli()), Member: h.replicaID}).Err()
+	if err != nil {
+		return err
+	}
+
+	cutoff := strconv.FormatInt(now.Add(-replicaTTL).UnixMilli(), 10)
+	dead, err := h.redis.ZRangeByScore(ctx, replicasKey, &redis.ZRangeBy{Min: "-inf", Max: "(" + cutoff}).Result()
+	if err != nil {
+		return err
+	}
+	for _, replicaID := range dead {
+		err := h.pruneReplica(ctx, replicaID)
+		if err != nil {
+			return fmt.Errorf("pruning replica %s: %w", replicaID, err)
 		}
-		
-		oldestClient := userConnections[oldestIndex].client
-		log.Printf("User %s exceeded connection limit (%d), disconnecting oldest connection", 
-			newClient.userID.String(), h.maxConnections)
-		
-		select {
-		case oldestClient.send <- &Message{
-			Type: "error",
-			Data: map[string]interface{}{"message": "Connection limit exceeded. Disconnecting oldest session."},
-		}:
-		default:
+	}
+	return nil
+}
+
+// pruneReplica drops every connection a dead replica left behind. Users with
+// no connections left on any replica are away.
+func (h *Hub) pruneReplica(ctx context.Context, replicaID string) error {
+	key := replicaConnectionsKey(replicaID)
+	connections, err := h.redis.SMembers(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	away := make(map[uuid.UUID]bool)
+	for _, connection := range connections {
+		userPart, tokenID, ok := strings.Cut(connection, " ")
+		userID, err := uuid.Parse(userPart)
+		if !ok || err != nil {
+			continue
 		}
-		
-		h.unregister <- oldestClient
+
+		userKey := userConnectionsKey(userID)
+		pipe := h.redis.TxPipeline()
+		pipe.ZRem(ctx, userKey, tokenID)
+		remaining := pipe.ZCard(ctx, userKey)
+		_, err = pipe.Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if remaining.Val() == 0 {
+			away[userID] = true
+		}
+	}
+
+	pipe := h.redis.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.ZRem(ctx, replicasKey, replicaID)
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	for userID := range away {
+		h.provider.MarkUserAway(ctx, userID)
+	}
+	if len(connections) > 0 {
+		log.Printf("Pruned %d connections left by stopped replica %s", len(connections), replicaID)
+	}
+	return nil
+}
+
+func replicaConnection(client *Client) string {
+	return client.userID.String() + " " + client.tokenID
+}
+
+// trackConnection counts a new connection against the user's limit across
+// every replica, disconnecting the oldest ones beyond it.
+func (h *Hub) trackConnection(client *Client) {
+	ctx := context.Background()
+	key := userConnectionsKey(client.userID)
+
+	pipe := h.redis.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(time.Now().UnixNano()), Member: client.tokenID})
+	pipe.SAdd(ctx, replicaConnectionsKey(h.replicaID), replicaConnection(client))
+	oldest := pipe.ZRange(ctx, key, 0, int64(-h.maxConnections-1))
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		log.Printf("Failed to track connection for user %s: %v", client.userID, err)
+		return
+	}
+
+	for _, tokenID := range oldest.Val() {
+		log.Printf("User %s exceeded connection limit (%d), disconnecting oldest connection",
+			client.userID.String(), h.maxConnections)
+
+		h.redis.ZRem(ctx, key, tokenID)
+		h.publish(&Message{
+			Type: disconnectType,
+			Data: map[string]interface{}{
+				"token_id": tokenID,
+				"message":  "Connection limit exceeded. Disconnecting oldest session.",
+			},
+		})
+	}
+}
+
+// untrackConnection drops a closed connection from the user's count. The
+// user is away once their last connection on any replica closes.
+func (h *Hub) untrackConnection(client *Client) {
+	ctx := context.Background()
+	key := userConnectionsKey(client.userID)
+
+	pipe := h.redis.TxPipeline()
+	pipe.ZRem(ctx, key, client.tokenID)
+	pipe.SRem(ctx, replicaConnectionsKey(h.replicaID), replicaConnection(client))
+	remaining := pipe.ZCard(ctx, key)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		log.Printf("Failed to untrack connection for user %s: %v", client.userID, err)
+		return
+	}
+
+	if remaining.Val() == 0 {
+		client.provider.MarkUserAway(ctx, client.userID)
 	}
 }
 
 func (h *Hub) removeUserConnection(client *Client) {
 	userConnections := h.userConnections[client.userID]
-	
+
 	for i, conn := range userConnections {
-		if conn.client == client {
+		if conn == client {
 			h.userConnections[client.userID] = append(userConnections[:i], userConnections[i+1:]...)
 			break
 		}
 	}
-	
+
 	if len(h.userConnections[client.userID]) == 0 {
 		delete(h.userConnections, client.userID)
 	}
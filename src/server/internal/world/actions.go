@@ -0,0 +1,79 @@
+package world
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/trbute/idler/server/internal/database"
+)
+
+// ActionHandler runs one tick of an action for a character. Whatever it
+// returns is written with the rest of the tick's batched updates.
+type ActionHandler interface {
+	HandleTick(char database.Character, action database.Action) *TickUpdate
+}
+
+// ActionHandlerFunc lets a plain function be used as an ActionHandler.
+type ActionHandlerFunc func(char database.Character, action database.Action) *TickUpdate
+
+func (f ActionHandlerFunc) HandleTick(char database.Character, action database.Action) *TickUpdate {
+	return f(char, action)
+}
+
+// gatheringActions are the actions performed on resource nodes.
+var gatheringActions = []string{"GATHERING", "WOODCUTTING", "STONEBREAKING", "MINING"}
+
+// RegisterActionHandler sets the handler for an action name, replacing any
+// handler already registered for it. Handlers must be registered before
+// ProcessTicks starts.
+func (cfg *WorldConfig) RegisterActionHandler(name string, handler ActionHandler) {
+	if cfg.handlers == nil {
+		cfg.handlers = make(map[string]ActionHandler)
+	}
+	cfg.handlers[name] = handler
+}
+
+// registerDefaultActionHandlers fills in the built in handlers for any action
+// that hasn't been given one already.
+func (cfg *WorldConfig) registerDefaultActionHandlers() {
+	defaults := map[string]ActionHandler{
+		"TRAVELING": ActionHandlerFunc(cfg.processTravel),
+		"CRAFTING":  ActionHandlerFunc(cfg.processCrafting),
+		"MELEE":     ActionHandlerFunc(cfg.processCombat),
+		"ARCHERY":   ActionHandlerFunc(cfg.processCombat),
+	}
+	for _, name := range gatheringActions {
+		defaults[name] = ActionHandlerFunc(cfg.processResourceGathering)
+	}
+
+	for name, handler := range defaults {
+		if _, ok := cfg.handlers[name]; !ok {
+			cfg.RegisterActionHandler(name, handler)
+		}
+	}
+}
+
+func (cfg *WorldConfig) processCharacterAction(char database.Character) *TickUpdate {
+	ctx := context.Background()
+
+	action, err := cfg.GetActionById(ctx, char.ActionID)
+	if err != nil {
+		log.Printf("Error getting action for character %s: %v", char.Name, err)
+		return nil
+	}
+
+	handler, ok := cfg.handlers[action.Name]
+	if !ok {
+		log.Printf("No handler for action %s, setting character %s to idle", action.Name, char.Name)
+		message := fmt.Sprintf("Character %s can't perform %s yet and is now idle", char.Name, action.Name)
+		return idleUpdate(message, "warning")
+	}
+
+	start := time.Now()
+	update := handler.HandleTick(char, action)
+	cfg.telemetry.handlerTime(action.Name, time.Since(start))
+
+	return update
+}
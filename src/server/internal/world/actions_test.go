@@ -0,0 +1,31 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/trbute/idler/server/internal/database"
+)
+
+func TestRegisterDefaultActionHandlers(t *testing.T) {
+	cfg := &WorldConfig{}
+
+	custom := ActionHandlerFunc(func(char database.Character, action database.Action) *TickUpdate {
+		return &TickUpdate{}
+	})
+	cfg.RegisterActionHandler("CRAFTING", custom)
+	cfg.registerDefaultActionHandlers()
+
+	for _, name := range append([]string{"TRAVELING", "CRAFTING", "MELEE", "ARCHERY"}, gatheringActions...) {
+		if _, ok := cfg.handlers[name]; !ok {
+			t.Errorf("no handler registered for %s", name)
+		}
+	}
+
+	if update := cfg.handlers["CRAFTING"].HandleTick(database.Character{}, database.Action{}); update == nil {
+		t.Errorf("default handler replaced the custom CRAFTING handler")
+	}
+
+	if _, ok := cfg.handlers["WEAVING"]; ok {
+		t.Errorf("WEAVING should have no handler")
+	}
+}
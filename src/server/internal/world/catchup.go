@@ -0,0 +1,235 @@
+package world
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/api"
+	"github.com/trbute/idler/server/internal/database"
+)
+
+// catchUp replays the ticks missed while the server was down, one at a time
+// through the same path as live ticks, so limits, capacity and idling all
+// behave as if the server had never stopped. Nothing is pushed while
+// replaying, connected owners get one summary per character at the end.
+// Anything beyond MaxCatchUpTicks is forfeited. Only the shards given are
+// replayed, which are the ones this instance just acquired. The rest were
+// kept ticking live, by this instance or their own holders.
+func (cfg *WorldConfig) catchUp(ctx context.Context, lease *tickLease, shards tickShards) {
+	state, err := cfg.DB.GetWorldState(ctx)
+	if err != nil {
+		log.Printf("Error getting world state, skipping catch up: %v", err)
+		return
+	}
+	cfg.tick = state.LastTick
+
+	if !state.LastTickAt.Valid || cfg.MaxCatchUpTicks <= 0 {
+		return
+	}
+
+	start := time.Now()
+	lastTickAt := state.LastTickAt.Time
+
+	cfg.catchingUp = newCatchUpSummary(lastTickAt)
+	defer func() {
+		summary := cfg.catchingUp
+		cfg.catchingUp = nil
+		cfg.sendCatchUpSummary(ctx, summary)
+	}()
+	budget := int64(cfg.MaxCatchUpTicks)
+	var replayed, forfeited int64
+
+	// Replaying takes time of its own, so keep going until nothing is owed
+	for budget > 0 {
+		now := time.Now()
+		missed, skipped := missedTicks(lastTickAt, now, cfg.TickRate, budget)
+		if missed == 0 {
+			break
+		}
+		if skipped > 0 {
+			forfeited += skipped
+			lastTickAt = lastTickAt.Add(time.Duration(skipped) * cfg.TickRate)
+		}
+
+		for range missed {
+			// Replaying can take longer than the lease lasts
+			if !lease.renew(ctx) {
+				log.Printf("Lost tick leadership after catching up %d ticks", replayed)
+				return
+			}
+			cfg.runTick(ctx, lease, shards)
+			lastTickAt = lastTickAt.Add(cfg.TickRate)
+			cfg.recordTick(ctx, lastTickAt)
+			replayed++
+		}
+		budget -= missed
+	}
+
+	if replayed == 0 {
+		return
+	}
+
+	// Whatever was still owed when the budget ran out is dropped, so record
+	// the present rather than leaving it for the next restart
+	if budget == 0 {
+		cfg.recordTick(ctx, time.Now())
+	}
+
+	log.Printf("Caught up %d missed ticks in %v (%d forfeited)",
+		replayed, time.Since(start).Round(time.Millisecond), forfeited)
+}
+
+// missedTicks returns how many whole ticks fit between the last tick and now,
+// capped at budget, along with how many older ticks the cap skipped.
+func missedTicks(lastTickAt, now time.Time, tickRate time.Duration, budget int64) (int64, int64) {
+	if tickRate <= 0 || budget <= 0 || !now.After(lastTickAt) {
+		return 0, 0
+	}
+
+	missed := int64(now.Sub(lastTickAt) / tickRate)
+	if missed > budget {
+		return budget, missed - budget
+	}
+	return missed, 0
+}
+
+// recordTick stores the tick count and when the tick happened, so a restart
+// knows how far behind it is.
+func (cfg *WorldConfig) recordTick(ctx context.Context, at time.Time) {
+	cfg.tick++
+	err := cfg.DB.UpdateWorldState(ctx, database.UpdateWorldStateParams{
+		LastTick:   cfg.tick,
+		LastTickAt: pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		log.Printf("Error recording tick %d: %v", cfg.tick, err)
+	}
+}
+
+// catchUpSummary adds up what the replayed ticks did, so owners hear about
+// it once rather than for every tick.
+type catchUpSummary struct {
+	since           time.Time
+	characters      map[pgtype.UUID]database.Character
+	inventoryOwners map[pgtype.UUID]pgtype.UUID
+	gains           map[inventoryItem]int32
+	progress        map[pgtype.UUID]api.CharacterProgressUpdate
+	changes         map[pgtype.UUID]api.CharacterChange
+	notifications   []api.Notification
+}
+
+type inventoryItem struct {
+	inventoryID pgtype.UUID
+	itemID      int32
+}
+
+func newCatchUpSummary(since time.Time) *catchUpSummary {
+	return &catchUpSummary{
+		since:           since,
+		characters:      make(map[pgtype.UUID]database.Character),
+		inventoryOwners: make(map[pgtype.UUID]pgtype.UUID),
+		gains:           make(map[inventoryItem]int32),
+		progress:        make(map[pgtype.UUID]api.CharacterProgressUpdate),
+		changes:         make(map[pgtype.UUID]api.CharacterChange),
+	}
+}
+
+func (s *catchUpSummary) add(w *tickWrites, result tickResult) {
+	for id, char := range w.characters {
+		s.characters[id] = char
+	}
+	for inventoryID, owner := range w.inventoryOwners {
+		s.inventoryOwners[inventoryID] = owner
+	}
+	for _, update := range result.applied {
+		s.gains[inventoryItem{update.InventoryID, update.ItemID}] += update.Quantity
+	}
+	// Progress is a running total, so the last tick's is the one to show
+	for _, update := range result.progress {
+		s.progress[update.CharacterID] = update
+		if change, ok := s.changes[update.CharacterID]; ok {
+			change.Idled = false
+			s.changes[update.CharacterID] = change
+		}
+	}
+	// Likewise a character that went idle shows idle, unless it started
+	// acting again in a later tick
+	for _, change := range result.changes {
+		id := change.Character.ID
+		merged := s.changes[id]
+		merged.Character = change.Character
+		merged.Idled = merged.Idled || change.Idled
+		merged.Inventory = merged.Inventory || change.Inventory
+		s.changes[id] = merged
+		if change.Idled {
+			delete(s.progress, id)
+		}
+	}
+	s.notifications = append(s.notifications, result.notifications...)
+}
+
+// sendCatchUpSummary pushes the caught up inventory and progress in one go
+// and sends each connected owner what their characters gained and what
+// happened to them along the way.
+func (cfg *WorldConfig) sendCatchUpSummary(ctx context.Context, s *catchUpSummary) {
+	var applied []api.InventoryUpdate
+	for key, quantity := range s.gains {
+		applied = append(applied, api.InventoryUpdate{
+			InventoryID: key.inventoryID,
+			ItemID:      key.itemID,
+			Quantity:    quantity,
+		})
+	}
+	progress := make([]api.CharacterProgressUpdate, 0, len(s.progress))
+	for _, update := range s.progress {
+		progress = append(progress, update)
+	}
+	cfg.pushTickUpdates(ctx, s.characters, s.inventoryOwners, applied, progress)
+	changes := make([]api.CharacterChange, 0, len(s.changes))
+	for _, change := range s.changes {
+		changes = append(changes, change)
+	}
+	cfg.SendCharacterChanges(changes)
+
+	summaries := make(map[uuid.UUID]map[pgtype.UUID]*api.CharacterAwaySummary)
+	characterSummary := func(char database.Character) *api.CharacterAwaySummary {
+		userID := uuid.UUID(char.UserID.Bytes)
+		if summaries[userID] == nil {
+			summaries[userID] = make(map[pgtype.UUID]*api.CharacterAwaySummary)
+		}
+		summary, ok := summaries[userID][char.ID]
+		if !ok {
+			summary = &api.CharacterAwaySummary{Name: char.Name}
+			summaries[userID][char.ID] = summary
+		}
+		return summary
+	}
+
+	for _, update := range applied {
+		char, ok := s.characters[s.inventoryOwners[update.InventoryID]]
+		if !ok || update.Quantity == 0 {
+			continue
+		}
+		item, err := cfg.GetItemById(ctx, update.ItemID)
+		if err != nil {
+			continue
+		}
+		summary := characterSummary(char)
+		summary.Items = append(summary.Items, api.ItemGain{Name: item.Name, Quantity: update.Quantity})
+	}
+	for _, n := range s.notifications {
+		summary := characterSummary(n.Character)
+		summary.Events = append(summary.Events, n.Message)
+	}
+
+	for userID, byCharacter := range summaries {
+		away := &api.AwaySummary{Since: s.since}
+		for _, summary := range byCharacter {
+			away.Characters = append(away.Characters, *summary)
+		}
+		cfg.SendAwaySummary(userID, away)
+	}
+}
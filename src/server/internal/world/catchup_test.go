@@ -0,0 +1,80 @@
+package world
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/api"
+	"github.com/trbute/idler/server/internal/database"
+)
+
+func TestMissedTicks(t *testing.T) {
+	last := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rate := time.Second
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		budget      int64
+		wantMissed  int64
+		wantSkipped int64
+	}{
+		{"no time passed", last, 10, 0, 0},
+		{"clock went backwards", last.Add(-time.Minute), 10, 0, 0},
+		{"partial tick", last.Add(1500 * time.Millisecond), 10, 1, 0},
+		{"within budget", last.Add(5 * time.Second), 10, 5, 0},
+		{"over budget", last.Add(25 * time.Second), 10, 10, 15},
+		{"no budget", last.Add(5 * time.Second), 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		missed, skipped := missedTicks(last, tt.now, rate, tt.budget)
+		if missed != tt.wantMissed || skipped != tt.wantSkipped {
+			t.Errorf("%s: missedTicks = (%d, %d), want (%d, %d)",
+				tt.name, missed, skipped, tt.wantMissed, tt.wantSkipped)
+		}
+	}
+}
+
+func TestCatchUpSummaryAdd(t *testing.T) {
+	char := database.Character{ID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}, Name: "ada"}
+	inventoryID := pgtype.UUID{Bytes: [16]byte{2}, Valid: true}
+	w := newTickWrites([]database.Character{char})
+	w.inventoryOwners[inventoryID] = char.ID
+
+	s := newCatchUpSummary(time.Now())
+	for tick := int32(1); tick <= 3; tick++ {
+		s.add(w, tickResult{
+			applied:  []api.InventoryUpdate{{InventoryID: inventoryID, ItemID: 7, Quantity: 2}},
+			progress: []api.CharacterProgressUpdate{{CharacterID: char.ID, Progress: tick * 2}},
+			notifications: []api.Notification{
+				{Character: char, Message: "tick", Severity: "info"},
+			},
+		})
+	}
+
+	if got := s.gains[inventoryItem{inventoryID, 7}]; got != 6 {
+		t.Errorf("gains = %d, want 6", got)
+	}
+	if got := s.progress[char.ID].Progress; got != 6 {
+		t.Errorf("progress = %d, want the last tick's 6", got)
+	}
+	if len(s.notifications) != 3 {
+		t.Errorf("kept %d notifications, want 3", len(s.notifications))
+	}
+
+	s.add(w, tickResult{
+		changes: []api.CharacterChange{{Character: char, Idled: true, Inventory: true}},
+	})
+	if _, ok := s.progress[char.ID]; ok {
+		t.Errorf("kept progress for a character that went idle")
+	}
+
+	s.add(w, tickResult{
+		progress: []api.CharacterProgressUpdate{{CharacterID: char.ID, Progress: 1}},
+	})
+	if change := s.changes[char.ID]; change.Idled || !change.Inventory {
+		t.Errorf("changes = %+v, want acting again with its inventory still changed", change)
+	}
+}
@@ -0,0 +1,215 @@
+package world
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/api"
+	"github.com/trbute/idler/server/internal/database"
+)
+
+// StrikeClaim is an attack on a creature spawn. Strikes are settled when
+// the tick commits, with the spawn locked, so characters fighting the same
+// creature share one health pool and only one of them lands the killing
+// blow.
+type StrikeClaim struct {
+	SpawnID      int32
+	CreatureName string
+	RespawnTicks int32
+	Damage       int32
+	// DamageTaken is dealt back to the character unless its strike kills
+	DamageTaken int32
+	// Loot and Progress only go to whoever lands the killing blow
+	Loot     []api.InventoryUpdate
+	Progress api.CharacterProgressUpdate
+}
+
+type characterStrike struct {
+	character database.Character
+	claim     StrikeClaim
+}
+
+func (cfg *WorldConfig) processCombat(char database.Character, action database.Action) *TickUpdate {
+	ctx := context.Background()
+
+	if !char.ActionCreatureTarget.Valid {
+		log.Printf("Character %s has no creature to fight", char.Name)
+		return nil
+	}
+
+	spawn, err := cfg.DB.GetCreatureSpawnById(ctx, char.ActionCreatureTarget.Int32)
+	if err != nil {
+		log.Printf("Error getting creature spawn for character %s: %v", char.Name, err)
+		return nil
+	}
+
+	creature, err := cfg.GetCreatureById(ctx, spawn.CreatureID)
+	if err != nil {
+		log.Printf("Error getting creature for character %s: %v", char.Name, err)
+		return nil
+	}
+
+	level := int32(1)
+	skill, err := cfg.DB.GetCharacterSkill(ctx, database.GetCharacterSkillParams{
+		CharacterID: char.ID,
+		ActionID:    action.ID,
+	})
+	if err == nil {
+		level = api.LevelForExperience(skill.Experience)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("Error getting %s skill for character %s: %v", action.Name, char.Name, err)
+		return nil
+	}
+
+	inventory, err := cfg.GetInventoryByCharacterId(ctx, char.ID)
+	if err != nil {
+		log.Printf("Error getting inventory for character %s: %v", char.Name, err)
+		return nil
+	}
+
+	drops, err := cfg.GetCreatureDropsByCreatureId(ctx, creature.ID)
+	if err != nil {
+		log.Printf("Error getting loot for creature %s: %v", creature.Name, err)
+		return nil
+	}
+
+	strike := &StrikeClaim{
+		SpawnID:      spawn.ID,
+		CreatureName: creature.Name,
+		RespawnTicks: creature.RespawnTicks,
+		Damage:       api.CombatDamage(action.Name, level),
+		DamageTaken:  api.DamageTaken(action.Name, creature.Damage),
+		Progress: api.CharacterProgressUpdate{
+			CharacterID: char.ID,
+			Progress:    char.ActionAmountProgress.Int32,
+			ActionID:    action.ID,
+			Experience:  api.ExperienceForKill(creature.Tier, creature.MaxHp),
+		},
+	}
+	for _, drop := range cfg.rollLoot(drops) {
+		strike.Loot = append(strike.Loot, api.InventoryUpdate{
+			InventoryID: inventory.ID,
+			ItemID:      drop.ItemID,
+			Quantity:    drop.Quantity,
+		})
+	}
+
+	return &TickUpdate{Strike: strike}
+}
+
+// rollLoot rolls each entry of a loot table on its own, so a creature can
+// drop several items at once or nothing at all.
+func (cfg *WorldConfig) rollLoot(drops []database.CreatureDrop) []database.CreatureDrop {
+	var loot []database.CreatureDrop
+	for _, drop := range drops {
+		if cfg.Seed.Intn(100) < int(drop.DropChance) {
+			loot = append(loot, drop)
+		}
+	}
+	return loot
+}
+
+// settleStrikes locks each creature under attack and lands the strikes on
+// it in the order they came in, taking the damage off in the same
+// transaction. Whoever strikes a creature that is already dead is idled.
+func (cfg *WorldConfig) settleStrikes(ctx context.Context, w *tickWrites) error {
+	bySpawn := make(map[int32][]characterStrike)
+	for _, strike := range w.strikes {
+		bySpawn[strike.claim.SpawnID] = append(bySpawn[strike.claim.SpawnID], strike)
+	}
+
+	// Spawns are locked in id order so concurrent ticks can't deadlock
+	spawnIDs := make([]int32, 0, len(bySpawn))
+	for spawnID := range bySpawn {
+		spawnIDs = append(spawnIDs, spawnID)
+	}
+	slices.Sort(spawnIDs)
+
+	for _, spawnID := range spawnIDs {
+		spawn, err := cfg.DB.LockCreatureSpawn(ctx, spawnID)
+		if err != nil {
+			return err
+		}
+
+		hp := spawn.Hp
+		var respawnTicks int32
+		for _, strike := range bySpawn[spawnID] {
+			claim := strike.claim
+			dealt := min(claim.Damage, hp)
+			if dealt <= 0 {
+				message := fmt.Sprintf("%s is already dead. Character %s is now idle", claim.CreatureName, strike.character.Name)
+				w.idles = append(w.idles, characterIdle{
+					character: strike.character,
+					idle:      IdleUpdate{Message: message, Severity: "warning"},
+				})
+				continue
+			}
+			hp -= dealt
+			respawnTicks = claim.RespawnTicks
+
+			if hp > 0 {
+				w.damage = append(w.damage, api.CharacterDamageUpdate{
+					CharacterID: strike.character.ID,
+					Damage:      claim.DamageTaken,
+				})
+				continue
+			}
+			w.inventory = append(w.inventory, claim.Loot...)
+			w.progress = append(w.progress, claim.Progress)
+		}
+
+		total := spawn.Hp - hp
+		if total == 0 {
+			continue
+		}
+
+		spawn, err = cfg.DB.DamageCreatureSpawn(ctx, database.DamageCreatureSpawnParams{
+			Damage:       total,
+			RespawnTicks: respawnTicks,
+			ID:           spawnID,
+		})
+		if err != nil {
+			return fmt.Errorf("dealing %d damage to spawn %d: %w", total, spawnID, err)
+		}
+		if spawn.Hp == 0 {
+			err := cfg.idleDefeatedCreature(ctx, spawn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cfg *WorldConfig) idleDefeatedCreature(ctx context.Context, spawn database.CreatureSpawn) error {
+	creature, err := cfg.GetCreatureById(ctx, spawn.CreatureID)
+	if err != nil {
+		return err
+	}
+
+	characters, err := cfg.DB.GetCharactersByCreatureTarget(ctx, pgtype.Int4{Int32: spawn.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	for _, char := range characters {
+		err := cfg.ApiConfig.SetCharacterToIdle(ctx, char.ID)
+		if err != nil {
+			return err
+		}
+		message := fmt.Sprintf("%s at (%d, %d) was defeated. Character %s is now idle",
+			creature.Name, spawn.PositionX, spawn.PositionY, char.Name)
+		err = cfg.ApiConfig.NotifyCharacterOwner(ctx, char, message, "info")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package world
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/api"
+	"github.com/trbute/idler/server/internal/database"
+)
+
+// IdleUpdate stops a character's action once the tick commits and tells its
+// owner why. An empty message idles the character quietly.
+type IdleUpdate struct {
+	Message  string
+	Severity string
+}
+
+func idleUpdate(message string, severity string) *TickUpdate {
+	return &TickUpdate{Idle: &IdleUpdate{Message: message, Severity: severity}}
+}
+
+type characterIdle struct {
+	character database.Character
+	idle      IdleUpdate
+}
+
+// tickWrites is everything a tick's actions want written, gathered so it
+// can all be committed together.
+type tickWrites struct {
+	characters      map[pgtype.UUID]database.Character
+	inventoryOwners map[pgtype.UUID]pgtype.UUID
+
+	arrivals  []database.Character
+	idles     []characterIdle
+	harvests  []characterHarvest
+	strikes   []characterStrike
+	crafts    []characterCraft
+	inventory []api.InventoryUpdate
+	progress  []api.CharacterProgressUpdate
+	toolWear  []api.ToolWearUpdate
+	damage    []api.CharacterDamageUpdate
+}
+
+func newTickWrites(activeChars []database.Character) *tickWrites {
+	characters := make(map[pgtype.UUID]database.Character, len(activeChars))
+	for _, char := range activeChars {
+		characters[char.ID] = char
+	}
+	return &tickWrites{
+		characters:      characters,
+		inventoryOwners: make(map[pgtype.UUID]pgtype.UUID),
+	}
+}
+
+func (w *tickWrites) add(update TickUpdate) {
+	char := w.characters[update.characterID]
+
+	w.inventory = append(w.inventory, update.InventoryUpdates...)
+	for _, inventoryUpdate := range update.InventoryUpdates {
+		w.inventoryOwners[inventoryUpdate.InventoryID] = update.characterID
+	}
+	if update.ProgressUpdate != nil {
+		w.progress = append(w.progress, *update.ProgressUpdate)
+	}
+	if update.ToolWear != nil {
+		w.toolWear = append(w.toolWear, *update.ToolWear)
+	}
+	if update.Damage != nil {
+		w.damage = append(w.damage, *update.Damage)
+	}
+	if update.Idle != nil {
+		w.idles = append(w.idles, characterIdle{character: char, idle: *update.Idle})
+	}
+	if update.Harvest != nil {
+		w.harvests = append(w.harvests, characterHarvest{character: char, claim: *update.Harvest})
+		w.inventoryOwners[update.Harvest.InventoryID] = update.characterID
+	}
+	if update.Strike != nil {
+		w.strikes = append(w.strikes, characterStrike{character: char, claim: *update.Strike})
+		for _, loot := range update.Strike.Loot {
+			w.inventoryOwners[loot.InventoryID] = update.characterID
+		}
+	}
+	if update.Craft != nil {
+		w.crafts = append(w.crafts, characterCraft{character: char, claim: *update.Craft})
+		w.inventoryOwners[update.Craft.InventoryID] = update.characterID
+	}
+	if update.Arrived {
+		w.arrivals = append(w.arrivals, char)
+	}
+}
+
+// tickResult is what a committed tick did, for telling the owners.
+type tickResult struct {
+	// applied are the inventory updates that went through
+	applied []api.InventoryUpdate
+	// progress is what was written once harvests and strikes were settled
+	progress      []api.CharacterProgressUpdate
+	notifications []api.Notification
+	// changes are the characters that went idle or had their inventory
+	// changed some way the deltas don't show
+	changes []api.CharacterChange
+}
+
+// commitTick writes everything from the tick in one transaction, so a
+// failure part way through can't leave inventories, weights and characters
+// disagreeing. Nothing is sent to the owners yet, that's left to the caller.
+// The transaction is fenced by the shards' epochs, so an instance that lost
+// a shard part way through the tick can't commit over its new holder.
+func (cfg *WorldConfig) commitTick(ctx context.Context, w *tickWrites, shards tickShards) (tickResult, error) {
+	var result tickResult
+	notifications, changes, err := cfg.RunInTxQuietly(ctx, func(txApi *api.ApiConfig) error {
+		txCfg := *cfg
+		txCfg.DB = txApi.DB
+		txCfg.ApiConfig = txApi
+
+		err := txCfg.fenceShards(ctx, shards)
+		if err != nil {
+			return err
+		}
+		result.applied, result.progress, err = txCfg.writeTick(ctx, w)
+		return err
+	})
+	if err != nil {
+		return tickResult{}, err
+	}
+	result.notifications = notifications
+	result.changes = changes
+	return result, nil
+}
+
+// errShardFenced means another instance has committed a shard at a later
+// epoch than the one this instance holds it at.
+var errShardFenced = errors.New("tick shard was taken over by another instance")
+
+// fenceShards records the epoch each shard is committed at, refusing if a
+// later one has already committed. The row stays locked until the
+// transaction ends, so commits to a shard can't interleave.
+func (cfg *WorldConfig) fenceShards(ctx context.Context, shards tickShards) error {
+	for shard := range shards.held {
+		fenced, err := cfg.DB.FenceTickShard(ctx, database.FenceTickShardParams{
+			Shard: int32(shard),
+			Epoch: shards.epochs[shard],
+		})
+		if err != nil {
+			return fmt.Errorf("fencing tick shard %d: %w", shard, err)
+		}
+		if fenced == 0 {
+			return fmt.Errorf("shard %d at epoch %d: %w", shard, shards.epochs[shard], errShardFenced)
+		}
+	}
+	return nil
+}
+
+func (cfg *WorldConfig) writeTick(ctx context.Context, tick *tickWrites) ([]api.InventoryUpdate, []api.CharacterProgressUpdate, error) {
+	// Settling adds to the writes, so work on a copy that a retried
+	// transaction can start over from
+	w := *tick
+	w.idles = slices.Clone(tick.idles)
+	w.inventory = slices.Clone(tick.inventory)
+	w.progress = slices.Clone(tick.progress)
+	w.toolWear = slices.Clone(tick.toolWear)
+	w.damage = slices.Clone(tick.damage)
+
+	err := cfg.settleStrikes(ctx, &w)
+	if err != nil {
+		return nil, nil, fmt.Errorf("damaging creature spawns: %w", err)
+	}
+
+	grants, err := cfg.settleHarvests(ctx, &w)
+	if err != nil {
+		return nil, nil, fmt.Errorf("harvesting resource node spawns: %w", err)
+	}
+
+	err = cfg.settleCrafts(ctx, &w)
+	if err != nil {
+		return nil, nil, fmt.Errorf("checking crafting ingredients: %w", err)
+	}
+
+	for _, char := range w.arrivals {
+		arrived, err := cfg.ApiConfig.CompleteCharacterTravel(ctx, char.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("completing travel for %s: %w", char.Name, err)
+		}
+		message := fmt.Sprintf("Character %s arrived at (%d, %d)",
+			arrived.Name, arrived.PositionX, arrived.PositionY)
+		err = cfg.ApiConfig.NotifyCharacterOwner(ctx, arrived, message, "info")
+		if err != nil {
+			return nil, nil, fmt.Errorf("notifying %s: %w", arrived.Name, err)
+		}
+	}
+
+	for _, idle := range w.idles {
+		err := cfg.ApiConfig.SetCharacterToIdle(ctx, idle.character.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("idling %s: %w", idle.character.Name, err)
+		}
+		if idle.idle.Message != "" {
+			err = cfg.ApiConfig.NotifyCharacterOwner(ctx, idle.character, idle.idle.Message, idle.idle.Severity)
+			if err != nil {
+				return nil, nil, fmt.Errorf("notifying %s: %w", idle.character.Name, err)
+			}
+		}
+	}
+
+	applied, err := cfg.BatchAddItemsToInventory(ctx, w.inventory)
+	if err != nil {
+		return nil, nil, fmt.Errorf("updating inventories: %w", err)
+	}
+
+	err = cfg.depleteSpawns(ctx, &w, grants, applied)
+	if err != nil {
+		return nil, nil, fmt.Errorf("harvesting resource node spawns: %w", err)
+	}
+
+	err = cfg.ApiConfig.BatchUpdateCharacterProgress(ctx, w.progress)
+	if err != nil {
+		return nil, nil, fmt.Errorf("updating character progress: %w", err)
+	}
+
+	err = cfg.ApiConfig.BatchWearTools(ctx, w.toolWear)
+	if err != nil {
+		return nil, nil, fmt.Errorf("wearing tools: %w", err)
+	}
+
+	err = cfg.ApiConfig.BatchDamageCharacters(ctx, w.damage)
+	if err != nil {
+		return nil, nil, fmt.Errorf("damaging characters: %w", err)
+	}
+
+	return applied, w.progress, nil
+}
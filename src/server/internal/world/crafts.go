@@ -0,0 +1,89 @@
+package world
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/api"
+	"github.com/trbute/idler/server/internal/database"
+)
+
+// CraftClaim turns ingredients into a recipe's output. The ingredients are
+// only checked against the inventory when the tick commits, with their rows
+// locked, since they may have been dropped, stashed or traded since the
+// handler looked.
+type CraftClaim struct {
+	InventoryID pgtype.UUID
+	RecipeName  string
+	// Ingredients are removals, so their quantities are negative
+	Ingredients []api.InventoryUpdate
+	Output      api.InventoryUpdate
+	// Progress is only written if the craft goes ahead
+	Progress *api.CharacterProgressUpdate
+}
+
+type characterCraft struct {
+	character database.Character
+	claim     CraftClaim
+}
+
+// settleCrafts locks each crafting inventory and its ingredients and only
+// lets a craft through if every ingredient is still there. A craft that
+// comes up short is dropped whole, output included, and the character is
+// idled, so one character's missing ingredient never fails the tick.
+func (cfg *WorldConfig) settleCrafts(ctx context.Context, w *tickWrites) error {
+	// Inventories are locked in id order, like moves and trades, so they
+	// can't deadlock with each other
+	crafts := slices.Clone(w.crafts)
+	slices.SortFunc(crafts, func(a, b characterCraft) int {
+		return bytes.Compare(a.claim.InventoryID.Bytes[:], b.claim.InventoryID.Bytes[:])
+	})
+
+	for _, craft := range crafts {
+		claim := craft.claim
+		_, err := cfg.DB.LockInventory(ctx, claim.InventoryID)
+		if err != nil {
+			return err
+		}
+
+		short := false
+		for _, ingredient := range claim.Ingredients {
+			rows, err := cfg.DB.LockInventoryItems(ctx, database.LockInventoryItemsParams{
+				InventoryID: claim.InventoryID,
+				ItemID:      ingredient.ItemID,
+			})
+			if err != nil {
+				return err
+			}
+			var held int32
+			for _, row := range rows {
+				held += row.Quantity
+			}
+			if held < -ingredient.Quantity {
+				short = true
+				break
+			}
+		}
+
+		if short {
+			message := fmt.Sprintf("Character %s ran out of ingredients for %s and is now idle",
+				craft.character.Name, claim.RecipeName)
+			w.idles = append(w.idles, characterIdle{
+				character: craft.character,
+				idle:      IdleUpdate{Message: message, Severity: "warning"},
+			})
+			continue
+		}
+
+		w.inventory = append(w.inventory, claim.Ingredients...)
+		w.inventory = append(w.inventory, claim.Output)
+		if claim.Progress != nil {
+			w.progress = append(w.progress, *claim.Progress)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package world
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+)
+
+const tickInstancesKey = "tick:instances"
+
+// Only the instance whose value is in the key may extend or drop a lease.
+// Taking a lease bumps the shard's epoch, which fences off commits from
+// whoever held it before.
+var (
+	acquireLeaseScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0`)
+	renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// tickShards is the part of the world an instance ticks. The holder of
+// shard 0 leads, running the world wide steps as well as its characters.
+type tickShards struct {
+	count int
+	held  map[int]bool
+	// epochs are the fencing tokens each held shard was acquired with
+	epochs map[int]int64
+}
+
+func (s tickShards) leader() bool {
+	return s.held[0]
+}
+
+func (s tickShards) empty() bool {
+	return len(s.held) == 0
+}
+
+// since returns the shards held now that weren't held in prev.
+func (s tickShards) since(prev tickShards) tickShards {
+	held := make(map[int]bool, len(s.held))
+	epochs := make(map[int]int64, len(s.held))
+	for shard := range s.held {
+		if !prev.held[shard] {
+			held[shard] = true
+			epochs[shard] = s.epochs[shard]
+		}
+	}
+	return tickShards{count: s.count, held: held, epochs: epochs}
+}
+
+// owns reports whether a character is ticked by this instance.
+func (s tickShards) owns(characterID pgtype.UUID) bool {
+	return s.held[shardOf(characterID, s.count)]
+}
+
+// shardOf spreads characters across shards by a hash of their ID.
+func shardOf(characterID pgtype.UUID, count int) int {
+	if count <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write(characterID.Bytes[:])
+	return int(h.Sum32() % uint32(count))
+}
+
+// tickLease makes sure each shard is ticked by exactly one instance. Every
+// shard has a lease key in Redis that its holder renews each tick. When a
+// holder stops renewing, the lease runs out and another instance takes the
+// shard over. Instances heartbeat so shards can be spread evenly between
+// however many are running.
+//
+// A lease can run out while its holder is still mid tick, so each one comes
+// with an epoch that commits are checked against. See fenceShards.
+type tickLease struct {
+	redis      *redis.Client
+	instanceID string
+	count      int
+	ttl        time.Duration
+	held       map[int]bool
+	epochs     map[int]int64
+}
+
+func newTickLease(rdb *redis.Client, count int, ttl time.Duration) *tickLease {
+	if count < 1 {
+		count = 1
+	}
+	return &tickLease{
+		redis:      rdb,
+		instanceID: uuid.NewString(),
+		count:      count,
+		ttl:        ttl,
+		held:       make(map[int]bool),
+		epochs:     make(map[int]int64),
+	}
+}
+
+func leaseKey(shard int) string {
+	return fmt.Sprintf("tick:lease:%d", shard)
+}
+
+func epochKey(shard int) string {
+	return fmt.Sprintf("tick:epoch:%d", shard)
+}
+
+// refresh renews the shards this instance holds, gives up any beyond its
+// share and takes free shards up to its share.
+func (l *tickLease) refresh(ctx context.Context) tickShards {
+	target := l.count
+	instances, err := l.heartbeat(ctx)
+	if err != nil {
+		log.Printf("Error sending tick heartbeat: %v", err)
+	} else if instances > 1 {
+		target = (l.count + instances - 1) / instances
+	}
+
+	l.renew(ctx)
+
+	// Give up the highest shards first so leadership stays put
+	for shard := l.count - 1; shard >= 0 && len(l.held) > target; shard-- {
+		if !l.held[shard] {
+			continue
+		}
+		err := releaseLeaseScript.Run(ctx, l.redis, []string{leaseKey(shard)}, l.instanceID).Err()
+		if err != nil {
+			log.Printf("Error releasing tick shard %d: %v", shard, err)
+			continue
+		}
+		delete(l.held, shard)
+		delete(l.epochs, shard)
+		log.Printf("Released tick shard %d", shard)
+	}
+
+	for shard := 0; shard < l.count && len(l.held) < target; shard++ {
+		if l.held[shard] {
+			continue
+		}
+		keys := []string{leaseKey(shard), epochKey(shard)}
+		epoch, err := acquireLeaseScript.Run(ctx, l.redis, keys, l.instanceID, l.ttl.Milliseconds()).Int64()
+		if err != nil {
+			log.Printf("Error acquiring tick shard %d: %v", shard, err)
+			continue
+		}
+		if epoch > 0 {
+			l.held[shard] = true
+			l.epochs[shard] = epoch
+			log.Printf("Acquired tick shard %d of %d at epoch %d", shard, l.count, epoch)
+		}
+	}
+
+	return l.shards()
+}
+
+// renew extends every lease still held and forgets any that were lost. It
+// reports whether this instance still leads.
+func (l *tickLease) renew(ctx context.Context) bool {
+	for shard := range l.held {
+		renewed, err := renewLeaseScript.Run(ctx, l.redis, []string{leaseKey(shard)}, l.instanceID, l.ttl.Milliseconds()).Int()
+		if err != nil || renewed == 0 {
+			delete(l.held, shard)
+			delete(l.epochs, shard)
+			log.Printf("Lost tick shard %d", shard)
+		}
+	}
+	return l.held[0]
+}
+
+// holds renews the leases and reports whether every one of the given
+// shards is still held, at the epoch it was taken with.
+func (l *tickLease) holds(ctx context.Context, shards tickShards) bool {
+	l.renew(ctx)
+	for shard := range shards.held {
+		if !l.held[shard] || l.epochs[shard] != shards.epochs[shard] {
+			return false
+		}
+	}
+	return true
+}
+
+// heartbeat marks this instance alive and returns how many are.
+func (l *tickLease) heartbeat(ctx context.Context) (int, error) {
+	now := time.Now()
+	cutoff := now.Add(-l.ttl).UnixMilli()
+
+	pipe := l.redis.TxPipeline()
+	pipe.ZAdd(ctx, tickInstancesKey, redis.Z{Score: float64(now.UnixMilli()), Member: l.instanceID})
+	pipe.ZRemRangeByScore(ctx, tickInstancesKey, "-inf", "("+strconv.FormatInt(cutoff, 10))
+	count := pipe.ZCard(ctx, tickInstancesKey)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count.Val()), nil
+}
+
+func (l *tickLease) shards() tickShards {
+	held := make(map[int]bool, len(l.held))
+	epochs := make(map[int]int64, len(l.held))
+	for shard := range l.held {
+		held[shard] = true
+		epochs[shard] = l.epochs[shard]
+	}
+	return tickShards{count: l.count, held: held, epochs: epochs}
+}
@@ -0,0 +1,70 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestTickShardsOwnEachCharacterOnce(t *testing.T) {
+	const count = 4
+
+	var ids []pgtype.UUID
+	for range 200 {
+		ids = append(ids, pgtype.UUID{Bytes: uuid.New(), Valid: true})
+	}
+
+	split := []tickShards{
+		{count: count, held: map[int]bool{0: true, 2: true}},
+		{count: count, held: map[int]bool{1: true}},
+		{count: count, held: map[int]bool{3: true}},
+	}
+
+	used := make(map[int]bool)
+	for _, id := range ids {
+		owners := 0
+		for _, shards := range split {
+			if shards.owns(id) {
+				owners++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("character %s owned by %d instances, want 1", id.String(), owners)
+		}
+		used[shardOf(id, count)] = true
+	}
+	if len(used) != count {
+		t.Errorf("characters landed in %d shards, want %d", len(used), count)
+	}
+}
+
+func TestShardOfSingleShard(t *testing.T) {
+	id := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	for _, count := range []int{0, 1} {
+		if got := shardOf(id, count); got != 0 {
+			t.Errorf("shardOf(_, %d) = %d, want 0", count, got)
+		}
+	}
+}
+
+func TestTickShardsSince(t *testing.T) {
+	before := tickShards{count: 4, held: map[int]bool{1: true, 2: true}}
+	after := tickShards{
+		count:  4,
+		held:   map[int]bool{0: true, 1: true, 3: true},
+		epochs: map[int]int64{0: 7, 1: 2, 3: 5},
+	}
+
+	acquired := after.since(before)
+	if !acquired.leader() || !acquired.held[3] || len(acquired.held) != 2 {
+		t.Errorf("since() = %v, want shards 0 and 3", acquired.held)
+	}
+	if acquired.epochs[0] != 7 || acquired.epochs[3] != 5 {
+		t.Errorf("since() epochs = %v, want the epochs the shards were taken at", acquired.epochs)
+	}
+
+	if fresh := after.since(tickShards{}); len(fresh.held) != 3 {
+		t.Errorf("since(nothing) = %v, want every held shard", fresh.held)
+	}
+}
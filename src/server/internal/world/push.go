@@ -0,0 +1,112 @@
+package world
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/api"
+	"github.com/trbute/idler/server/internal/database"
+)
+
+type itemDelta struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+	// Weight is per item, so clients can keep inventory weight current
+	Weight int32 `json:"weight"`
+}
+
+type inventoryDelta struct {
+	CharacterName string      `json:"character_name"`
+	Items         []itemDelta `json:"items"`
+}
+
+type actionProgress struct {
+	CharacterName string `json:"character_name"`
+	ActionName    string `json:"action_name"`
+	Progress      int32  `json:"progress"`
+	Limit         int32  `json:"limit,omitempty"`
+	Ticks         int32  `json:"ticks"`
+}
+
+// pushTickUpdates tells each user what changed for their characters this
+// tick. Everything is coalesced into at most one inventory_delta and one
+// action_progress message per user, whatever the number of characters.
+func (cfg *WorldConfig) pushTickUpdates(
+	ctx context.Context,
+	characters map[pgtype.UUID]database.Character,
+	inventoryOwners map[pgtype.UUID]pgtype.UUID,
+	applied []api.InventoryUpdate,
+	progress []api.CharacterProgressUpdate,
+) {
+	deltas := make(map[uuid.UUID]map[string]*inventoryDelta)
+	for _, update := range applied {
+		char, ok := characters[inventoryOwners[update.InventoryID]]
+		if !ok || update.Quantity == 0 {
+			continue
+		}
+		item, err := cfg.GetItemById(ctx, update.ItemID)
+		if err != nil {
+			continue
+		}
+
+		userID := uuid.UUID(char.UserID.Bytes)
+		if deltas[userID] == nil {
+			deltas[userID] = make(map[string]*inventoryDelta)
+		}
+		delta, ok := deltas[userID][char.Name]
+		if !ok {
+			delta = &inventoryDelta{CharacterName: char.Name}
+			deltas[userID][char.Name] = delta
+		}
+		delta.Items = append(delta.Items, itemDelta{
+			Name:     item.Name,
+			Quantity: update.Quantity,
+			Weight:   item.Weight,
+		})
+	}
+
+	progressByUser := make(map[uuid.UUID][]actionProgress)
+	for _, update := range progress {
+		char, ok := characters[update.CharacterID]
+		if !ok {
+			continue
+		}
+		action, err := cfg.GetActionById(ctx, char.ActionID)
+		if err != nil {
+			continue
+		}
+
+		userID := uuid.UUID(char.UserID.Bytes)
+		progressByUser[userID] = append(progressByUser[userID], actionProgress{
+			CharacterName: char.Name,
+			ActionName:    action.Name,
+			Progress:      update.Progress,
+			Limit:         char.ActionAmountLimit.Int32,
+			Ticks:         update.Ticks,
+		})
+	}
+
+	for userID, byCharacter := range deltas {
+		characterDeltas := make([]inventoryDelta, 0, len(byCharacter))
+		for _, delta := range byCharacter {
+			characterDeltas = append(characterDeltas, *delta)
+		}
+		sort.Slice(characterDeltas, func(i, j int) bool {
+			return characterDeltas[i].CharacterName < characterDeltas[j].CharacterName
+		})
+		cfg.ApiConfig.Hub.SendToUser(userID, "inventory_delta", map[string]interface{}{
+			"characters": characterDeltas,
+		})
+	}
+
+	for userID, characterProgress := range progressByUser {
+		sort.Slice(characterProgress, func(i, j int) bool {
+			return characterProgress[i].CharacterName < characterProgress[j].CharacterName
+		})
+		cfg.ApiConfig.Hub.SendToUser(userID, "action_progress", map[string]interface{}{
+			"characters": characterProgress,
+		})
+	}
+}
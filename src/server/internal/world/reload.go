@@ -0,0 +1,187 @@
+package world
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/trbute/idler/server/data"
+)
+
+// contentReload asks the tick loop to reload world content between ticks
+// and waits for the outcome.
+type contentReload struct {
+	done chan contentReloadResult
+}
+
+type contentReloadResult struct {
+	diff *data.Diff
+	err  error
+}
+
+// requestReload queues a reload for the tick loop and blocks until it has
+// run, so the reload never lands halfway through a tick.
+func (cfg *WorldConfig) requestReload(ctx context.Context) (*data.Diff, error) {
+	req := contentReload{done: make(chan contentReloadResult, 1)}
+	select {
+	case cfg.reloads <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case result := <-req.done:
+		return result.diff, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// reloadContent syncs the database with the content files, then drops the
+// cached content so the next tick reads the new data on every instance.
+// Characters the sync idled are dropped from the cache too and their owners
+// told.
+func (cfg *WorldConfig) reloadContent(ctx context.Context) (*data.Diff, error) {
+	diff, err := cfg.Content.ReloadContent(ctx, cfg.ContentDir)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.InvalidateContentCache(ctx)
+	if err != nil {
+		return diff, err
+	}
+
+	err = cfg.NotifyContentIdled(ctx, diff.Idled)
+	if err != nil {
+		return diff, err
+	}
+	return diff, nil
+}
+
+// requireAdminToken only lets requests through that carry the admin token
+// as a bearer token, so reaching the stats address isn't enough to reload.
+func (cfg *WorldConfig) requireAdminToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !hasBearerToken(r, cfg.AdminToken) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func hasBearerToken(r *http.Request, token string) bool {
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// handleReload reloads world content on request and replies with what
+// changed, or every problem found in the content files.
+func (cfg *WorldConfig) handleReload(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	diff, err := cfg.requestReload(r.Context())
+	if err != nil {
+		log.Printf("Error reloading world content: %v", err)
+		var contentErrs data.ContentErrors
+		if errors.As(err, &contentErrs) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write([]byte(err.Error() + "\n"))
+		return
+	}
+
+	var report bytes.Buffer
+	diff.WriteReport(&report)
+	log.Printf("World content reloaded from %s\n%s", cfg.ContentDir, report.String())
+	w.Write(report.Bytes())
+}
+
+// fileStamp is what a content file looked like when last checked.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// contentModTimes fingerprints every file in dir by its size and
+// modification time.
+func contentModTimes(dir string) (map[string]fileStamp, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]fileStamp, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		files[entry.Name()] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return files, nil
+}
+
+func sameModTimes(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, stamp := range a {
+		other, ok := b[name]
+		if !ok || other.size != stamp.size || !other.modTime.Equal(stamp.modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// watchContent polls the content directory and reloads whenever a file
+// changes. It's meant for development, where content is edited in place.
+func (cfg *WorldConfig) watchContent(interval time.Duration) {
+	last, err := contentModTimes(cfg.ContentDir)
+	if err != nil {
+		log.Printf("Unable to watch world content: %v", err)
+		return
+	}
+	log.Printf("Watching %s for world content changes", filepath.Clean(cfg.ContentDir))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		current, err := contentModTimes(cfg.ContentDir)
+		if err != nil {
+			log.Printf("Error watching world content: %v", err)
+			continue
+		}
+		if sameModTimes(last, current) {
+			continue
+		}
+		last = current
+
+		diff, err := cfg.requestReload(context.Background())
+		if err != nil {
+			log.Printf("Error reloading world content: %v", err)
+			continue
+		}
+		var report bytes.Buffer
+		diff.WriteReport(&report)
+		log.Printf("World content reloaded from %s\n%s", cfg.ContentDir, report.String())
+	}
+}
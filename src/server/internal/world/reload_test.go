@@ -0,0 +1,75 @@
+package world
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestContentModTimes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "items.json")
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := contentModTimes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := contentModTimes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameModTimes(before, again) {
+		t.Error("unchanged directory reported as changed")
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	edited, err := contentModTimes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sameModTimes(before, edited) {
+		t.Error("edited file not reported as changed")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "grid.json"), []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	added, err := contentModTimes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sameModTimes(edited, added) {
+		t.Error("new file not reported as changed")
+	}
+}
+
+func TestHasBearerToken(t *testing.T) {
+	cases := []struct {
+		header string
+		token  string
+		want   bool
+	}{
+		{"Bearer secret", "secret", true},
+		{"Bearer wrong", "secret", false},
+		{"secret", "secret", false},
+		{"", "secret", false},
+		{"Bearer ", "", false},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest("POST", "/internal/reload", nil)
+		if c.header != "" {
+			r.Header.Set("Authorization", c.header)
+		}
+		if got := hasBearerToken(r, c.token); got != c.want {
+			t.Errorf("hasBearerToken(%q, %q) = %v, want %v", c.header, c.token, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package world
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/trbute/idler/server/api"
+	"github.com/trbute/idler/server/internal/database"
+)
+
+// HarvestClaim asks for a drop from a resource node spawn. Claims are
+// settled when the tick commits, with the spawn locked, so characters
+// sharing a spawn can't take more than it holds, whichever instance ticks
+// them.
+type HarvestClaim struct {
+	SpawnID      int32
+	NodeName     string
+	NodeTier     int32
+	RespawnTicks int32
+	InventoryID  pgtype.UUID
+	ItemID       int32
+	Quantity     int32
+	// Progress is the character's progress before this drop
+	Progress int32
+	ActionID int32
+	// ToolWear is only applied if anything was harvested
+	ToolWear *api.ToolWearUpdate
+}
+
+type characterHarvest struct {
+	character database.Character
+	claim     HarvestClaim
+}
+
+// harvestGrant is what a harvest claim was granted from its spawn, before
+// the inventory has had its say.
+type harvestGrant struct {
+	characterHarvest
+	granted int32
+	// limited is false for spawns that never run out
+	limited bool
+}
+
+// settleHarvests locks each claimed spawn and grants what it has left in
+// the order the claims came in. Whoever gets nothing is idled. The spawn is
+// only drawn down by depleteSpawns once it is known how much fit.
+func (cfg *WorldConfig) settleHarvests(ctx context.Context, w *tickWrites) ([]harvestGrant, error) {
+	bySpawn := make(map[int32][]characterHarvest)
+	for _, harvest := range w.harvests {
+		bySpawn[harvest.claim.SpawnID] = append(bySpawn[harvest.claim.SpawnID], harvest)
+	}
+
+	// Spawns are locked in id order so concurrent ticks can't deadlock
+	spawnIDs := make([]int32, 0, len(bySpawn))
+	for spawnID := range bySpawn {
+		spawnIDs = append(spawnIDs, spawnID)
+	}
+	slices.Sort(spawnIDs)
+
+	var grants []harvestGrant
+	for _, spawnID := range spawnIDs {
+		spawn, err := cfg.DB.LockResourceNodeSpawn(ctx, spawnID)
+		if err != nil {
+			return nil, err
+		}
+
+		var taken int32
+		for _, harvest := range bySpawn[spawnID] {
+			claim := harvest.claim
+			granted := claim.Quantity
+			if spawn.Remaining.Valid {
+				granted = min(granted, spawn.Remaining.Int32-taken)
+			}
+			if granted <= 0 {
+				message := fmt.Sprintf("%s is depleted. Character %s is now idle", claim.NodeName, harvest.character.Name)
+				w.idles = append(w.idles, characterIdle{
+					character: harvest.character,
+					idle:      IdleUpdate{Message: message, Severity: "warning"},
+				})
+				continue
+			}
+			taken += granted
+
+			w.inventory = append(w.inventory, api.InventoryUpdate{
+				InventoryID: claim.InventoryID,
+				ItemID:      claim.ItemID,
+				Quantity:    granted,
+			})
+			grants = append(grants, harvestGrant{
+				characterHarvest: harvest,
+				granted:          granted,
+				limited:          spawn.Remaining.Valid,
+			})
+		}
+	}
+
+	return grants, nil
+}
+
+// depleteSpawns credits each harvest with what actually went into the
+// inventory, which a full inventory may have cut short, and takes only that
+// off the spawn. Progress, experience and tool wear follow the same amount.
+func (cfg *WorldConfig) depleteSpawns(ctx context.Context, w *tickWrites, grants []harvestGrant, applied []api.InventoryUpdate) error {
+	added := make(map[inventoryItem]int32)
+	for _, update := range applied {
+		if update.Quantity > 0 {
+			added[inventoryItem{update.InventoryID, update.ItemID}] += update.Quantity
+		}
+	}
+
+	taken := make(map[int32]int32)
+	respawnTicks := make(map[int32]int32)
+	for _, grant := range grants {
+		claim := grant.claim
+		key := inventoryItem{claim.InventoryID, claim.ItemID}
+		gathered := min(grant.granted, added[key])
+		if gathered <= 0 {
+			continue
+		}
+		added[key] -= gathered
+		if grant.limited {
+			taken[claim.SpawnID] += gathered
+			respawnTicks[claim.SpawnID] = claim.RespawnTicks
+		}
+
+		w.progress = append(w.progress, api.CharacterProgressUpdate{
+			CharacterID: grant.character.ID,
+			Progress:    claim.Progress + gathered,
+			ActionID:    claim.ActionID,
+			Experience:  api.ExperienceForDrop(claim.NodeTier, gathered),
+		})
+		if claim.ToolWear != nil {
+			w.toolWear = append(w.toolWear, *claim.ToolWear)
+		}
+	}
+
+	spawnIDs := make([]int32, 0, len(taken))
+	for spawnID := range taken {
+		spawnIDs = append(spawnIDs, spawnID)
+	}
+	slices.Sort(spawnIDs)
+
+	for _, spawnID := range spawnIDs {
+		spawn, err := cfg.DB.HarvestResourceNodeSpawn(ctx, database.HarvestResourceNodeSpawnParams{
+			Quantity:     taken[spawnID],
+			RespawnTicks: respawnTicks[spawnID],
+			ID:           spawnID,
+		})
+		if err != nil {
+			return fmt.Errorf("harvesting %d from spawn %d: %w", taken[spawnID], spawnID, err)
+		}
+		if spawn.Remaining.Int32 == 0 {
+			err := cfg.idleDepletedSpawn(ctx, spawn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cfg *WorldConfig) idleDepletedSpawn(ctx context.Context, spawn database.ResourceNodeSpawn) error {
+	node, err := cfg.GetResourceNodeById(ctx, spawn.NodeID)
+	if err != nil {
+		return err
+	}
+
+	characters, err := cfg.DB.GetCharactersByActionTarget(ctx, pgtype.Int4{Int32: spawn.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	for _, char := range characters {
+		err := cfg.ApiConfig.SetCharacterToIdle(ctx, char.ID)
+		if err != nil {
+			return err
+		}
+		message := fmt.Sprintf("%s at (%d, %d) is depleted. Character %s is now idle",
+			node.Name, spawn.PositionX, spawn.PositionY, char.Name)
+		err = cfg.ApiConfig.NotifyCharacterOwner(ctx, char, message, "warning")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
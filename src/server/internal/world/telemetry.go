@@ -0,0 +1,199 @@
+package world
+
+import (
+	"encoding/json"
+	"log"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tickSummaryInterval is how often a summary of recent ticks is logged.
+const tickSummaryInterval = time.Minute
+
+// TickStats is what one tick cost. Times are in milliseconds.
+type TickStats struct {
+	StartedAt        time.Time `json:"started_at"`
+	Leader           bool      `json:"leader"`
+	ActiveCharacters int       `json:"active_characters"`
+	// WorldMs covers respawns, regen and expiry, which only the leader runs
+	WorldMs float64 `json:"world_ms"`
+	// HandlerMs is how long every character's action took to run
+	HandlerMs float64 `json:"handler_ms"`
+	// DBBatchMs is how long the tick's writes took to commit
+	DBBatchMs  float64                 `json:"db_batch_ms"`
+	PushMs     float64                 `json:"push_ms"`
+	DurationMs float64                 `json:"duration_ms"`
+	Overrun    bool                    `json:"overrun"`
+	Handlers   map[string]HandlerStats `json:"handlers"`
+}
+
+// HandlerStats is the time spent in one action's handler.
+type HandlerStats struct {
+	Calls  int     `json:"calls"`
+	TimeMs float64 `json:"time_ms"`
+}
+
+// TickTelemetrySnapshot is served on the stats endpoint.
+type TickTelemetrySnapshot struct {
+	TickRateMs    float64                 `json:"tick_rate_ms"`
+	Ticks         int64                   `json:"ticks"`
+	Overruns      int64                   `json:"overruns"`
+	SlowTicks     int64                   `json:"slow_ticks"`
+	MaxDurationMs float64                 `json:"max_duration_ms"`
+	Last          *TickStats              `json:"last"`
+	Handlers      map[string]HandlerStats `json:"handlers"`
+}
+
+// tickTelemetry keeps measurements of the ticks this instance has run.
+// Handlers report their time concurrently while a tick runs.
+type tickTelemetry struct {
+	mu       sync.Mutex
+	tickRate time.Duration
+	// warnShare is the share of the tick rate a tick may take before a
+	// warning is logged
+	warnShare float64
+
+	ticks       int64
+	overruns    int64
+	slowTicks   int64
+	maxDuration time.Duration
+	last        *TickStats
+	handlers    map[string]HandlerStats
+	current     map[string]HandlerStats
+
+	lastSummary   time.Time
+	summaryTicks  int64
+	summaryTotal  time.Duration
+	summaryMaxDur time.Duration
+}
+
+func newTickTelemetry(tickRate time.Duration, warnShare float64) *tickTelemetry {
+	return &tickTelemetry{
+		tickRate:    tickRate,
+		warnShare:   warnShare,
+		handlers:    make(map[string]HandlerStats),
+		current:     make(map[string]HandlerStats),
+		lastSummary: time.Now(),
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// handlerTime adds one handler call to the running tick.
+func (t *tickTelemetry) handlerTime(action string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := t.current[action]
+	stats.Calls++
+	stats.TimeMs += milliseconds(d)
+	t.current[action] = stats
+}
+
+// record finishes a tick, logging it and warning when it ran long.
+func (t *tickTelemetry) record(stats TickStats, duration time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats.DurationMs = milliseconds(duration)
+	stats.Overrun = duration > t.tickRate
+	stats.Handlers = t.current
+	t.current = make(map[string]HandlerStats)
+
+	t.ticks++
+	t.last = &stats
+	if stats.Overrun {
+		t.overruns++
+	}
+	if duration > t.maxDuration {
+		t.maxDuration = duration
+	}
+	for action, handler := range stats.Handlers {
+		total := t.handlers[action]
+		total.Calls += handler.Calls
+		total.TimeMs += handler.TimeMs
+		t.handlers[action] = total
+	}
+
+	attrs := []any{
+		"active_characters", stats.ActiveCharacters,
+		"leader", stats.Leader,
+		"world_ms", stats.WorldMs,
+		"handler_ms", stats.HandlerMs,
+		"db_batch_ms", stats.DBBatchMs,
+		"push_ms", stats.PushMs,
+		"duration_ms", stats.DurationMs,
+		"tick_rate_ms", milliseconds(t.tickRate),
+		"overrun", stats.Overrun,
+	}
+
+	if t.warnShare > 0 && float64(duration) > t.warnShare*float64(t.tickRate) {
+		t.slowTicks++
+		slog.Warn("tick ran long", attrs...)
+	} else {
+		slog.Debug("tick", attrs...)
+	}
+
+	t.summaryTicks++
+	t.summaryTotal += duration
+	if duration > t.summaryMaxDur {
+		t.summaryMaxDur = duration
+	}
+	if time.Since(t.lastSummary) >= tickSummaryInterval {
+		slog.Info("tick summary",
+			"ticks", t.summaryTicks,
+			"avg_duration_ms", milliseconds(t.summaryTotal/time.Duration(t.summaryTicks)),
+			"max_duration_ms", milliseconds(t.summaryMaxDur),
+			"overruns", t.overruns,
+			"slow_ticks", t.slowTicks,
+			"active_characters", stats.ActiveCharacters,
+		)
+		t.lastSummary = time.Now()
+		t.summaryTicks = 0
+		t.summaryTotal = 0
+		t.summaryMaxDur = 0
+	}
+}
+
+func (t *tickTelemetry) snapshot() TickTelemetrySnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	handlers := make(map[string]HandlerStats, len(t.handlers))
+	for action, stats := range t.handlers {
+		handlers[action] = stats
+	}
+
+	return TickTelemetrySnapshot{
+		TickRateMs:    milliseconds(t.tickRate),
+		Ticks:         t.ticks,
+		Overruns:      t.overruns,
+		SlowTicks:     t.slowTicks,
+		MaxDurationMs: milliseconds(t.maxDuration),
+		Last:          t.last,
+		Handlers:      handlers,
+	}
+}
+
+// serveStats serves tick telemetry and content reloads on an address meant
+// to stay inside the deployment, apart from the public API.
+func (cfg *WorldConfig) serveStats() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /internal/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cfg.telemetry.snapshot())
+	})
+	if cfg.Content != nil && cfg.AdminToken != "" {
+		mux.HandleFunc("POST /internal/reload", cfg.requireAdminToken(cfg.handleReload))
+	}
+
+	log.Printf("Serving tick stats on %s", cfg.StatsAddr)
+	err := http.ListenAndServe(cfg.StatsAddr, mux)
+	if err != nil {
+		log.Printf("Stats endpoint stopped: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package world
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTickTelemetryRecord(t *testing.T) {
+	telemetry := newTickTelemetry(100*time.Millisecond, 0.5)
+
+	telemetry.handlerTime("MINING", 2*time.Millisecond)
+	telemetry.handlerTime("MINING", 3*time.Millisecond)
+	telemetry.record(TickStats{ActiveCharacters: 2}, 40*time.Millisecond)
+	telemetry.record(TickStats{}, 60*time.Millisecond)
+	telemetry.record(TickStats{}, 150*time.Millisecond)
+
+	snapshot := telemetry.snapshot()
+	if snapshot.Ticks != 3 {
+		t.Errorf("Ticks = %d, want 3", snapshot.Ticks)
+	}
+	if snapshot.SlowTicks != 2 {
+		t.Errorf("SlowTicks = %d, want 2", snapshot.SlowTicks)
+	}
+	if snapshot.Overruns != 1 {
+		t.Errorf("Overruns = %d, want 1", snapshot.Overruns)
+	}
+	if snapshot.MaxDurationMs != 150 {
+		t.Errorf("MaxDurationMs = %v, want 150", snapshot.MaxDurationMs)
+	}
+	if got := snapshot.Handlers["MINING"]; got.Calls != 2 || got.TimeMs != 5 {
+		t.Errorf("MINING handler = %+v, want 2 calls over 5ms", got)
+	}
+	if len(snapshot.Last.Handlers) != 0 {
+		t.Errorf("last tick handlers = %v, want none", snapshot.Last.Handlers)
+	}
+}
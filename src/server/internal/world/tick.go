@@ -8,85 +8,278 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/redis/go-redis/v9"
 	"github.com/trbute/idler/server/api"
+	"github.com/trbute/idler/server/data"
 	"github.com/trbute/idler/server/internal/database"
 )
 
 type TickUpdate struct {
-	InventoryUpdate *api.InventoryUpdate
-	ProgressUpdate  *api.CharacterProgressUpdate
+	InventoryUpdates []api.InventoryUpdate
+	ProgressUpdate   *api.CharacterProgressUpdate
+	ToolWear         *api.ToolWearUpdate
+	Damage           *api.CharacterDamageUpdate
+	Idle             *IdleUpdate
+	// Harvest and Strike are settled against the spawn when the tick
+	// commits, Craft against the inventory
+	Harvest *HarvestClaim
+	Strike  *StrikeClaim
+	Craft   *CraftClaim
+	// Arrived finishes the character's travel
+	Arrived bool
+
+	// characterID is who the update belongs to, filled in by the tick loop
+	characterID pgtype.UUID
 }
 
 type WorldConfig struct {
 	DB       *database.Queries
 	Redis    *redis.Client
 	TickRate time.Duration
-	Seed     *rand.Rand
+	// Seed rolls drops and loot. Handlers run concurrently, so ProcessTicks
+	// puts it behind a lock before the first tick
+	Seed *rand.Rand
+	// GroundExpiryTicks is how long dropped items last, zero keeps them forever
+	GroundExpiryTicks int32
+	// MaxCatchUpTicks caps how many ticks missed while the server was down
+	// are replayed on startup, zero skips catching up
+	MaxCatchUpTicks int32
+	// Shards splits active characters between instances, each shard ticked
+	// by whichever instance holds its lease
+	Shards int
+	// LeaseTTL is how long a shard lease outlives its last renewal
+	LeaseTTL time.Duration
+	// TickWarnShare is the share of the tick rate a tick may take before a
+	// warning is logged, zero never warns
+	TickWarnShare float64
+	// StatsAddr is where tick telemetry and the content reload endpoint are
+	// served, empty serves nothing
+	StatsAddr string
+	// AdminToken must be sent as a bearer token to reload content over
+	// HTTP, empty leaves the reload endpoint off
+	AdminToken string
+	// Content reloads world content from ContentDir between ticks
+	Content    *data.DataConfig
+	ContentDir string
+	// ContentWatch is how often ContentDir is polled for changes to reload,
+	// zero only reloads when asked
+	ContentWatch time.Duration
 	*api.ApiConfig
+
+	// telemetry measures each tick this instance runs
+	telemetry *tickTelemetry
+	// reloads queues content reloads for the tick loop to run between ticks
+	reloads chan contentReload
+	// catchingUp collects what replayed ticks did while catching up, nil
+	// when ticking live
+	catchingUp *catchUpSummary
+	// handlers runs each action's tick, keyed by action name
+	handlers map[string]ActionHandler
+	// tick counts every tick the world has run, including caught up ones
+	tick int64
 }
 
+// ProcessTicks runs the tick loop. Every instance runs it, but only shards
+// whose lease this instance holds are ticked here, so replicas never tick
+// the same character twice.
 func (cfg *WorldConfig) ProcessTicks() {
+	cfg.registerDefaultActionHandlers()
+	if cfg.Seed != nil {
+		cfg.Seed = rand.New(&lockedSource{rng: cfg.Seed})
+	}
+
+	cfg.telemetry = newTickTelemetry(cfg.TickRate, cfg.TickWarnShare)
+	cfg.reloads = make(chan contentReload)
+	if cfg.StatsAddr != "" {
+		go cfg.serveStats()
+	}
+	if cfg.Content != nil && cfg.ContentWatch > 0 {
+		go cfg.watchContent(cfg.ContentWatch)
+	}
+
+	leaseTTL := cfg.LeaseTTL
+	if leaseTTL <= 0 {
+		leaseTTL = 3 * cfg.TickRate
+	}
+	lease := newTickLease(cfg.Redis, cfg.Shards, leaseTTL)
+
 	ticker := time.NewTicker(cfg.TickRate)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		activeChars, err := cfg.GetActiveCharacters(context.Background())
-		if err != nil {
-			log.Printf("Error getting active characters: %v", err)
+	leading := false
+	var previous tickShards
+	for {
+		ctx := context.Background()
+
+		// Reloads run between ticks, so the next tick is the first to see
+		// the new content
+		select {
+		case req := <-cfg.reloads:
+			diff, err := cfg.reloadContent(ctx)
+			req.done <- contentReloadResult{diff: diff, err: err}
 			continue
+		case <-ticker.C:
 		}
 
-		updateChan := make(chan TickUpdate, len(activeChars))
-		var wg sync.WaitGroup
-
-		for _, char := range activeChars {
-			wg.Add(1)
-			go func(char database.Character) {
-				defer wg.Done()
-				if update := cfg.processCharacterAction(char); update != nil {
-					updateChan <- *update
-				}
-			}(char)
+		shards := lease.refresh(ctx)
+		acquired := shards.since(previous)
+		previous = shards
+		if shards.empty() {
+			leading = false
+			continue
 		}
 
-		go func() {
-			wg.Wait()
-			close(updateChan)
-		}()
+		// A new leader replays whatever was missed since the last recorded
+		// tick, whether the server was down or the old leader stopped. Only
+		// the shards it just took are replayed, any it already held were
+		// ticked live and would otherwise be ticked twice
+		if shards.leader() && !leading {
+			cfg.catchUp(ctx, lease, acquired)
+		}
+		leading = shards.leader()
 
-		var inventoryUpdates []api.InventoryUpdate
-		var progressUpdates []api.CharacterProgressUpdate
-		for update := range updateChan {
-			if update.InventoryUpdate != nil {
-				inventoryUpdates = append(inventoryUpdates, *update.InventoryUpdate)
-			}
-			if update.ProgressUpdate != nil {
-				progressUpdates = append(progressUpdates, *update.ProgressUpdate)
-			}
+		cfg.runTick(ctx, lease, shards)
+		if leading {
+			cfg.recordTick(ctx, time.Now())
 		}
+	}
+}
 
-		if len(inventoryUpdates) > 0 {
-			err := cfg.BatchAddItemsToInventory(context.Background(), inventoryUpdates)
-			if err != nil {
-				log.Printf("Error batch updating inventories: %v", err)
-			}
+// runTick advances the world by one tick for the shards this instance
+// holds. The leader also runs respawns and expiry, which are world wide.
+func (cfg *WorldConfig) runTick(ctx context.Context, lease *tickLease, shards tickShards) {
+	stats := TickStats{StartedAt: time.Now(), Leader: shards.leader()}
+	defer func() {
+		cfg.telemetry.record(stats, time.Since(stats.StartedAt))
+	}()
+
+	if shards.leader() {
+		start := time.Now()
+		cfg.runWorldTick(ctx)
+		stats.WorldMs = milliseconds(time.Since(start))
+	}
+
+	activeChars, err := cfg.GetActiveCharacters(ctx)
+	if err != nil {
+		log.Printf("Error getting active characters: %v", err)
+		return
+	}
+
+	owned := activeChars[:0:0]
+	for _, char := range activeChars {
+		if shards.owns(char.ID) {
+			owned = append(owned, char)
 		}
+	}
+	stats.ActiveCharacters = len(owned)
+	cfg.runCharacterTick(ctx, lease, shards, owned, &stats)
+}
 
-		if len(progressUpdates) > 0 {
-			err := cfg.ApiConfig.BatchUpdateCharacterProgress(context.Background(), progressUpdates)
-			if err != nil {
-				log.Printf("Error batch updating character progress: %v", err)
-			}
+// runWorldTick respawns nodes and creatures, regenerates health and expires
+// ground items.
+func (cfg *WorldConfig) runWorldTick(ctx context.Context) {
+	_, err := cfg.DB.TickResourceNodeRespawns(ctx)
+	if err != nil {
+		log.Printf("Error respawning resource nodes: %v", err)
+	}
+
+	_, err = cfg.DB.TickCreatureRespawns(ctx)
+	if err != nil {
+		log.Printf("Error respawning creatures: %v", err)
+	}
+
+	err = cfg.DB.RegenerateCharacterHealth(ctx)
+	if err != nil {
+		log.Printf("Error regenerating character health: %v", err)
+	}
+
+	if cfg.GroundExpiryTicks > 0 {
+		err := cfg.ExpireGroundItems(ctx, cfg.GroundExpiryTicks)
+		if err != nil {
+			log.Printf("Error expiring ground items: %v", err)
 		}
 	}
+
 }
 
-func (cfg *WorldConfig) processCharacterAction(char database.Character) *TickUpdate {
-	return cfg.processResourceGathering(char)
+// runCharacterTick runs each character's action and commits the results.
+func (cfg *WorldConfig) runCharacterTick(ctx context.Context, lease *tickLease, shards tickShards, activeChars []database.Character, stats *TickStats) {
+	start := time.Now()
+	updateChan := make(chan TickUpdate, len(activeChars))
+	var wg sync.WaitGroup
+
+	for _, char := range activeChars {
+		wg.Add(1)
+		go func(char database.Character) {
+			defer wg.Done()
+			if update := cfg.processCharacterAction(char); update != nil {
+				update.characterID = char.ID
+				updateChan <- *update
+			}
+		}(char)
+	}
+
+	go func() {
+		wg.Wait()
+		close(updateChan)
+	}()
+
+	writes := newTickWrites(activeChars)
+	for update := range updateChan {
+		writes.add(update)
+	}
+	stats.HandlerMs = milliseconds(time.Since(start))
+
+	// A slow tick can outlast the lease, and whoever took the shard over
+	// will tick its characters from here
+	if !lease.holds(ctx, shards) {
+		log.Printf("Lost a tick shard mid tick, dropping the tick's writes")
+		return
+	}
+
+	start = time.Now()
+	result, err := cfg.commitTick(ctx, writes, shards)
+	stats.DBBatchMs = milliseconds(time.Since(start))
+	if err != nil {
+		log.Printf("Error committing tick: %v", err)
+		return
+	}
+
+	// Replayed ticks are told to owners all at once when catching up ends
+	if cfg.catchingUp != nil {
+		cfg.catchingUp.add(writes, result)
+		return
+	}
+
+	start = time.Now()
+	cfg.SendNotifications(result.notifications)
+	cfg.pushTickUpdates(ctx, writes.characters, writes.inventoryOwners, result.applied, result.progress)
+	// After the progress, so characters that stopped this tick show idle
+	cfg.SendCharacterChanges(result.changes)
+	stats.PushMs = milliseconds(time.Since(start))
 }
 
-func (cfg *WorldConfig) processResourceGathering(char database.Character) *TickUpdate {
+func (cfg *WorldConfig) processTravel(char database.Character, _ database.Action) *TickUpdate {
+	if !char.DestinationX.Valid || !char.DestinationY.Valid {
+		log.Printf("Character %s is traveling without a destination", char.Name)
+		return idleUpdate("", "")
+	}
+
+	newProgress := char.ActionAmountProgress.Int32 + 1
+	if !char.ActionAmountLimit.Valid || newProgress >= char.ActionAmountLimit.Int32 {
+		return &TickUpdate{Arrived: true}
+	}
+
+	return &TickUpdate{
+		ProgressUpdate: &api.CharacterProgressUpdate{
+			CharacterID: char.ID,
+			Progress:    newProgress,
+		},
+	}
+}
+
+func (cfg *WorldConfig) processResourceGathering(char database.Character, _ database.Action) *TickUpdate {
 	ctx := context.Background()
 
 	if !char.ActionTarget.Valid {
@@ -97,14 +290,9 @@ func (cfg *WorldConfig) processResourceGathering(char database.Character) *TickU
 	// Check if character has reached their gathering limit
 	if char.ActionAmountLimit.Valid && char.ActionAmountProgress.Valid {
 		if char.ActionAmountProgress.Int32 >= char.ActionAmountLimit.Int32 {
-			err := cfg.ApiConfig.SetCharacterToIdle(ctx, char.ID)
-			if err != nil {
-				log.Printf("Failed to set character %s to idle: %v", char.Name, err)
-			}
 			message := fmt.Sprintf("Character %s finished gathering %d items and is now idle",
 				char.Name, char.ActionAmountLimit.Int32)
-			cfg.ApiConfig.Hub.SendNotificationToUser(char.UserID.Bytes, message, "info")
-			return nil
+			return idleUpdate(message, "info")
 		}
 	}
 
@@ -131,26 +319,174 @@ func (cfg *WorldConfig) processResourceGathering(char database.Character) *TickU
 		return nil
 	}
 
+	node, err := cfg.GetResourceNodeById(ctx, spawn.NodeID)
+	if err != nil {
+		log.Printf("Error getting resource node for character %s: %v", char.Name, err)
+		return nil
+	}
+
+	action, err := cfg.GetActionById(ctx, node.ActionID)
+	if err != nil {
+		log.Printf("Error getting action for node %d for character %s: %v", node.ID, char.Name, err)
+		return nil
+	}
+
+	// The tool may have been dropped since the action started, so look it up
+	// every tick rather than trusting the check made when gathering began
+	quantity := int32(1)
+	var toolWear *api.ToolWearUpdate
+	if action.RequiredToolTypeID.Valid {
+		tool, toolInstance, err := cfg.GetBestToolForType(ctx, char.ID, action.RequiredToolTypeID.Int32, node.MinToolTier)
+		if err != nil {
+			log.Printf("Error checking tool for character %s: %v", char.Name, err)
+			return nil
+		}
+		if tool == nil {
+			message := fmt.Sprintf("Character %s no longer has a tool for %s and is now idle",
+				char.Name, node.Name)
+			return idleUpdate(message, "warning")
+		}
+		quantity = api.GatherYield(tool.ToolTier, node.MinToolTier)
+		if toolInstance.Durability.Valid {
+			toolWear = &api.ToolWearUpdate{
+				CharacterID:     char.ID,
+				InventoryItemID: toolInstance.ID,
+			}
+		}
+	}
+
+	// Slower nodes only drop once enough ticks have been spent on them
+	ticks := char.ActionTicks + 1
+	if ticks < node.IntervalTicks {
+		return &TickUpdate{
+			ProgressUpdate: &api.CharacterProgressUpdate{
+				CharacterID: char.ID,
+				Progress:    char.ActionAmountProgress.Int32,
+				Ticks:       ticks,
+			},
+		}
+	}
+	quantity *= cfg.rollQuantity(node.MinQuantity, node.MaxQuantity)
+
+	if char.ActionAmountLimit.Valid {
+		remaining := char.ActionAmountLimit.Int32 - char.ActionAmountProgress.Int32
+		if quantity > remaining {
+			quantity = remaining
+		}
+	}
+
 	drop := cfg.rollDrop(resources)
 
-	result := &TickUpdate{
-		InventoryUpdate: &api.InventoryUpdate{
+	// What the spawn has left is only checked when the tick commits, so
+	// the claim may come back smaller or empty
+	return &TickUpdate{
+		Harvest: &HarvestClaim{
+			SpawnID:      spawn.ID,
+			NodeName:     node.Name,
+			NodeTier:     node.Tier,
+			RespawnTicks: node.RespawnTicks,
+			InventoryID:  inventory.ID,
+			ItemID:       drop.ItemID,
+			Quantity:     quantity,
+			Progress:     char.ActionAmountProgress.Int32,
+			ActionID:     action.ID,
+			ToolWear:     toolWear,
+		},
+	}
+}
+
+func (cfg *WorldConfig) processCrafting(char database.Character, _ database.Action) *TickUpdate {
+	ctx := context.Background()
+
+	if !char.ActionRecipeID.Valid {
+		log.Printf("Character %s has no recipe to craft", char.Name)
+		return nil
+	}
+
+	recipe, err := cfg.GetRecipeById(ctx, char.ActionRecipeID.Int32)
+	if err != nil {
+		log.Printf("Error getting recipe for character %s: %v", char.Name, err)
+		return nil
+	}
+
+	if char.ActionAmountLimit.Valid && char.ActionAmountProgress.Valid {
+		if char.ActionAmountProgress.Int32 >= char.ActionAmountLimit.Int32 {
+			message := fmt.Sprintf("Character %s finished crafting %d %s and is now idle",
+				char.Name, char.ActionAmountLimit.Int32, recipe.Name)
+			return idleUpdate(message, "info")
+		}
+	}
+
+	inventory, err := cfg.GetInventoryByCharacterId(ctx, char.ID)
+	if err != nil {
+		log.Printf("Error getting inventory for character %s: %v", char.Name, err)
+		return nil
+	}
+
+	ingredients, err := cfg.GetRecipeIngredientsByRecipeId(ctx, recipe.ID)
+	if err != nil {
+		log.Printf("Error getting ingredients for character %s: %v", char.Name, err)
+		return nil
+	}
+
+	missing, err := cfg.FindMissingIngredient(ctx, inventory.ID, ingredients)
+	if err != nil {
+		log.Printf("Error checking ingredients for character %s: %v", char.Name, err)
+		return nil
+	}
+	if missing != nil {
+		message := fmt.Sprintf("Character %s ran out of ingredients for %s and is now idle",
+			char.Name, recipe.Name)
+		return idleUpdate(message, "warning")
+	}
+
+	// Outputs are capacity checked before ingredients are removed, so make
+	// sure they fit on their own rather than losing the ingredients
+	canAdd, err := cfg.CheckInventoryCapacity(ctx, inventory.ID, recipe.ItemID, recipe.Quantity)
+	if err != nil {
+		log.Printf("Error checking capacity for character %s: %v", char.Name, err)
+		return nil
+	}
+	if !canAdd {
+		return idleUpdate(api.InventoryFullMessage(char.Name), "warning")
+	}
+
+	craft := &CraftClaim{
+		InventoryID: inventory.ID,
+		RecipeName:  recipe.Name,
+		Output: api.InventoryUpdate{
 			InventoryID: inventory.ID,
-			ItemID:      drop.ItemID,
-			Quantity:    1,
+			ItemID:      recipe.ItemID,
+			Quantity:    recipe.Quantity,
 		},
 	}
+	for _, ingredient := range ingredients {
+		craft.Ingredients = append(craft.Ingredients, api.InventoryUpdate{
+			InventoryID: inventory.ID,
+			ItemID:      ingredient.ItemID,
+			Quantity:    -ingredient.Quantity,
+		})
+	}
 
-	// Add progress update if character has a limit set
 	if char.ActionAmountLimit.Valid {
-		newProgress := char.ActionAmountProgress.Int32 + 1
-		result.ProgressUpdate = &api.CharacterProgressUpdate{
+		craft.Progress = &api.CharacterProgressUpdate{
 			CharacterID: char.ID,
-			Progress:    newProgress,
+			Progress:    char.ActionAmountProgress.Int32 + 1,
 		}
 	}
 
-	return result
+	return &TickUpdate{Craft: craft}
+}
+
+// rollQuantity picks how many items a drop yields from a node's range.
+func (cfg *WorldConfig) rollQuantity(minQuantity, maxQuantity int32) int32 {
+	if minQuantity < 1 {
+		minQuantity = 1
+	}
+	if maxQuantity <= minQuantity {
+		return minQuantity
+	}
+	return minQuantity + int32(cfg.Seed.Intn(int(maxQuantity-minQuantity+1)))
 }
 
 func (cfg *WorldConfig) rollDrop(resources []database.Resource) database.Resource {
@@ -178,3 +514,29 @@ func (cfg *WorldConfig) rollDrop(resources []database.Resource) database.Resourc
 
 	return resources[0]
 }
+
+// lockedSource lets one *rand.Rand be shared by every handler goroutine.
+// A rand.Rand built on it only keeps state in the source, so locking here
+// is enough for Intn and friends.
+type lockedSource struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rng.Seed(seed)
+}
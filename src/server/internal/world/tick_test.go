@@ -0,0 +1,52 @@
+package world
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestRollQuantity(t *testing.T) {
+	cfg := &WorldConfig{Seed: rand.New(rand.NewSource(1))}
+
+	if got := cfg.rollQuantity(1, 1); got != 1 {
+		t.Errorf("rollQuantity(1, 1) = %d, want 1", got)
+	}
+	if got := cfg.rollQuantity(0, 0); got != 1 {
+		t.Errorf("rollQuantity(0, 0) = %d, want 1", got)
+	}
+	if got := cfg.rollQuantity(3, 2); got != 3 {
+		t.Errorf("rollQuantity(3, 2) = %d, want 3", got)
+	}
+
+	seen := map[int32]bool{}
+	for i := 0; i < 200; i++ {
+		got := cfg.rollQuantity(1, 3)
+		if got < 1 || got > 3 {
+			t.Fatalf("rollQuantity(1, 3) = %d, want between 1 and 3", got)
+		}
+		seen[got] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("rollQuantity(1, 3) only produced %v", seen)
+	}
+}
+
+func TestLockedSourceShared(t *testing.T) {
+	cfg := &WorldConfig{Seed: rand.New(&lockedSource{rng: rand.New(rand.NewSource(1))})}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				if got := cfg.rollQuantity(1, 3); got < 1 || got > 3 {
+					t.Errorf("rollQuantity(1, 3) = %d, want between 1 and 3", got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -63,7 +63,8 @@ func main() {
 	}
 
 	dataCfg := data.DataConfig{
-		DB: DbConn,
+		DB:   DbConn,
+		Pool: pool,
 	}
 
 	dataCfg.InitData()
@@ -74,6 +75,13 @@ func main() {
 	}
 
 	tickRate := time.Duration(time.Duration(tickInt) * time.Millisecond)
+	travelTicks := getEnvInt("TRAVEL_TICKS", 5)
+	groundExpiryTicks := getEnvInt("GROUND_EXPIRY_TICKS", 600)
+	maxCatchUpTicks := getEnvInt("CATCH_UP_MAX_TICKS", 3600)
+	tickShards := getEnvInt("TICK_SHARDS", 1)
+	tickLeaseMs := getEnvInt("TICK_LEASE_MS", 0)
+	tickWarnPercent := getEnvInt("TICK_WARN_PERCENT", 80)
+	contentWatchMs := getEnvInt("CONTENT_WATCH_MS", 0)
 	seed := rand.New(rand.NewSource(time.Now().UnixNano()))
 
 	rdb := redis.NewClient(&redis.Options{
@@ -90,32 +98,70 @@ func main() {
 	}
 	defer rdb.Close()
 
-	hub := websocket.NewHub()
-	go hub.Run()
+	hub := websocket.NewHub(rdb)
 
 	limiter := ratelimit.NewLimiter(rdb)
 
 	apiCfg := api.ApiConfig{
-		DB:        DbConn,
-		JwtSecret: jwtSecret,
-		Redis:     rdb,
-		Pool:      pool,
-		Hub:       hub,
-		Limiter:   limiter,
+		DB:          DbConn,
+		JwtSecret:   jwtSecret,
+		Redis:       rdb,
+		Pool:        pool,
+		Hub:         hub,
+		Limiter:     limiter,
+		TravelTicks: int32(travelTicks),
 	}
 
 	worldCfg := world.WorldConfig{
-		DB:        DbConn,
-		Redis:     rdb,
-		TickRate:  tickRate,
-		Seed:      seed,
-		ApiConfig: &apiCfg,
+		DB:                DbConn,
+		Redis:             rdb,
+		TickRate:          tickRate,
+		Seed:              seed,
+		GroundExpiryTicks: int32(groundExpiryTicks),
+		MaxCatchUpTicks:   int32(maxCatchUpTicks),
+		Shards:            tickShards,
+		LeaseTTL:          time.Duration(tickLeaseMs) * time.Millisecond,
+		TickWarnShare:     float64(tickWarnPercent) / 100,
+		StatsAddr:         os.Getenv("STATS_ADDR"),
+		AdminToken:        os.Getenv("ADMIN_TOKEN"),
+		Content:           &dataCfg,
+		ContentDir:        "data/json",
+		ContentWatch:      time.Duration(contentWatchMs) * time.Millisecond,
+		ApiConfig:         &apiCfg,
 	}
 
+	// Connections left by replicas that stopped without closing them are
+	// cleared and their users marked away. With no other replica running
+	// nobody can be connected, so anyone still marked online is away too
+	othersRunning, err := hub.Start(context.Background(), &apiCfg)
+	if err != nil {
+		log.Printf("Unable to clear stale connections: %v", err)
+	} else if !othersRunning {
+		err = DbConn.MarkOnlineUsersSeen(context.Background())
+		if err != nil {
+			log.Printf("Unable to mark users away: %v", err)
+		}
+	}
+	go hub.Run()
+
 	go worldCfg.ProcessTicks()
 	apiCfg.ServeApi()
 }
 
+func getEnvInt(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Unable to convert %s to int: %v", name, err)
+	}
+
+	return parsed
+}
+
 func validateJWTSecret(secret string) error {
 	if secret == "" {
 		return errors.New("JWT_SECRET environment variable is required")
@@ -9,5 +9,14 @@ WHERE id = $1;
 -- name: GetAllActions :many
 SELECT id, name FROM actions;
 
--- name: CreateAction :exec
-INSERT INTO actions (name) VALUES ($1);
+-- name: ListActions :many
+SELECT * FROM actions ORDER BY id;
+
+-- name: UpsertAction :one
+INSERT INTO actions (name, required_tool_type_id) VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE SET required_tool_type_id = EXCLUDED.required_tool_type_id
+RETURNING *;
+
+-- name: DeleteActionsExcept :many
+DELETE FROM actions WHERE id <> ALL(@ids::INTEGER[])
+RETURNING *;
@@ -0,0 +1,28 @@
+-- name: BatchRecordCharacterGains :exec
+INSERT INTO character_gains (character_id, item_id, quantity)
+SELECT i.character_id, u.item_id, SUM(u.quantity)::INTEGER
+FROM UNNEST(@inventory_ids::UUID[], @item_ids::INTEGER[], @quantities::INTEGER[]) AS u(inventory_id, item_id, quantity)
+JOIN inventories i ON i.id = u.inventory_id
+JOIN characters c ON c.id = i.character_id
+JOIN users us ON us.id = c.user_id
+WHERE us.last_seen_at IS NOT NULL
+GROUP BY i.character_id, u.item_id
+ON CONFLICT (character_id, item_id) DO UPDATE SET
+	quantity = character_gains.quantity + EXCLUDED.quantity;
+
+-- name: RecordCharacterEvent :exec
+INSERT INTO character_events (character_id, message, created_at)
+SELECT c.id, @message::TEXT, NOW()
+FROM characters c
+JOIN users us ON us.id = c.user_id
+WHERE c.id = @character_id AND us.last_seen_at IS NOT NULL;
+
+-- name: TakeCharacterGainsByUserId :many
+DELETE FROM character_gains
+WHERE character_id IN (SELECT id FROM characters WHERE user_id = $1)
+RETURNING *;
+
+-- name: TakeCharacterEventsByUserId :many
+DELETE FROM character_events
+WHERE character_id IN (SELECT id FROM characters WHERE user_id = $1)
+RETURNING *;
@@ -0,0 +1,16 @@
+-- name: GetCharacterSkills :many
+SELECT * FROM character_skills
+WHERE character_id = $1
+ORDER BY action_id;
+
+-- name: GetCharacterSkill :one
+SELECT * FROM character_skills
+WHERE character_id = $1 AND action_id = $2;
+
+-- name: BatchAddSkillExperience :exec
+INSERT INTO character_skills(character_id, action_id, experience, created_at, updated_at)
+SELECT unnest(@character_ids::UUID[]), unnest(@action_ids::INTEGER[]), unnest(@experience::INTEGER[]), NOW(), NOW()
+ON CONFLICT (character_id, action_id)
+DO UPDATE SET
+	experience = character_skills.experience + EXCLUDED.experience,
+	updated_at = NOW();
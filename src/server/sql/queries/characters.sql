@@ -34,6 +34,11 @@ SET action_id = $1,
 	action_target = $2,
 	action_amount_limit = $3,
 	action_amount_progress = 0,
+	action_recipe_id = NULL,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_creature_target = NULL,
+	action_ticks = 0,
 	updated_at = NOW()
 WHERE id = $4
 RETURNING *;
@@ -48,9 +53,10 @@ RETURNING *;
 -- name: BatchUpdateCharacterProgress :exec
 UPDATE characters AS c
 SET action_amount_progress = updates.progress,
+	action_ticks = updates.ticks,
 	updated_at = NOW()
 FROM (
-	SELECT unnest($1::UUID[]) AS id, unnest($2::INTEGER[]) AS progress
+	SELECT unnest($1::UUID[]) AS id, unnest($2::INTEGER[]) AS progress, unnest($3::INTEGER[]) AS ticks
 ) AS updates
 WHERE c.id = updates.id;
 
@@ -60,10 +66,134 @@ SET action_id = $1,
 	action_target = NULL,
 	action_amount_limit = NULL,
 	action_amount_progress = 0,
+	action_recipe_id = NULL,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_creature_target = NULL,
+	action_ticks = 0,
 	updated_at = NOW()
 WHERE id = $2
 RETURNING *;
 
+-- name: StartCharacterCrafting :one
+UPDATE characters
+SET action_id = $1,
+	action_target = NULL,
+	action_recipe_id = $2,
+	action_amount_limit = $3,
+	action_amount_progress = 0,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_creature_target = NULL,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE id = $4
+RETURNING *;
+
+-- name: StartCharacterTravel :one
+UPDATE characters
+SET action_id = $1,
+	action_target = NULL,
+	action_recipe_id = NULL,
+	destination_x = $2,
+	destination_y = $3,
+	action_amount_limit = $4,
+	action_amount_progress = 0,
+	action_creature_target = NULL,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE id = $5
+RETURNING *;
+
+-- name: CompleteCharacterTravel :one
+UPDATE characters
+SET position_x = destination_x,
+	position_y = destination_y,
+	action_id = $1,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_amount_limit = NULL,
+	action_amount_progress = 0,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE id = $2 AND destination_x IS NOT NULL AND destination_y IS NOT NULL
+RETURNING *;
+
 -- name: GetActiveCharacters :many
 SELECT * FROM characters
 WHERE action_id != 1;
+
+
+-- name: GetCharactersByActionTarget :many
+SELECT * FROM characters
+WHERE action_target = $1;
+
+-- name: GetCharactersByCreatureTarget :many
+SELECT * FROM characters
+WHERE action_creature_target = $1;
+
+-- name: StartCharacterCombat :one
+UPDATE characters
+SET action_id = $1,
+	action_creature_target = $2,
+	action_target = NULL,
+	action_recipe_id = NULL,
+	action_amount_limit = NULL,
+	action_amount_progress = 0,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE id = $3
+RETURNING *;
+
+-- name: BatchDamageCharacters :many
+UPDATE characters AS c
+SET hp = GREATEST(c.hp - d.damage, 0),
+	updated_at = NOW()
+FROM (
+	SELECT unnest(@ids::UUID[]) AS id, unnest(@damages::INTEGER[]) AS damage
+) AS d
+WHERE c.id = d.id
+RETURNING c.*;
+
+-- name: RespawnCharacter :one
+UPDATE characters
+SET hp = max_hp,
+	position_x = $1,
+	position_y = $2,
+	action_id = $3,
+	action_target = NULL,
+	action_creature_target = NULL,
+	action_recipe_id = NULL,
+	action_amount_limit = NULL,
+	action_amount_progress = 0,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE id = $4
+RETURNING *;
+
+-- name: RegenerateCharacterHealth :exec
+UPDATE characters
+SET hp = LEAST(hp + 1, max_hp)
+WHERE hp < max_hp AND action_creature_target IS NULL;
+
+-- name: IdleCharactersUsingRemovedContent :many
+UPDATE characters
+SET action_id = @idle_action_id,
+	action_target = NULL,
+	action_amount_limit = NULL,
+	action_amount_progress = 0,
+	action_recipe_id = NULL,
+	destination_x = NULL,
+	destination_y = NULL,
+	action_creature_target = NULL,
+	action_ticks = 0,
+	updated_at = NOW()
+WHERE action_id <> ALL(@action_ids::INTEGER[])
+	OR action_target <> ALL(@resource_node_spawn_ids::INTEGER[])
+	OR action_creature_target <> ALL(@creature_spawn_ids::INTEGER[])
+	OR action_recipe_id <> ALL(@recipe_ids::INTEGER[])
+RETURNING *;
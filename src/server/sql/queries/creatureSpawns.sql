@@ -0,0 +1,37 @@
+-- name: UpsertCreatureSpawn :one
+INSERT INTO creature_spawns (creature_id, position_x, position_y, hp) VALUES ($1, $2, $3, $4)
+ON CONFLICT (creature_id, position_x, position_y) DO UPDATE SET
+	hp = LEAST(creature_spawns.hp, EXCLUDED.hp)
+RETURNING *;
+
+-- name: ListCreatureSpawns :many
+SELECT * FROM creature_spawns ORDER BY id;
+
+-- name: DeleteCreatureSpawnsExcept :many
+DELETE FROM creature_spawns WHERE id <> ALL(@ids::INTEGER[])
+RETURNING *;
+
+-- name: GetCreatureSpawnById :one
+SELECT * FROM creature_spawns WHERE id = $1;
+
+-- name: GetCreatureSpawnsByCoordinates :many
+SELECT * FROM creature_spawns WHERE position_x = $1 AND position_y = $2;
+
+-- name: LockCreatureSpawn :one
+SELECT * FROM creature_spawns WHERE id = $1
+FOR UPDATE;
+
+-- name: DamageCreatureSpawn :one
+UPDATE creature_spawns
+SET hp = hp - @damage::INTEGER,
+	respawn_ticks_left = CASE WHEN hp = @damage::INTEGER THEN @respawn_ticks::INTEGER ELSE respawn_ticks_left END
+WHERE id = @id::INTEGER AND hp >= @damage::INTEGER
+RETURNING *;
+
+-- name: TickCreatureRespawns :many
+UPDATE creature_spawns AS s
+SET respawn_ticks_left = GREATEST(s.respawn_ticks_left - 1, 0),
+	hp = CASE WHEN s.respawn_ticks_left <= 1 THEN c.max_hp ELSE s.hp END
+FROM creatures AS c
+WHERE s.creature_id = c.id AND s.hp = 0
+RETURNING s.*;
@@ -0,0 +1,38 @@
+-- name: UpsertCreature :one
+INSERT INTO creatures (name, tier, max_hp, damage, respawn_ticks) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (name) DO UPDATE SET
+	tier = EXCLUDED.tier,
+	max_hp = EXCLUDED.max_hp,
+	damage = EXCLUDED.damage,
+	respawn_ticks = EXCLUDED.respawn_ticks
+RETURNING *;
+
+-- name: ListCreatures :many
+SELECT * FROM creatures ORDER BY id;
+
+-- name: DeleteCreaturesExcept :many
+DELETE FROM creatures WHERE id <> ALL(@ids::INTEGER[])
+RETURNING *;
+
+-- name: GetCreatureById :one
+SELECT * FROM creatures WHERE id = $1;
+
+-- name: GetCreatureByName :one
+SELECT * FROM creatures WHERE name = $1;
+
+-- name: UpsertCreatureDrop :one
+INSERT INTO creature_drops (creature_id, item_id, drop_chance, quantity) VALUES ($1, $2, $3, $4)
+ON CONFLICT (creature_id, item_id) DO UPDATE SET
+	drop_chance = EXCLUDED.drop_chance,
+	quantity = EXCLUDED.quantity
+RETURNING *;
+
+-- name: ListCreatureDrops :many
+SELECT * FROM creature_drops ORDER BY id;
+
+-- name: DeleteCreatureDropsExcept :exec
+DELETE FROM creature_drops WHERE id <> ALL(@ids::INTEGER[]);
+
+-- name: GetCreatureDropsByCreatureId :many
+SELECT * FROM creature_drops
+WHERE creature_id = $1;
@@ -1,5 +1,19 @@
 -- name: GetGrid :many
 SELECT * FROM grid;
 
--- name: CreateGridItem :exec
-INSERT INTO grid (position_x, position_y) VALUES ($1, $2);
+-- name: GetGridItem :one
+SELECT * FROM grid
+WHERE position_x = $1 AND position_y = $2;
+
+-- name: UpsertGridItem :exec
+INSERT INTO grid (position_x, position_y) VALUES ($1, $2)
+ON CONFLICT (position_x, position_y) DO NOTHING;
+
+-- name: GetOccupiedGridItems :many
+SELECT * FROM grid AS g
+WHERE EXISTS (SELECT 1 FROM characters AS c WHERE c.position_x = g.position_x AND c.position_y = g.position_y)
+	OR EXISTS (SELECT 1 FROM inventories AS i WHERE i.user_id IS NOT NULL AND i.position_x = g.position_x AND i.position_y = g.position_y)
+ORDER BY g.position_x, g.position_y;
+
+-- name: DeleteGridItem :exec
+DELETE FROM grid WHERE position_x = $1 AND position_y = $2;
@@ -15,6 +15,11 @@ RETURNING *;
 SELECT * FROM inventories
 WHERE id = $1;
 
+-- name: LockInventory :one
+SELECT * FROM inventories
+WHERE id = $1
+FOR UPDATE;
+
 -- name: GetInventoryByCharacterId :one
 SELECT * FROM inventories
 WHERE character_id = $1;
@@ -23,3 +28,39 @@ WHERE character_id = $1;
 UPDATE inventories
 SET weight = weight + $2, updated_at = NOW()
 WHERE id = $1;
+
+-- name: UpdateInventoryPositionByCharacterId :exec
+UPDATE inventories
+SET position_x = $2, position_y = $3, updated_at = NOW()
+WHERE character_id = $1;
+
+-- name: GetGroundInventoryByCoordinates :one
+SELECT * FROM inventories
+WHERE character_id IS NULL AND user_id IS NULL AND position_x = $1 AND position_y = $2;
+
+-- name: CreateGroundInventory :one
+INSERT INTO inventories(id, character_id, position_x, position_y, capacity, created_at, updated_at)
+VALUES (gen_random_uuid(), NULL, $1, $2, $3, NOW(), NOW())
+ON CONFLICT (position_x, position_y) WHERE character_id IS NULL AND user_id IS NULL
+DO UPDATE SET updated_at = NOW()
+RETURNING *;
+
+-- name: GetStashByUserId :one
+SELECT * FROM inventories
+WHERE user_id = $1;
+
+-- name: CreateStash :one
+INSERT INTO inventories(id, user_id, position_x, position_y, capacity, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, 0, 0, $2, NOW(), NOW())
+ON CONFLICT (user_id) WHERE user_id IS NOT NULL
+DO UPDATE SET updated_at = NOW()
+RETURNING *;
+
+-- name: RecomputeInventoryWeights :exec
+UPDATE inventories
+SET weight = COALESCE((
+	SELECT SUM(ii.quantity * it.weight)
+	FROM inventory_items ii
+	JOIN items it ON it.id = ii.item_id
+	WHERE ii.inventory_id = inventories.id
+), 0)::INTEGER;
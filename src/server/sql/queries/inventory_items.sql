@@ -5,29 +5,69 @@ WHERE inventory_id = $1;
 -- name: AddItemsToInventory :one
 INSERT INTO inventory_items(id, inventory_id, item_id, quantity, updated_at, created_at)
 VALUES (gen_random_uuid(), $1, $2, $3, NOW(), NOW())
-ON CONFLICT (inventory_id, item_id) 
+ON CONFLICT (inventory_id, item_id) WHERE durability IS NULL
 DO UPDATE SET
 	quantity = inventory_items.quantity + EXCLUDED.quantity,
+	ticks_untouched = 0,
 	updated_at = NOW()
 RETURNING *;
 
 -- name: BatchAddItemsToInventory :exec
 INSERT INTO inventory_items(id, inventory_id, item_id, quantity, updated_at, created_at)
 SELECT gen_random_uuid(), unnest($1::UUID[]), unnest($2::INTEGER[]), unnest($3::INTEGER[]), NOW(), NOW()
-ON CONFLICT (inventory_id, item_id) 
+ON CONFLICT (inventory_id, item_id) WHERE durability IS NULL
 DO UPDATE SET
 	quantity = inventory_items.quantity + EXCLUDED.quantity,
+	ticks_untouched = 0,
 	updated_at = NOW();
 
--- name: RemoveItemsFromInventory :exec
+-- name: RemoveItemsFromInventory :execrows
 UPDATE inventory_items 
-SET quantity = quantity - $3, updated_at = NOW()
-WHERE inventory_id = $1 AND item_id = $2 AND quantity >= $3;
+SET quantity = quantity - $3, ticks_untouched = 0, updated_at = NOW()
+WHERE inventory_id = $1 AND item_id = $2 AND quantity >= $3 AND durability IS NULL;
+
+-- name: LockInventoryItems :many
+SELECT * FROM inventory_items
+WHERE inventory_id = $1 AND item_id = $2
+FOR UPDATE;
 
 -- name: GetInventoryItemQuantity :one
-SELECT quantity FROM inventory_items
+SELECT COALESCE(SUM(quantity), 0)::INTEGER AS quantity FROM inventory_items
 WHERE inventory_id = $1 AND item_id = $2;
 
 -- name: DeleteEmptyInventoryItems :exec
 DELETE FROM inventory_items
 WHERE inventory_id = $1 AND quantity <= 0;
+
+
+-- name: BatchAddItemInstancesToInventory :exec
+INSERT INTO inventory_items(id, inventory_id, item_id, quantity, durability, updated_at, created_at)
+SELECT gen_random_uuid(), unnest(@inventory_ids::UUID[]), unnest(@item_ids::INTEGER[]), 1, unnest(@durabilities::INTEGER[]), NOW(), NOW();
+
+-- name: BatchWearInventoryItems :many
+UPDATE inventory_items
+SET durability = durability - 1, updated_at = NOW()
+WHERE id = ANY(@ids::UUID[]) AND durability IS NOT NULL
+RETURNING *;
+
+-- name: DeleteInventoryItemsById :exec
+DELETE FROM inventory_items
+WHERE id = ANY(@ids::UUID[]);
+
+-- name: MoveInventoryItemsById :exec
+UPDATE inventory_items
+SET inventory_id = @inventory_id, ticks_untouched = 0, updated_at = NOW()
+WHERE id = ANY(@ids::UUID[]);
+
+-- name: TickGroundItems :exec
+UPDATE inventory_items AS ii
+SET ticks_untouched = ii.ticks_untouched + 1
+FROM inventories AS i
+WHERE ii.inventory_id = i.id AND i.character_id IS NULL AND i.user_id IS NULL;
+
+-- name: DeleteExpiredGroundItems :many
+DELETE FROM inventory_items AS ii
+USING inventories AS i
+WHERE ii.inventory_id = i.id AND i.character_id IS NULL AND i.user_id IS NULL
+	AND ii.ticks_untouched >= @expiry_ticks::INTEGER
+RETURNING ii.*;
@@ -1,5 +1,18 @@
--- name: CreateItem :exec
-INSERT INTO items (name, weight) VALUES ($1, $2);
+-- name: UpsertItem :one
+INSERT INTO items (name, weight, tool_type_id, tool_tier, max_durability) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (name) DO UPDATE SET
+	weight = EXCLUDED.weight,
+	tool_type_id = EXCLUDED.tool_type_id,
+	tool_tier = EXCLUDED.tool_tier,
+	max_durability = EXCLUDED.max_durability
+RETURNING *;
+
+-- name: ListItems :many
+SELECT * FROM items ORDER BY id;
+
+-- name: DeleteItemsExcept :many
+DELETE FROM items WHERE id <> ALL(@ids::INTEGER[])
+RETURNING *;
 
 -- name: GetItemByResourceId :one
 SELECT * FROM items
@@ -12,3 +25,12 @@ WHERE id = $1;
 -- name: GetItemByName :one
 SELECT * FROM items
 WHERE name = $1;
+
+-- name: GetItemHoldersExcept :many
+SELECT it.name AS item_name, c.name AS character_name, i.user_id, i.position_x, i.position_y
+FROM inventory_items ii
+JOIN items it ON it.id = ii.item_id
+JOIN inventories i ON i.id = ii.inventory_id
+LEFT JOIN characters c ON c.id = i.character_id
+WHERE ii.item_id <> ALL(@ids::INTEGER[]) AND ii.quantity > 0
+ORDER BY it.name, c.name, i.position_x, i.position_y;
@@ -0,0 +1,37 @@
+-- name: UpsertRecipe :one
+INSERT INTO recipes (name, item_id, quantity) VALUES ($1, $2, $3)
+ON CONFLICT (name) DO UPDATE SET
+	item_id = EXCLUDED.item_id,
+	quantity = EXCLUDED.quantity
+RETURNING *;
+
+-- name: DeleteRecipesExcept :many
+DELETE FROM recipes WHERE id <> ALL(@ids::INTEGER[])
+RETURNING *;
+
+-- name: GetRecipeById :one
+SELECT * FROM recipes
+WHERE id = $1;
+
+-- name: GetRecipeByName :one
+SELECT * FROM recipes
+WHERE name = $1;
+
+-- name: GetAllRecipes :many
+SELECT * FROM recipes
+ORDER BY name;
+
+-- name: UpsertRecipeIngredient :one
+INSERT INTO recipe_ingredients (recipe_id, item_id, quantity) VALUES ($1, $2, $3)
+ON CONFLICT (recipe_id, item_id) DO UPDATE SET quantity = EXCLUDED.quantity
+RETURNING *;
+
+-- name: ListRecipeIngredients :many
+SELECT * FROM recipe_ingredients ORDER BY id;
+
+-- name: DeleteRecipeIngredientsExcept :exec
+DELETE FROM recipe_ingredients WHERE id <> ALL(@ids::INTEGER[]);
+
+-- name: GetRecipeIngredientsByRecipeId :many
+SELECT * FROM recipe_ingredients
+WHERE recipe_id = $1;
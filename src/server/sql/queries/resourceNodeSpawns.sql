@@ -10,5 +10,34 @@ SELECT * FROM resource_node_spawns WHERE position_x = $1 AND position_y = $2;
 -- name: GetResourceNodeSpawnByCoordsAndNodeId :one
 SELECT * FROM resource_node_spawns WHERE position_x = $1 AND position_y = $2 AND node_id = $3;
 
--- name: CreateResourceNodeSpawn :exec
-INSERT INTO resource_node_spawns (node_id, position_x, position_y) VALUES ($1, $2, $3);
+-- name: UpsertResourceNodeSpawn :one
+INSERT INTO resource_node_spawns (node_id, position_x, position_y, remaining) VALUES ($1, $2, $3, $4)
+ON CONFLICT (node_id, position_x, position_y) DO UPDATE SET
+	remaining = CASE
+		WHEN EXCLUDED.remaining IS NULL OR resource_node_spawns.remaining IS NULL THEN EXCLUDED.remaining
+		ELSE LEAST(resource_node_spawns.remaining, EXCLUDED.remaining)
+	END
+RETURNING *;
+
+-- name: DeleteResourceNodeSpawnsExcept :many
+DELETE FROM resource_node_spawns WHERE id <> ALL(@ids::INTEGER[])
+RETURNING *;
+
+-- name: LockResourceNodeSpawn :one
+SELECT * FROM resource_node_spawns WHERE id = $1
+FOR UPDATE;
+
+-- name: HarvestResourceNodeSpawn :one
+UPDATE resource_node_spawns
+SET remaining = remaining - @quantity::INTEGER,
+	respawn_ticks_left = @respawn_ticks::INTEGER
+WHERE id = @id::INTEGER AND remaining >= @quantity::INTEGER
+RETURNING *;
+
+-- name: TickResourceNodeRespawns :many
+UPDATE resource_node_spawns AS s
+SET respawn_ticks_left = GREATEST(s.respawn_ticks_left - 1, 0),
+	remaining = CASE WHEN s.respawn_ticks_left <= 1 THEN n.amount ELSE s.remaining END
+FROM resource_nodes AS n
+WHERE s.node_id = n.id AND s.remaining = 0
+RETURNING s.*;
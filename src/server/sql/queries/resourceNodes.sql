@@ -1,8 +1,27 @@
 -- name: GetResourceNodeById :one
 SELECT * FROM resource_nodes WHERE id = $1;
 
--- name: CreateResourceNode :exec
-INSERT INTO resource_nodes (name, action_id, tier) VALUES ($1, $2, $3);
+-- name: UpsertResourceNode :one
+INSERT INTO resource_nodes (name, action_id, tier, min_tool_tier, min_level, amount, respawn_ticks, interval_ticks, min_quantity, max_quantity, biome) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (name) DO UPDATE SET
+	action_id = EXCLUDED.action_id,
+	tier = EXCLUDED.tier,
+	min_tool_tier = EXCLUDED.min_tool_tier,
+	min_level = EXCLUDED.min_level,
+	amount = EXCLUDED.amount,
+	respawn_ticks = EXCLUDED.respawn_ticks,
+	interval_ticks = EXCLUDED.interval_ticks,
+	min_quantity = EXCLUDED.min_quantity,
+	max_quantity = EXCLUDED.max_quantity,
+	biome = EXCLUDED.biome
+RETURNING *;
+
+-- name: ListResourceNodes :many
+SELECT * FROM resource_nodes ORDER BY id;
+
+-- name: DeleteResourceNodesExcept :many
+DELETE FROM resource_nodes WHERE id <> ALL(@ids::INTEGER[])
+RETURNING *;
 
 -- name: GetResourceNodeByName :one
 SELECT * FROM resource_nodes WHERE name = $1;
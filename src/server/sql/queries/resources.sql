@@ -2,5 +2,13 @@
 SELECT * FROM resources
 WHERE resource_node_id = $1;
 
--- name: CreateResource :exec
-INSERT INTO resources (resource_node_id, item_id, drop_chance) VALUES ($1, $2, $3);
+-- name: UpsertResource :one
+INSERT INTO resources (resource_node_id, item_id, drop_chance) VALUES ($1, $2, $3)
+ON CONFLICT (resource_node_id, item_id) DO UPDATE SET drop_chance = EXCLUDED.drop_chance
+RETURNING *;
+
+-- name: ListResources :many
+SELECT * FROM resources ORDER BY id;
+
+-- name: DeleteResourcesExcept :exec
+DELETE FROM resources WHERE id <> ALL(@ids::INTEGER[]);
@@ -1,2 +1,17 @@
 -- name: GetToolTypeById :one
-SELECT * FROM tool_types WHERE id = $1;
\ No newline at end of file
+SELECT * FROM tool_types WHERE id = $1;
+
+-- name: GetToolTypeByName :one
+SELECT * FROM tool_types WHERE name = $1;
+
+-- name: ListToolTypes :many
+SELECT * FROM tool_types ORDER BY id;
+
+-- name: UpsertToolType :one
+INSERT INTO tool_types (name) VALUES ($1)
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: DeleteToolTypesExcept :many
+DELETE FROM tool_types WHERE id <> ALL(@ids::INTEGER[])
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,43 @@
+-- name: CreateTrade :one
+INSERT INTO trades(id, initiator_id, recipient_id, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, NOW(), NOW())
+RETURNING *;
+
+-- name: GetOpenTradeByCharacterId :one
+SELECT * FROM trades
+WHERE status = 'OPEN' AND (initiator_id = @character_id OR recipient_id = @character_id);
+
+-- name: ConfirmTrade :one
+UPDATE trades
+SET initiator_confirmed = initiator_confirmed OR initiator_id = @character_id,
+	recipient_confirmed = recipient_confirmed OR recipient_id = @character_id,
+	updated_at = NOW()
+WHERE id = @id AND status = 'OPEN'
+RETURNING *;
+
+-- name: ResetTradeConfirmations :exec
+UPDATE trades
+SET initiator_confirmed = FALSE, recipient_confirmed = FALSE, updated_at = NOW()
+WHERE id = $1;
+
+-- name: UpdateOpenTradeStatus :one
+UPDATE trades
+SET status = $2, updated_at = NOW()
+WHERE id = $1 AND status = 'OPEN'
+RETURNING *;
+
+-- name: CloseConfirmedTrade :one
+UPDATE trades
+SET status = $2, updated_at = NOW()
+WHERE id = $1 AND status = 'OPEN' AND initiator_confirmed AND recipient_confirmed
+RETURNING *;
+
+-- name: AddTradeItem :exec
+INSERT INTO trade_items(trade_id, character_id, item_id, quantity)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (trade_id, character_id, item_id)
+DO UPDATE SET quantity = trade_items.quantity + EXCLUDED.quantity;
+
+-- name: GetTradeItems :many
+SELECT * FROM trade_items
+WHERE trade_id = $1;
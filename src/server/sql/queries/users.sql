@@ -28,3 +28,19 @@ RETURNING *;
 -- name: GetSurnameById :one
 SELECT surname FROM users
 WHERE id = $1;
+
+
+-- name: MarkUserSeen :exec
+UPDATE users
+SET last_seen_at = NOW()
+WHERE id = $1;
+
+-- name: MarkUserOnline :exec
+UPDATE users
+SET last_seen_at = NULL
+WHERE id = $1;
+
+-- name: MarkOnlineUsersSeen :exec
+UPDATE users
+SET last_seen_at = NOW()
+WHERE last_seen_at IS NULL;
@@ -0,0 +1,12 @@
+-- name: GetWorldGeneration :many
+SELECT * FROM world_generation WHERE id = 1;
+
+-- name: UpsertWorldGeneration :exec
+INSERT INTO world_generation (id, seed, config, updated_at) VALUES (1, $1, $2, NOW())
+ON CONFLICT (id) DO UPDATE SET
+	seed = EXCLUDED.seed,
+	config = EXCLUDED.config,
+	updated_at = NOW();
+
+-- name: DeleteWorldGeneration :exec
+DELETE FROM world_generation;
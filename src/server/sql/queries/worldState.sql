@@ -0,0 +1,10 @@
+-- name: GetWorldState :one
+SELECT * FROM world_state WHERE id = 1;
+
+-- name: UpdateWorldState :exec
+UPDATE world_state SET last_tick = $1, last_tick_at = $2 WHERE id = 1;
+
+-- name: FenceTickShard :execrows
+INSERT INTO tick_shards (shard, epoch) VALUES ($1, $2)
+ON CONFLICT (shard) DO UPDATE SET epoch = EXCLUDED.epoch
+WHERE tick_shards.epoch <= EXCLUDED.epoch;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE characters ADD COLUMN destination_x INTEGER DEFAULT NULL;
+ALTER TABLE characters ADD COLUMN destination_y INTEGER DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE characters DROP COLUMN destination_x;
+ALTER TABLE characters DROP COLUMN destination_y;
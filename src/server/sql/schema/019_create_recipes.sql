@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE recipes(
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	quantity INTEGER NOT NULL,
+	FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+);
+
+CREATE TABLE recipe_ingredients(
+	id SERIAL PRIMARY KEY,
+	recipe_id INTEGER NOT NULL,
+	item_id INTEGER NOT NULL,
+	quantity INTEGER NOT NULL,
+	FOREIGN KEY (recipe_id) REFERENCES recipes (id) ON DELETE CASCADE,
+	FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+);
+
+ALTER TABLE characters ADD COLUMN action_recipe_id INTEGER REFERENCES recipes ON DELETE SET NULL DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE characters DROP COLUMN action_recipe_id;
+DROP TABLE recipe_ingredients;
+DROP TABLE recipes;
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE items ADD COLUMN tool_tier INTEGER NOT NULL DEFAULT 0;
+-- Tools take the tier their type had, which is kept for existing data but
+-- no longer has to be given for new types
+UPDATE items SET tool_tier = tool_types.tier
+FROM tool_types
+WHERE items.tool_type_id = tool_types.id;
+ALTER TABLE tool_types ALTER COLUMN tier SET DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE tool_types ALTER COLUMN tier DROP DEFAULT;
+ALTER TABLE items DROP COLUMN tool_tier;
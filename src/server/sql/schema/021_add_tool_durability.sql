@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE items ADD COLUMN max_durability INTEGER DEFAULT NULL;
+ALTER TABLE inventory_items ADD COLUMN durability INTEGER DEFAULT NULL;
+ALTER TABLE inventory_items DROP CONSTRAINT inventory_items_item_id_inventory_id_key;
+CREATE UNIQUE INDEX inventory_items_stack_idx ON inventory_items (inventory_id, item_id) WHERE durability IS NULL;
+
+-- +goose Down
+DELETE FROM inventory_items WHERE durability IS NOT NULL;
+DROP INDEX inventory_items_stack_idx;
+ALTER TABLE inventory_items ADD CONSTRAINT inventory_items_item_id_inventory_id_key UNIQUE (item_id, inventory_id);
+ALTER TABLE inventory_items DROP COLUMN durability;
+ALTER TABLE items DROP COLUMN max_durability;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE character_skills(
+	character_id UUID NOT NULL,
+	action_id INTEGER NOT NULL,
+	experience INTEGER NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (character_id, action_id),
+	FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+	FOREIGN KEY (action_id) REFERENCES actions (id) ON DELETE CASCADE
+);
+
+ALTER TABLE resource_nodes ADD COLUMN min_level INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE resource_nodes DROP COLUMN min_level;
+DROP TABLE character_skills;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE resource_nodes ADD COLUMN amount INTEGER DEFAULT NULL;
+ALTER TABLE resource_nodes ADD COLUMN respawn_ticks INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE resource_node_spawns ADD COLUMN remaining INTEGER DEFAULT NULL;
+ALTER TABLE resource_node_spawns ADD COLUMN respawn_ticks_left INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE resource_node_spawns DROP COLUMN respawn_ticks_left;
+ALTER TABLE resource_node_spawns DROP COLUMN remaining;
+ALTER TABLE resource_nodes DROP COLUMN respawn_ticks;
+ALTER TABLE resource_nodes DROP COLUMN amount;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE UNIQUE INDEX inventories_ground_idx ON inventories (position_x, position_y) WHERE character_id IS NULL;
+
+-- +goose Down
+DROP INDEX inventories_ground_idx;
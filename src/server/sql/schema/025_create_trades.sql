@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE trades(
+	id UUID PRIMARY KEY,
+	initiator_id UUID NOT NULL,
+	recipient_id UUID NOT NULL,
+	initiator_confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+	recipient_confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+	status TEXT NOT NULL DEFAULT 'OPEN',
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	FOREIGN KEY (initiator_id) REFERENCES characters (id) ON DELETE CASCADE,
+	FOREIGN KEY (recipient_id) REFERENCES characters (id) ON DELETE CASCADE
+);
+
+CREATE TABLE trade_items(
+	trade_id UUID NOT NULL,
+	character_id UUID NOT NULL,
+	item_id INTEGER NOT NULL,
+	quantity INTEGER NOT NULL,
+	PRIMARY KEY (trade_id, character_id, item_id),
+	FOREIGN KEY (trade_id) REFERENCES trades (id) ON DELETE CASCADE,
+	FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+	FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE trade_items;
+DROP TABLE trades;
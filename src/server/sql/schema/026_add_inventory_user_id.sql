@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE inventories ADD COLUMN user_id UUID REFERENCES users (id) ON DELETE CASCADE;
+CREATE UNIQUE INDEX inventories_stash_idx ON inventories (user_id) WHERE user_id IS NOT NULL;
+DROP INDEX inventories_ground_idx;
+CREATE UNIQUE INDEX inventories_ground_idx ON inventories (position_x, position_y) WHERE character_id IS NULL AND user_id IS NULL;
+
+-- +goose Down
+DROP INDEX inventories_ground_idx;
+CREATE UNIQUE INDEX inventories_ground_idx ON inventories (position_x, position_y) WHERE character_id IS NULL;
+DROP INDEX inventories_stash_idx;
+ALTER TABLE inventories DROP COLUMN user_id;
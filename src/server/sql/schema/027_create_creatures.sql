@@ -0,0 +1,42 @@
+-- +goose Up
+CREATE TABLE creatures(
+	id SERIAL PRIMARY KEY,
+	name TEXT UNIQUE NOT NULL,
+	tier INTEGER NOT NULL,
+	max_hp INTEGER NOT NULL,
+	damage INTEGER NOT NULL,
+	respawn_ticks INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE creature_drops(
+	id SERIAL PRIMARY KEY,
+	creature_id INTEGER NOT NULL,
+	item_id INTEGER NOT NULL,
+	drop_chance INTEGER NOT NULL,
+	quantity INTEGER NOT NULL DEFAULT 1,
+	FOREIGN KEY (creature_id) REFERENCES creatures (id) ON DELETE CASCADE,
+	FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+);
+
+CREATE TABLE creature_spawns(
+	id SERIAL PRIMARY KEY,
+	creature_id INTEGER NOT NULL,
+	position_x INTEGER NOT NULL,
+	position_y INTEGER NOT NULL,
+	hp INTEGER NOT NULL,
+	respawn_ticks_left INTEGER NOT NULL DEFAULT 0,
+	FOREIGN KEY (position_x, position_y) REFERENCES grid (position_x, position_y) ON DELETE CASCADE,
+	FOREIGN KEY (creature_id) REFERENCES creatures (id) ON DELETE CASCADE
+);
+
+ALTER TABLE characters ADD COLUMN hp INTEGER NOT NULL DEFAULT 100;
+ALTER TABLE characters ADD COLUMN max_hp INTEGER NOT NULL DEFAULT 100;
+ALTER TABLE characters ADD COLUMN action_creature_target INTEGER REFERENCES creature_spawns ON DELETE SET NULL DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE characters DROP COLUMN action_creature_target;
+ALTER TABLE characters DROP COLUMN max_hp;
+ALTER TABLE characters DROP COLUMN hp;
+DROP TABLE creature_spawns;
+DROP TABLE creature_drops;
+DROP TABLE creatures;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE resource_nodes ADD COLUMN interval_ticks INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE resource_nodes ADD COLUMN min_quantity INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE resource_nodes ADD COLUMN max_quantity INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE characters ADD COLUMN action_ticks INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE characters DROP COLUMN action_ticks;
+ALTER TABLE resource_nodes DROP COLUMN max_quantity;
+ALTER TABLE resource_nodes DROP COLUMN min_quantity;
+ALTER TABLE resource_nodes DROP COLUMN interval_ticks;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE world_state(
+	id SERIAL PRIMARY KEY,
+	last_tick BIGINT NOT NULL DEFAULT 0,
+	last_tick_at TIMESTAMPTZ
+);
+
+INSERT INTO world_state (last_tick) VALUES (0);
+
+-- +goose Down
+DROP TABLE world_state;
@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN last_seen_at TIMESTAMPTZ DEFAULT NOW();
+
+CREATE TABLE character_gains(
+	character_id UUID NOT NULL,
+	item_id INTEGER NOT NULL,
+	quantity INTEGER NOT NULL,
+	PRIMARY KEY (character_id, item_id),
+	FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE,
+	FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+);
+
+CREATE TABLE character_events(
+	id SERIAL PRIMARY KEY,
+	character_id UUID NOT NULL,
+	message TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	FOREIGN KEY (character_id) REFERENCES characters (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE character_events;
+DROP TABLE character_gains;
+ALTER TABLE users DROP COLUMN last_seen_at;
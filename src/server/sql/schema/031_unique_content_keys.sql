@@ -0,0 +1,134 @@
+-- +goose Up
+-- Earlier data loads inserted every entity again on each version bump. Keep
+-- the oldest row of each, point everything at it and drop the rest.
+CREATE TEMP TABLE item_map ON COMMIT DROP AS
+SELECT i.id AS old_id, k.id AS new_id
+FROM items AS i
+JOIN (SELECT name, MIN(id) AS id FROM items GROUP BY name) AS k ON k.name = i.name
+WHERE i.id <> k.id;
+
+INSERT INTO inventory_items (id, item_id, inventory_id, quantity, created_at, updated_at)
+SELECT gen_random_uuid(), m.new_id, ii.inventory_id, SUM(ii.quantity), MIN(ii.created_at), NOW()
+FROM inventory_items AS ii
+JOIN item_map AS m ON m.old_id = ii.item_id
+WHERE ii.durability IS NULL
+GROUP BY m.new_id, ii.inventory_id
+ON CONFLICT (inventory_id, item_id) WHERE durability IS NULL
+DO UPDATE SET quantity = inventory_items.quantity + EXCLUDED.quantity, updated_at = NOW();
+DELETE FROM inventory_items WHERE durability IS NULL AND item_id IN (SELECT old_id FROM item_map);
+UPDATE inventory_items SET item_id = m.new_id FROM item_map AS m WHERE item_id = m.old_id;
+
+INSERT INTO trade_items (trade_id, character_id, item_id, quantity)
+SELECT t.trade_id, t.character_id, m.new_id, SUM(t.quantity)
+FROM trade_items AS t
+JOIN item_map AS m ON m.old_id = t.item_id
+GROUP BY t.trade_id, t.character_id, m.new_id
+ON CONFLICT (trade_id, character_id, item_id)
+DO UPDATE SET quantity = trade_items.quantity + EXCLUDED.quantity;
+DELETE FROM trade_items WHERE item_id IN (SELECT old_id FROM item_map);
+
+INSERT INTO character_gains (character_id, item_id, quantity)
+SELECT g.character_id, m.new_id, SUM(g.quantity)
+FROM character_gains AS g
+JOIN item_map AS m ON m.old_id = g.item_id
+GROUP BY g.character_id, m.new_id
+ON CONFLICT (character_id, item_id)
+DO UPDATE SET quantity = character_gains.quantity + EXCLUDED.quantity;
+DELETE FROM character_gains WHERE item_id IN (SELECT old_id FROM item_map);
+
+UPDATE resources SET item_id = m.new_id FROM item_map AS m WHERE item_id = m.old_id;
+UPDATE recipes SET item_id = m.new_id FROM item_map AS m WHERE item_id = m.old_id;
+UPDATE recipe_ingredients SET item_id = m.new_id FROM item_map AS m WHERE item_id = m.old_id;
+UPDATE creature_drops SET item_id = m.new_id FROM item_map AS m WHERE item_id = m.old_id;
+DELETE FROM items WHERE id IN (SELECT old_id FROM item_map);
+
+CREATE TEMP TABLE action_map ON COMMIT DROP AS
+SELECT a.id AS old_id, k.id AS new_id
+FROM actions AS a
+JOIN (SELECT name, MIN(id) AS id FROM actions GROUP BY name) AS k ON k.name = a.name
+WHERE a.id <> k.id;
+
+INSERT INTO character_skills (character_id, action_id, experience, created_at, updated_at)
+SELECT s.character_id, m.new_id, MAX(s.experience), MIN(s.created_at), NOW()
+FROM character_skills AS s
+JOIN action_map AS m ON m.old_id = s.action_id
+GROUP BY s.character_id, m.new_id
+ON CONFLICT (character_id, action_id)
+DO UPDATE SET experience = GREATEST(character_skills.experience, EXCLUDED.experience), updated_at = NOW();
+DELETE FROM character_skills WHERE action_id IN (SELECT old_id FROM action_map);
+
+UPDATE characters SET action_id = m.new_id FROM action_map AS m WHERE action_id = m.old_id;
+UPDATE resource_nodes SET action_id = m.new_id FROM action_map AS m WHERE action_id = m.old_id;
+DELETE FROM actions WHERE id IN (SELECT old_id FROM action_map);
+
+CREATE TEMP TABLE recipe_map ON COMMIT DROP AS
+SELECT r.id AS old_id, k.id AS new_id
+FROM recipes AS r
+JOIN (SELECT name, MIN(id) AS id FROM recipes GROUP BY name) AS k ON k.name = r.name
+WHERE r.id <> k.id;
+
+UPDATE characters SET action_recipe_id = m.new_id FROM recipe_map AS m WHERE action_recipe_id = m.old_id;
+DELETE FROM recipes WHERE id IN (SELECT old_id FROM recipe_map);
+
+CREATE TEMP TABLE resource_node_map ON COMMIT DROP AS
+SELECT n.id AS old_id, k.id AS new_id
+FROM resource_nodes AS n
+JOIN (SELECT name, MIN(id) AS id FROM resource_nodes GROUP BY name) AS k ON k.name = n.name
+WHERE n.id <> k.id;
+
+UPDATE resource_node_spawns SET node_id = m.new_id FROM resource_node_map AS m WHERE node_id = m.old_id;
+DELETE FROM resource_nodes WHERE id IN (SELECT old_id FROM resource_node_map);
+
+CREATE TEMP TABLE resource_node_spawn_map ON COMMIT DROP AS
+SELECT s.id AS old_id, k.id AS new_id
+FROM resource_node_spawns AS s
+JOIN (
+	SELECT node_id, position_x, position_y, MIN(id) AS id
+	FROM resource_node_spawns
+	GROUP BY node_id, position_x, position_y
+) AS k ON k.node_id = s.node_id AND k.position_x = s.position_x AND k.position_y = s.position_y
+WHERE s.id <> k.id;
+
+UPDATE characters SET action_target = m.new_id FROM resource_node_spawn_map AS m WHERE action_target = m.old_id;
+DELETE FROM resource_node_spawns WHERE id IN (SELECT old_id FROM resource_node_spawn_map);
+
+CREATE TEMP TABLE creature_spawn_map ON COMMIT DROP AS
+SELECT s.id AS old_id, k.id AS new_id
+FROM creature_spawns AS s
+JOIN (
+	SELECT creature_id, position_x, position_y, MIN(id) AS id
+	FROM creature_spawns
+	GROUP BY creature_id, position_x, position_y
+) AS k ON k.creature_id = s.creature_id AND k.position_x = s.position_x AND k.position_y = s.position_y
+WHERE s.id <> k.id;
+
+UPDATE characters SET action_creature_target = m.new_id FROM creature_spawn_map AS m WHERE action_creature_target = m.old_id;
+DELETE FROM creature_spawns WHERE id IN (SELECT old_id FROM creature_spawn_map);
+
+DELETE FROM resources AS r USING resources AS k
+WHERE k.resource_node_id = r.resource_node_id AND k.item_id = r.item_id AND k.id < r.id;
+DELETE FROM recipe_ingredients AS r USING recipe_ingredients AS k
+WHERE k.recipe_id = r.recipe_id AND k.item_id = r.item_id AND k.id < r.id;
+DELETE FROM creature_drops AS d USING creature_drops AS k
+WHERE k.creature_id = d.creature_id AND k.item_id = d.item_id AND k.id < d.id;
+
+ALTER TABLE items ADD CONSTRAINT items_name_key UNIQUE (name);
+ALTER TABLE actions ADD CONSTRAINT actions_name_key UNIQUE (name);
+ALTER TABLE recipes ADD CONSTRAINT recipes_name_key UNIQUE (name);
+ALTER TABLE resource_nodes ADD CONSTRAINT resource_nodes_name_key UNIQUE (name);
+ALTER TABLE resources ADD CONSTRAINT resources_node_item_key UNIQUE (resource_node_id, item_id);
+ALTER TABLE recipe_ingredients ADD CONSTRAINT recipe_ingredients_recipe_item_key UNIQUE (recipe_id, item_id);
+ALTER TABLE creature_drops ADD CONSTRAINT creature_drops_creature_item_key UNIQUE (creature_id, item_id);
+ALTER TABLE resource_node_spawns ADD CONSTRAINT resource_node_spawns_node_position_key UNIQUE (node_id, position_x, position_y);
+ALTER TABLE creature_spawns ADD CONSTRAINT creature_spawns_creature_position_key UNIQUE (creature_id, position_x, position_y);
+
+-- +goose Down
+ALTER TABLE creature_spawns DROP CONSTRAINT creature_spawns_creature_position_key;
+ALTER TABLE resource_node_spawns DROP CONSTRAINT resource_node_spawns_node_position_key;
+ALTER TABLE creature_drops DROP CONSTRAINT creature_drops_creature_item_key;
+ALTER TABLE recipe_ingredients DROP CONSTRAINT recipe_ingredients_recipe_item_key;
+ALTER TABLE resources DROP CONSTRAINT resources_node_item_key;
+ALTER TABLE resource_nodes DROP CONSTRAINT resource_nodes_name_key;
+ALTER TABLE recipes DROP CONSTRAINT recipes_name_key;
+ALTER TABLE actions DROP CONSTRAINT actions_name_key;
+ALTER TABLE items DROP CONSTRAINT items_name_key;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE resource_nodes ADD COLUMN biome TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE world_generation(
+	id SERIAL PRIMARY KEY,
+	seed BIGINT NOT NULL,
+	config JSONB NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE world_generation;
+
+ALTER TABLE resource_nodes DROP COLUMN biome;
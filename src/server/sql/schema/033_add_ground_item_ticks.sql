@@ -0,0 +1,7 @@
+-- +goose Up
+-- Ground items expire after a number of world ticks rather than wall clock
+-- time, so downtime doesn't count against them
+ALTER TABLE inventory_items ADD COLUMN ticks_untouched INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE inventory_items DROP COLUMN ticks_untouched;
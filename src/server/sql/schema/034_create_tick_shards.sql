@@ -0,0 +1,10 @@
+-- +goose Up
+-- The lease epoch that last committed each tick shard. A commit carrying an
+-- older epoch comes from an instance that lost the shard and is refused
+CREATE TABLE tick_shards(
+	shard INTEGER PRIMARY KEY,
+	epoch BIGINT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE tick_shards;